@@ -20,7 +20,7 @@ export const useProductReviews = (slug: string) => {
 		try {
 			setLoading(true)
 			const reviewsData = await reviewService.getReviewsByProduct(slug)
-			setReviews(reviewsData.data)
+			setReviews(reviewsData.data.reviews)
 		} catch (error) {
 			setError(
 				error instanceof Error
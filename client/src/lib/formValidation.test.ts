@@ -117,9 +117,11 @@ test("validateReview", () => {
 	expect(validateReview({ rating: 4, comment: "Too short" })).toContain(
 		"Review comment must be at least 10 characters",
 	)
-	expect(validateReview({ rating: 4, comment: "a".repeat(256) })).toContain(
-		"Review comment cannot exceed 255 characters",
+	expect(validateReview({ rating: 4, comment: "a".repeat(5001) })).toContain(
+		"Review comment cannot exceed 5000 characters",
 	)
+	const longComment = "a".repeat(256)
+	expect(validateReview({ rating: 4, comment: longComment })).toHaveLength(0)
 
 	// Test multiple errors
 	const multipleErrors = validateReview({ rating: 0, comment: "" })
@@ -157,7 +157,7 @@ export const validateMessage = (message: string) => {
  * Validates review input based on the following criteria:
  * - Rating is between 1 and 5 stars
  * - Comment is at least 10 characters long
- * - Comment is no longer than 500 characters
+ * - Comment is no longer than 5000 characters
  * @param review Review input to validate
  * @returns Array of error messages if review is invalid, otherwise an empty array
  */
@@ -175,8 +175,8 @@ export const validateReview = (review: {
 		errors.push("Review comment must be at least 10 characters")
 	}
 
-	if (review.comment && review.comment.trim().length > 255) {
-		errors.push("Review comment cannot exceed 255 characters")
+	if (review.comment && review.comment.trim().length > 5000) {
+		errors.push("Review comment cannot exceed 5000 characters")
 	}
 
 	return errors
@@ -1,5 +1,6 @@
 /** @format */
 
+import { apiFetch } from "../apiFetch"
 import { OrderStatus } from "../../types/ENUMs/OrderStatus"
 
 const ADMIN_ORDERS_API_URL = "http://localhost:8080/admin/orders"
@@ -11,7 +12,7 @@ const ADMIN_ORDERS_API_URL = "http://localhost:8080/admin/orders"
  */
 export const adminOrdersService = {
 	async getAllOrders() {
-		const response = await fetch(ADMIN_ORDERS_API_URL, {
+		const response = await apiFetch(ADMIN_ORDERS_API_URL, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -28,7 +29,7 @@ export const adminOrdersService = {
 	},
 
 	async getOrderDetails(orderId: number) {
-		const response = await fetch(`${ADMIN_ORDERS_API_URL}/${orderId}`, {
+		const response = await apiFetch(`${ADMIN_ORDERS_API_URL}/${orderId}`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -45,7 +46,7 @@ export const adminOrdersService = {
 	},
 
 	async getUserOrders(userId: number) {
-		const response = await fetch(`${ADMIN_ORDERS_API_URL}/user/${userId}`, {
+		const response = await apiFetch(`${ADMIN_ORDERS_API_URL}/user/${userId}`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -62,7 +63,7 @@ export const adminOrdersService = {
 	},
 
 	async updateOrderStatus(orderId: number, newStatus: OrderStatus) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${ADMIN_ORDERS_API_URL}/${orderId}/status`,
 			{
 				method: "PUT",
@@ -1,5 +1,7 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 // Admin API to manage users
 // @module services/adminService
 // @see ../../../../server/handlers/User.go
@@ -9,7 +11,7 @@ const ADMIN_API_URL = "http://localhost:8080/admin/users"
 export const adminService = {
 	// Get all users
 	async getAllUsers() {
-		const response = await fetch(ADMIN_API_URL, {
+		const response = await apiFetch(ADMIN_API_URL, {
 			method: "GET",
 			credentials: "include",
 			headers: { "Content-Type": "application/json" },
@@ -25,7 +27,7 @@ export const adminService = {
 
 	// Update a specific user by admin
 	async updateUser(userId: number, updatedData: any) {
-		const response = await fetch(`${ADMIN_API_URL}/${userId}`, {
+		const response = await apiFetch(`${ADMIN_API_URL}/${userId}`, {
 			method: "PUT",
 			credentials: "include",
 			headers: { "Content-Type": "application/json" },
@@ -41,7 +43,7 @@ export const adminService = {
 	},
 
 	async deleteUser(userId: number) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`http://localhost:8080/admin/users/${userId}`,
 			{
 				method: "DELETE",
@@ -0,0 +1,68 @@
+/** @format */
+
+const CSRF_API_URL = "http://localhost:8080/auth/csrf"
+const CSRF_HEADER = "X-CSRF-Token"
+
+const SAFE_METHODS = ["GET", "HEAD", "OPTIONS", "TRACE"]
+
+let csrfToken: Promise<string> | null = null
+
+// fetches the session's CSRF token once and reuses it for every later request
+function getCSRFToken(): Promise<string> {
+	if (!csrfToken) {
+		csrfToken = fetch(CSRF_API_URL, { credentials: "include" })
+			.then(async (response) => {
+				const data = await response.json()
+				if (!response.ok) {
+					throw new Error(data.message || "Failed to fetch CSRF token")
+				}
+				return data.data.csrf_token as string
+			})
+			.catch((error) => {
+				csrfToken = null
+				throw error
+			})
+	}
+
+	return csrfToken
+}
+
+function isSafe(init: RequestInit) {
+	return SAFE_METHODS.includes((init.method || "GET").toUpperCase())
+}
+
+async function send(input: RequestInfo | URL, init: RequestInit) {
+	if (isSafe(init)) {
+		return fetch(input, init)
+	}
+
+	const headers = new Headers(init.headers)
+	headers.set(CSRF_HEADER, await getCSRFToken())
+
+	return fetch(input, { ...init, headers })
+}
+
+/**
+ * fetch for requests to the backend. Mutations carry the session's CSRF token in the
+ * X-CSRF-Token header, and are retried once with a new token when the session has changed, e.g.
+ * after logging out.
+ * @module services/apiFetch
+ * @see ../../../server/middleware/CSRF.go
+ */
+export async function apiFetch(
+	input: RequestInfo | URL,
+	init: RequestInit = {},
+): Promise<Response> {
+	const response = await send(input, init)
+	if (response.status !== 403 || isSafe(init)) {
+		return response
+	}
+
+	const data = await response.clone().json().catch(() => null)
+	if (data?.error?.code !== "invalid_csrf_token") {
+		return response
+	}
+
+	csrfToken = null
+	return send(input, init)
+}
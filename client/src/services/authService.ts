@@ -1,5 +1,7 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 const AUTH_API_URL = "http://localhost:8080/auth"
 
 /**
@@ -10,7 +12,7 @@ const AUTH_API_URL = "http://localhost:8080/auth"
 export const authService = {
 	// where we make a request to backend to authenticate the user (makes sure that the email and password are valid)
 	async login(email: string, password: string) {
-		const response = await fetch(`${AUTH_API_URL}/login`, {
+		const response = await apiFetch(`${AUTH_API_URL}/login`, {
 			method: "POST",
 			headers: {
 				"Content-Type": "application/json",
@@ -36,7 +38,7 @@ export const authService = {
 		email: string,
 		password: string,
 	) {
-		const response = await fetch(`${AUTH_API_URL}/register`, {
+		const response = await apiFetch(`${AUTH_API_URL}/register`, {
 			method: "POST",
 			headers: {
 				"Content-Type": "application/json",
@@ -57,7 +59,7 @@ export const authService = {
 
 	// this is where we make a request to backend to logout the user
 	async logout() {
-		const response = await fetch(`${AUTH_API_URL}/logout`, {
+		const response = await apiFetch(`${AUTH_API_URL}/logout`, {
 			method: "POST",
 			credentials: "include",
 		})
@@ -74,7 +76,7 @@ export const authService = {
 
 	// validate the user's session
 	async validateSession() {
-		const response = await fetch(`${AUTH_API_URL}/validate`, {
+		const response = await apiFetch(`${AUTH_API_URL}/validate`, {
 			method: "GET",
 			credentials: "include",
 		})
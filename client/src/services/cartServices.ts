@@ -1,5 +1,7 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 const CART_API_URL = "http://localhost:8080/cart"
 
 /**
@@ -15,7 +17,7 @@ export const cartServices = {
 	 */
 
 	async ListCartItems() {
-		const response = await fetch(`${CART_API_URL}`, {
+		const response = await apiFetch(`${CART_API_URL}`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -44,7 +46,7 @@ export const cartServices = {
 	 */
 
 	async AddCartItem(id: number, quantity: number) {
-		const response = await fetch(`${CART_API_URL}`, {
+		const response = await apiFetch(`${CART_API_URL}`, {
 			method: "POST",
 			headers: {
 				"Content-Type": "application/json",
@@ -69,7 +71,7 @@ export const cartServices = {
 	 */
 
 	async deleteCartItem(id: number) {
-		const response = await fetch(`${CART_API_URL}/${id}`, {
+		const response = await apiFetch(`${CART_API_URL}/${id}`, {
 			method: "DELETE",
 			headers: {
 				"Content-Type": "application/json",
@@ -94,7 +96,7 @@ export const cartServices = {
 	 */
 
 	async updateCartItemQuantity(id: number, quantity: number) {
-		const response = await fetch(`${CART_API_URL}/${id}`, {
+		const response = await apiFetch(`${CART_API_URL}/${id}`, {
 			method: "PUT",
 			headers: {
 				"Content-Type": "application/json",
@@ -113,7 +115,7 @@ export const cartServices = {
 
 	
 	async checkoutCart(customerDetails: any) {
-        const response = await fetch(`${CART_API_URL}/checkout`, {
+        const response = await apiFetch(`${CART_API_URL}/checkout`, {
             method: "POST",
             headers: {
                 "Content-Type": "application/json",
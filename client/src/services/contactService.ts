@@ -1,5 +1,7 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 const CONTACT_API_URL = "http://localhost:8080/contact"
 
 /**
@@ -27,7 +29,7 @@ export const contactService = {
 		message: string,
 	) {
 		// where we make a request to backend to submit the contact request
-		const response = await fetch(`${CONTACT_API_URL}`, {
+		const response = await apiFetch(`${CONTACT_API_URL}`, {
 			method: "POST",
 			headers: {
 				"Content-Type": "application/json",
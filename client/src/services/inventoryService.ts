@@ -1,12 +1,14 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 const PRODUCTS_API_URL = "http://localhost:8080/products"
 import { Product } from "../types/Product"
 
 export const inventoryService = {
 	async getProducts(): Promise<any> {
 		try {
-			const response = await fetch(PRODUCTS_API_URL, {
+			const response = await apiFetch(PRODUCTS_API_URL, {
 				method: "GET",
 				headers: { "Content-Type": "application/json" },
 				credentials: "include",
@@ -24,7 +26,7 @@ export const inventoryService = {
 
 	async createProduct(product: Product): Promise<any> {
 		try {
-			const response = await fetch(PRODUCTS_API_URL, {
+			const response = await apiFetch(PRODUCTS_API_URL, {
 				method: "POST",
 				headers: { "Content-Type": "application/json" },
 				credentials: "include",
@@ -43,7 +45,7 @@ export const inventoryService = {
 
 	async updateProduct(id: string, updatedProduct: Product): Promise<any> {
 		try {
-			const response = await fetch(`${PRODUCTS_API_URL}/${id}`, {
+			const response = await apiFetch(`${PRODUCTS_API_URL}/${id}`, {
 				method: "PUT",
 				headers: { "Content-Type": "application/json" },
 				credentials: "include",
@@ -62,7 +64,7 @@ export const inventoryService = {
 
 	async deleteProduct(id: string): Promise<boolean> {
 		try {
-			const response = await fetch(`${PRODUCTS_API_URL}/${id}`, {
+			const response = await apiFetch(`${PRODUCTS_API_URL}/${id}`, {
 				method: "DELETE",
 				credentials: "include",
 			})
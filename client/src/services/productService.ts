@@ -1,5 +1,7 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 const PRODUCTS_API_URL = "http://localhost:8080/products" // TODO: use env variable
 
 /**
@@ -16,7 +18,7 @@ export const productService = {
 	 */
 
 	async listProducts() {
-		const response = await fetch(`${PRODUCTS_API_URL}`, {
+		const response = await apiFetch(`${PRODUCTS_API_URL}`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -38,7 +40,7 @@ export const productService = {
 	 * @throws {Error} An error from the API request
 	 */
 	async getProductBySlug(slug: string) {
-		const response = await fetch(`${PRODUCTS_API_URL}/${slug}`, {
+		const response = await apiFetch(`${PRODUCTS_API_URL}/${slug}`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -62,7 +64,7 @@ export const productService = {
 	 * @throws {Error} An error from the API request
 	 */
 	async getProductsByCategory(category: string) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/category/${category}`,
 			{
 				method: "GET",
@@ -89,7 +91,7 @@ export const productService = {
 	 * @throws {Error} An error from the API request
 	 */
 	async searchProducts(query: string) {
-		const response = await fetch(`${PRODUCTS_API_URL}/search/${query}`, {
+		const response = await apiFetch(`${PRODUCTS_API_URL}/search/${query}`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -1,5 +1,7 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
+
 const PRODUCTS_API_URL = "http://localhost:8080/products"
 
 /**
@@ -10,7 +12,7 @@ const PRODUCTS_API_URL = "http://localhost:8080/products"
 export const reviewService = {
 	// gets user's review for a product
 	async getReviewByUser(slug: string) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/${slug}/reviews/user`,
 			{
 				method: "GET",
@@ -32,7 +34,7 @@ export const reviewService = {
 	},
 
 	async getReviewsByProduct(slug: string) {
-		const response = await fetch(`${PRODUCTS_API_URL}/${slug}/reviews`, {
+		const response = await apiFetch(`${PRODUCTS_API_URL}/${slug}/reviews`, {
 			method: "GET",
 			headers: {
 				"Content-Type": "application/json",
@@ -50,7 +52,7 @@ export const reviewService = {
 
 	// currenty getReviewsByUser and getReviewByUser are the same server-side
 	async getReviewsByUser(slug: string, userId: number) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/${slug}/reviews/users/${userId}/reviews`,
 			{
 				method: "GET",
@@ -70,7 +72,7 @@ export const reviewService = {
 	},
 
 	async getReview(slug: string, reviewId: number) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/${slug}/reviews/${reviewId}`,
 			{
 				method: "GET",
@@ -88,7 +90,7 @@ export const reviewService = {
 	},
 
 	async getReviewStatistics(slug: string) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/${slug}/reviews/statistics`,
 			{
 				method: "GET",
@@ -111,7 +113,7 @@ export const reviewService = {
 		slug: string,
 		review: { rating: number; comment: string },
 	) {
-		const response = await fetch(`${PRODUCTS_API_URL}/${slug}/reviews`, {
+		const response = await apiFetch(`${PRODUCTS_API_URL}/${slug}/reviews`, {
 			method: "POST",
 			headers: {
 				"Content-Type": "application/json",
@@ -132,7 +134,7 @@ export const reviewService = {
 		reviewId: number,
 		review: { rating: number; comment: string },
 	) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/${slug}/reviews/${reviewId}`,
 			{
 				method: "PUT",
@@ -152,7 +154,7 @@ export const reviewService = {
 	},
 
 	async deleteReview(slug: string, reviewId: number) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${PRODUCTS_API_URL}/${slug}/reviews/${reviewId}`,
 			{
 				method: "DELETE",
@@ -171,7 +173,7 @@ export const reviewService = {
 	},
 
 	async fetchRecentReviews(limit: number = 5) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`http://localhost:8080/reviews/recent?limit=${limit}`,
 			{
 				method: "GET",
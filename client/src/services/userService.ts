@@ -1,5 +1,6 @@
 /** @format */
 
+import { apiFetch } from "./apiFetch"
 import { UserProfile } from "../pages/User/AccountDetails"
 
 const USERS_API_URL = "http://localhost:8080/users"
@@ -13,7 +14,7 @@ const USERS_API_URL = "http://localhost:8080/users"
 export const userService = {
 	//request to backend to get the profile of logged-in user
 	async getUserProfile(userId: number) {
-		const response = await fetch(`${USERS_API_URL}/${userId}`, {
+		const response = await apiFetch(`${USERS_API_URL}/${userId}`, {
 			method: "GET",
 			credentials: "include",
 			headers: { "Content-Type": "application/json" },
@@ -29,7 +30,7 @@ export const userService = {
 
 	// request to update the profile of a specific user
 	async updateUserProfile(userId: number, updatedUser: UserProfile) {
-		const response = await fetch(`${USERS_API_URL}/${userId}`, {
+		const response = await apiFetch(`${USERS_API_URL}/${userId}`, {
 			method: "PUT",
 			credentials: "include",
 			headers: { "Content-Type": "application/json" },
@@ -55,7 +56,7 @@ export const userService = {
 			password_confirmation: string
 		},
 	) {
-		const response = await fetch(
+		const response = await apiFetch(
 			`${USERS_API_URL}/${userId}/change-password`,
 			{
 				method: "POST",
@@ -75,7 +76,7 @@ export const userService = {
 
 	// request to backend to get the orders of a specific user
 	async getUserOrders(userId: number) {
-		const response = await fetch(`${USERS_API_URL}/${userId}/orders`, {
+		const response = await apiFetch(`${USERS_API_URL}/${userId}/orders`, {
 			method: "GET",
 			credentials: "include",
 			headers: { "Content-Type": "application/json" },
@@ -17,6 +17,8 @@ export interface Product {
 		created_at?: string | null
 		updated_at?: string | null
 		rating?: number
+		average_rating?: number
+		review_count?: number
 		imageUrl?: string
 		color?: string
 		size?: string
@@ -8,14 +8,25 @@ export interface ProductReview {
 	product_id: number
 	user_id: number
 	rating: number
+	title?: string
 	comment: string
+	photos?: ReviewPhoto[]
 	created_at?: string | null
 	updated_at?: string | null
 	product: ProductReviewProduct
 	user: User
 }
 
+export interface ReviewPhoto {
+	id: number
+	review_id: number
+	filename: string
+	url: string
+}
+
 export interface ReviewStatistics {
 	average_rating: number
 	total_reviews: number
+	// number of reviews per star rating, keyed 1 to 5
+	distribution: Record<number, number>
 }
@@ -0,0 +1,162 @@
+// Package analytics holds the shapes of the sales reports on the admin dashboard and the periods
+// they are grouped by. The figures themselves are aggregated in SQL by the repositories.
+//
+// Amounts are in the base currency: orders placed in another currency are converted back at the
+// exchange rate stored on them, rounded per order or per ordered item.
+package analytics
+
+import (
+	"fmt"
+	"keylab/money"
+	"math/big"
+	"time"
+)
+
+// Interval is the length of the periods a report is grouped by.
+type Interval string
+
+const (
+	Day   Interval = "day"
+	Week  Interval = "week"
+	Month Interval = "month"
+)
+
+// ParseInterval returns the interval named s.
+func ParseInterval(s string) (Interval, bool) {
+	switch Interval(s) {
+	case Day, Week, Month:
+		return Interval(s), true
+	default:
+		return "", false
+	}
+}
+
+// Start returns the start of the period containing t. Weeks start on Monday.
+func (i Interval) Start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch i {
+	case Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+func (i Interval) next(start time.Time) time.Time {
+	switch i {
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// SQL is a MariaDB expression for the start date of the period containing column, formatted like
+// Period.
+func (i Interval) SQL(column string) string {
+	switch i {
+	case Week:
+		return fmt.Sprintf("DATE_FORMAT(DATE_SUB(DATE(%[1]s), INTERVAL WEEKDAY(%[1]s) DAY), '%%Y-%%m-%%d')", column)
+	case Month:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", column)
+	default:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", column)
+	}
+}
+
+// Period formats the start of a period as a date, e.g. 2024-03-01.
+func Period(start time.Time) string {
+	return start.Format(time.DateOnly)
+}
+
+// Point is the sales of one period.
+type Point struct {
+	Period  string      `json:"period"`
+	Orders  int64       `json:"orders"`
+	Revenue money.Money `json:"revenue"`
+}
+
+// Fill returns a point for every period between from and to, in order, taking the figures from
+// points and zero for periods without sales, so charts have no gaps.
+func Fill(interval Interval, from time.Time, to time.Time, points []Point) []Point {
+	byPeriod := map[string]Point{}
+	for _, point := range points {
+		byPeriod[point.Period] = point
+	}
+
+	filled := []Point{}
+	for start := interval.Start(from); start.Before(to); start = interval.next(start) {
+		period := Period(start)
+		point, ok := byPeriod[period]
+		if !ok {
+			point = Point{Period: period}
+		}
+		filled = append(filled, point)
+	}
+
+	return filled
+}
+
+// Totals adds up the sales of a range.
+type Totals struct {
+	Orders            int64       `json:"orders"`
+	Revenue           money.Money `json:"revenue"`
+	AverageOrderValue money.Money `json:"average_order_value"`
+}
+
+// NewTotals works out the average order value of the orders, 0 without orders.
+func NewTotals(orders int64, revenue money.Money) Totals {
+	totals := Totals{Orders: orders, Revenue: revenue}
+	if orders > 0 {
+		totals.AverageOrderValue = revenue.MulRat(big.NewRat(1, orders))
+	}
+
+	return totals
+}
+
+// Rank says what top products and categories are ranked by.
+type Rank string
+
+const (
+	ByRevenue Rank = "revenue"
+	ByUnits   Rank = "units"
+)
+
+// ParseRank returns the ranking named s.
+func ParseRank(s string) (Rank, bool) {
+	switch Rank(s) {
+	case ByRevenue, ByUnits:
+		return Rank(s), true
+	default:
+		return "", false
+	}
+}
+
+// Top is a product or category with what it sold.
+type Top struct {
+	ID      int64       `json:"id"`
+	Name    string      `json:"name"`
+	Slug    string      `json:"slug"`
+	Units   int64       `json:"units"`
+	Revenue money.Money `json:"revenue"`
+}
+
+// Customers splits the customers who ordered in a range into those ordering for the first time
+// and those who had ordered before.
+type Customers struct {
+	New       int64 `json:"new"`
+	Returning int64 `json:"returning"`
+	Total     int64 `json:"total"`
+}
+
+// StatusCount is the orders in one status.
+type StatusCount struct {
+	Status  string      `json:"status"`
+	Orders  int64       `json:"orders"`
+	Revenue money.Money `json:"revenue"`
+}
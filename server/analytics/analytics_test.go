@@ -0,0 +1,45 @@
+package analytics
+
+import (
+	"keylab/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestStart(t *testing.T) {
+	at := time.Date(2024, 3, 14, 15, 30, 0, 0, time.UTC) // a Thursday
+
+	assert.Equal(t, date("2024-03-14"), Day.Start(at))
+	assert.Equal(t, date("2024-03-11"), Week.Start(at))
+	assert.Equal(t, date("2024-03-01"), Month.Start(at))
+	assert.Equal(t, date("2024-03-11"), Week.Start(date("2024-03-17")), "Sunday ends the week")
+}
+
+func TestFill(t *testing.T) {
+	points := []Point{
+		{Period: "2024-03-11", Orders: 2, Revenue: money.MustParse("40.00")},
+		{Period: "2024-03-25", Orders: 1, Revenue: money.MustParse("9.99")},
+	}
+
+	filled := Fill(Week, date("2024-03-13"), date("2024-04-01"), points)
+	assert.Equal(t, []Point{
+		points[0],
+		{Period: "2024-03-18"},
+		points[1],
+	}, filled)
+
+	assert.Len(t, Fill(Day, date("2024-03-01"), date("2024-03-08"), nil), 7)
+	assert.Len(t, Fill(Month, date("2024-01-15"), date("2024-03-02"), nil), 3)
+}
+
+func TestNewTotals(t *testing.T) {
+	assert.Equal(t, "33.33", NewTotals(3, money.MustParse("100.00")).AverageOrderValue.String())
+	assert.True(t, NewTotals(0, money.Money{}).AverageOrderValue.IsZero())
+}
@@ -0,0 +1,96 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"keylab/database/models"
+	"keylab/i18n"
+	"keylab/logging"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationUsesJSONFieldNames(t *testing.T) {
+	user := models.User{Forename: "J", Email: "not-an-email", Password: "password123"}
+	appErr := Validation(user.Validate("Forename", "Email", "Surname"))
+
+	assert.Equal(t, http.StatusBadRequest, appErr.Status)
+	assert.Equal(t, CodeValidationFailed, appErr.Code)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "forename", Tag: "min", Param: "2", Message: "forename must be at least 2 characters long", isString: true},
+		{Field: "email", Tag: "email", Message: "email must be a valid email address", isString: true},
+		{Field: "surname", Tag: "required", Message: "surname is required", isString: true},
+	}, appErr.Fields)
+}
+
+func TestRenderTranslatesMessages(t *testing.T) {
+	user := models.User{Forename: "J", Email: "jane@example.com", Surname: "Doe", Password: "password123"}
+	appErr := Validation(user.Validate("Forename", "Email", "Surname"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(i18n.WithLocale(req.Context(), "nl"))
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, Render(e.NewContext(req, rec), appErr))
+
+	var response struct {
+		Message string `json:"message"`
+		Error   struct {
+			Fields []FieldError `json:"fields"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	assert.Equal(t, "Validatie mislukt", response.Message)
+	assert.Equal(t, []FieldError{
+		{Field: "forename", Tag: "min", Param: "2", Message: "forename moet minimaal 2 tekens lang zijn"},
+	}, response.Error.Fields)
+	assert.Equal(t, "forename must be at least 2 characters long", appErr.Fields[0].Message)
+}
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{name: "App Error", err: NotFound("Product not found"), wantStatus: http.StatusNotFound, wantCode: CodeNotFound, wantMessage: "Product not found"},
+		{name: "Wrapped App Error", err: errors.Join(Conflict("Slug taken")), wantStatus: http.StatusConflict, wantCode: CodeConflict, wantMessage: "Slug taken"},
+		{name: "Echo Error", err: echo.ErrMethodNotAllowed, wantStatus: http.StatusMethodNotAllowed, wantCode: CodeMethodNotAllowed, wantMessage: "Method Not Allowed"},
+		{name: "Unknown Error", err: errors.New("dial tcp: connection refused"), wantStatus: http.StatusInternalServerError, wantCode: CodeInternal, wantMessage: "Internal server error"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(logging.WithRequestID(req.Context(), "req-123"))
+			rec := httptest.NewRecorder()
+
+			ErrorHandler(test.err, e.NewContext(req, rec))
+
+			var response struct {
+				Message string `json:"message"`
+				Error   struct {
+					Code      string `json:"code"`
+					Status    int    `json:"status"`
+					RequestID string `json:"request_id"`
+				} `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+			assert.Equal(t, test.wantStatus, rec.Code)
+			assert.Equal(t, test.wantStatus, response.Error.Status)
+			assert.Equal(t, test.wantCode, response.Error.Code)
+			assert.Equal(t, test.wantMessage, response.Message)
+			assert.Equal(t, "req-123", response.Error.RequestID)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"keylab/i18n"
+	"keylab/logging"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type body struct {
+	Message string `json:"message"`
+	Error   detail `json:"error"`
+}
+
+type detail struct {
+	Code      string       `json:"code"`
+	Status    int          `json:"status"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// Render writes err to the client in the error envelope, with its messages translated into the
+// request's locale.
+func Render(c echo.Context, err *Error) error {
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(err.Status)
+	}
+
+	locale := i18n.FromContext(c.Request().Context())
+
+	var fields []FieldError
+	for _, field := range err.Fields {
+		field.Message = FieldMessage(locale, field)
+		fields = append(fields, field)
+	}
+
+	requestID := logging.RequestIDFromContext(c.Request().Context())
+	if requestID == "" {
+		requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+
+	return c.JSON(err.Status, body{
+		Message: i18n.T(locale, err.Message),
+		Error: detail{
+			Code:      err.Code,
+			Status:    err.Status,
+			Fields:    fields,
+			RequestID: requestID,
+		},
+	})
+}
+
+// ErrorHandler is the Echo HTTPErrorHandler. It renders *Error values as they are, maps Echo's own
+// errors (unknown routes, bad bodies) to the matching code and hides every other error behind a
+// generic internal error. Server errors are logged with their cause.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	appErr := fromError(err)
+	if appErr.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request().Context(), "Request failed", "code", appErr.Code, "error", err)
+	}
+
+	if renderErr := Render(c, appErr); renderErr != nil {
+		slog.ErrorContext(c.Request().Context(), "Error writing error response", "error", renderErr)
+	}
+}
+
+func fromError(err error) *Error {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message := http.StatusText(httpErr.Code)
+		if text, ok := httpErr.Message.(string); ok && text != "" {
+			message = text
+		} else if httpErr.Message != nil {
+			message = fmt.Sprint(httpErr.Message)
+		}
+
+		return FromStatus(httpErr.Code, message).WithCause(httpErr.Internal)
+	}
+
+	return As(err)
+}
@@ -0,0 +1,64 @@
+package apperr
+
+import (
+	"errors"
+	"keylab/i18n"
+	"net/http"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validation turns the errors from a model's Validate method into a 400 response listing every
+// invalid field. Other errors become a plain bad request.
+func Validation(err error) *Error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return BadRequest(err.Error())
+	}
+
+	appErr := New(http.StatusBadRequest, CodeValidationFailed, "Validation failed")
+	for _, validationErr := range validationErrors {
+		fieldErr := FieldError{
+			Field:    validationErr.Field(),
+			Tag:      validationErr.Tag(),
+			Param:    validationErr.Param(),
+			isString: validationErr.Kind() == reflect.String,
+		}
+		fieldErr.Message = FieldMessage(i18n.Default, fieldErr)
+
+		appErr.Fields = append(appErr.Fields, fieldErr)
+	}
+
+	return appErr
+}
+
+// FieldMessage describes a failed validator rule in plain language, translated into locale.
+func FieldMessage(locale string, fieldErr FieldError) string {
+	args := map[string]string{"field": fieldErr.Field, "param": fieldErr.Param}
+
+	switch fieldErr.Tag {
+	case "required":
+		return i18n.Format(locale, "{field} is required", args)
+	case "email":
+		return i18n.Format(locale, "{field} must be a valid email address", args)
+	case "e164":
+		return i18n.Format(locale, "{field} must be a phone number in international format, e.g. +441234567890", args)
+	case "numeric":
+		return i18n.Format(locale, "{field} must be a number", args)
+	case "min", "gte":
+		if fieldErr.isString {
+			return i18n.Format(locale, "{field} must be at least {param} characters long", args)
+		}
+		return i18n.Format(locale, "{field} must be at least {param}", args)
+	case "max", "lte":
+		if fieldErr.isString {
+			return i18n.Format(locale, "{field} must be at most {param} characters long", args)
+		}
+		return i18n.Format(locale, "{field} must be at most {param}", args)
+	case "oneof":
+		return i18n.Format(locale, "{field} must be one of: {param}", args)
+	default:
+		return i18n.Format(locale, "{field} is invalid", args)
+	}
+}
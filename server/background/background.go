@@ -0,0 +1,98 @@
+package background
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Group runs background workers and lets shutdown wait for them. Workers receive a context that is
+// only cancelled once the shutdown timeout has expired, so they get the whole grace period to finish.
+type Group struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopping atomic.Bool
+	stopped  chan struct{}
+}
+
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{ctx: ctx, cancel: cancel, stopped: make(chan struct{})}
+}
+
+// Go starts fn in its own goroutine. Panics are recovered and logged. Once shutdown has begun no
+// new workers are started and Go returns false.
+func (g *Group) Go(name string, fn func(ctx context.Context)) bool {
+	if g.stopping.Load() {
+		slog.Warn("Background worker not started, shutting down", "worker", name)
+		return false
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.run(name, fn)
+	}()
+
+	return true
+}
+
+// Every starts a worker that runs fn straight away and then every interval until shutdown begins.
+// A run that panics is logged and the next one still happens.
+func (g *Group) Every(name string, interval time.Duration, fn func(ctx context.Context)) bool {
+	return g.Go(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			g.run(name, fn)
+
+			select {
+			case <-ticker.C:
+			case <-g.stopped:
+				return
+			}
+		}
+	})
+}
+
+func (g *Group) run(name string, fn func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Background worker panicked", "worker", name, "panic", r)
+		}
+	}()
+
+	fn(g.ctx)
+}
+
+// Stopping reports whether shutdown has begun.
+func (g *Group) Stopping() bool {
+	return g.stopping.Load()
+}
+
+// Shutdown stops new workers from starting and waits for running ones to return. If ctx expires
+// first, the workers' context is cancelled and ctx.Err() is returned.
+func (g *Group) Shutdown(ctx context.Context) error {
+	if g.stopping.CompareAndSwap(false, true) {
+		close(g.stopped)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		return ctx.Err()
+	}
+}
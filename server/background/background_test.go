@@ -0,0 +1,77 @@
+package background
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownWaitsForWorkers(t *testing.T) {
+	group := NewGroup()
+	finished := make(chan struct{})
+
+	group.Go("slow", func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		close(finished)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, group.Shutdown(ctx))
+	assert.True(t, group.Stopping())
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Shutdown returned before the worker finished")
+	}
+
+	assert.False(t, group.Go("late", func(ctx context.Context) {}))
+}
+
+func TestShutdownTimeoutCancelsWorkers(t *testing.T) {
+	group := NewGroup()
+	cancelled := make(chan struct{})
+
+	group.Go("stuck", func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, group.Shutdown(ctx), context.DeadlineExceeded)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Worker context was not cancelled after the shutdown timeout")
+	}
+}
+
+func TestEveryRepeatsUntilShutdown(t *testing.T) {
+	group := NewGroup()
+	var runs atomic.Int32
+
+	group.Every("ticking", 5*time.Millisecond, func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			panic("first run fails")
+		}
+	})
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond, "runs again after a panic")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, group.Shutdown(ctx), "the worker stops when shutdown begins")
+
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
@@ -1,45 +1,321 @@
 package config
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"keylab/helpers"
-	"log"
-	"path/filepath"
+	"keylab/logging"
+	"keylab/money"
+	"keylab/tax"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/caarlos0/env/v11"
-	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is built from, in increasing order of precedence: the envDefault tags, an optional YAML or
+// TOML config file, an optional .env file, environment variables and command line flags. In files
+// settings use lower case keys (server_url), as flags they are kebab case (--server-url).
 type Config struct {
-	CLIENT_URL       string `env:"CLIENT_URL,required"`
-	SERVER_URL       string `env:"SERVER_URL,required"`
-	SESSIONS_KEY     string `env:"SESSIONS_KEY,required"`
-	HASH_KEY         string `env:"HASH_KEY,required"`
-	MARIADB_USER     string `env:"MARIADB_USER,required"`
-	MARIADB_PASSWORD string `env:"MARIADB_PASSWORD,required"`
-	MARIADB_HOST     string `env:"MARIADB_HOST,required"`
-	MARIADB_PORT     string `env:"MARIADB_PORT,required"`
-	MARIADB_DATABASE string `env:"MARIADB_DATABASE,required"`
+	CLIENT_URL       string `env:"CLIENT_URL"`
+	SERVER_URL       string `env:"SERVER_URL"`
+	SESSIONS_KEY     string `env:"SESSIONS_KEY"`
+	HASH_KEY         string `env:"HASH_KEY"`
+	MARIADB_USER     string `env:"MARIADB_USER"`
+	MARIADB_PASSWORD string `env:"MARIADB_PASSWORD"`
+	MARIADB_HOST     string `env:"MARIADB_HOST"`
+	MARIADB_PORT     string `env:"MARIADB_PORT" envDefault:"3306"`
+	MARIADB_DATABASE string `env:"MARIADB_DATABASE"`
+
+	LOG_LEVEL               string        `env:"LOG_LEVEL" envDefault:"info"`
+	LOG_FORMAT              string        `env:"LOG_FORMAT" envDefault:"json"`
+	DB_SLOW_QUERY_THRESHOLD time.Duration `env:"DB_SLOW_QUERY_THRESHOLD" envDefault:"200ms"`
+	SHUTDOWN_TIMEOUT        time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"15s"`
+	MAX_UPLOAD_SIZE         ByteSize      `env:"MAX_UPLOAD_SIZE" envDefault:"10MB"`
+
+	METRICS_ADDR  string `env:"METRICS_ADDR"`
+	METRICS_TOKEN string `env:"METRICS_TOKEN"`
+
+	BASE_CURRENCY money.Currency `env:"BASE_CURRENCY" envDefault:"GBP"`
+	TAX_MODE      tax.Mode       `env:"TAX_MODE" envDefault:"inclusive"`
+
+	STORE_NAME    string `env:"STORE_NAME" envDefault:"Keylab"`
+	STORE_ADDRESS string `env:"STORE_ADDRESS"`
+
+	LOW_STOCK_THRESHOLD   int    `env:"LOW_STOCK_THRESHOLD" envDefault:"5"`
+	LOW_STOCK_WEBHOOK_URL string `env:"LOW_STOCK_WEBHOOK_URL"`
+	LOW_STOCK_EMAIL       string `env:"LOW_STOCK_EMAIL"`
+	SMTP_ADDR             string `env:"SMTP_ADDR"`
+	SMTP_USERNAME         string `env:"SMTP_USERNAME"`
+	SMTP_PASSWORD         string `env:"SMTP_PASSWORD"`
+	SMTP_FROM             string `env:"SMTP_FROM"`
+
+	REVIEW_PREMODERATION   bool   `env:"REVIEW_PREMODERATION" envDefault:"false"`
+	REVIEW_PURCHASE_POLICY string `env:"REVIEW_PURCHASE_POLICY" envDefault:"flag"`
+
+	RECOMMENDATIONS_INTERVAL time.Duration `env:"RECOMMENDATIONS_INTERVAL" envDefault:"1h"`
+	RECOMMENDATIONS_WINDOW   time.Duration `env:"RECOMMENDATIONS_WINDOW" envDefault:"8760h"`
 }
 
-var (
-	cfg  *Config
-	once sync.Once
+const minKeyLength = 32
+
+// REVIEW_PURCHASE_POLICY values: reviews by users who have not had the product delivered are
+// accepted without the verified purchase flag, or rejected.
+const (
+	ReviewsFlagUnverified  = "flag"
+	ReviewsRequirePurchase = "require"
 )
 
-func Initialize() *Config {
-	once.Do(func() {
-		envPath := filepath.Join(helpers.GetProjectRoot(), ".env")
+// LogValue keeps keys and passwords out of the logs when the configuration is logged.
+func (c Config) LogValue() slog.Value {
+	return logging.RedactStruct(c)
+}
+
+func (c Config) String() string {
+	return c.LogValue().String()
+}
+
+// Validate checks every setting and returns all problems at once.
+func (c Config) Validate() error {
+	var errs []error
+
+	required := map[string]string{
+		"CLIENT_URL":       c.CLIENT_URL,
+		"SERVER_URL":       c.SERVER_URL,
+		"SESSIONS_KEY":     c.SESSIONS_KEY,
+		"HASH_KEY":         c.HASH_KEY,
+		"MARIADB_USER":     c.MARIADB_USER,
+		"MARIADB_PASSWORD": c.MARIADB_PASSWORD,
+		"MARIADB_HOST":     c.MARIADB_HOST,
+		"MARIADB_DATABASE": c.MARIADB_DATABASE,
+	}
+	for _, name := range settingNames() {
+		if value, ok := required[name]; ok && value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", name))
+		}
+	}
+
+	if c.CLIENT_URL != "" {
+		if err := validateURL(c.CLIENT_URL); err != nil {
+			errs = append(errs, fmt.Errorf("CLIENT_URL: %w", err))
+		}
+	}
+	if c.SERVER_URL != "" {
+		if err := validateURL(c.SERVER_URL); err != nil {
+			errs = append(errs, fmt.Errorf("SERVER_URL: %w", err))
+		} else if _, err := helpers.ListenAddress(c.SERVER_URL); err != nil {
+			errs = append(errs, fmt.Errorf("SERVER_URL: %w", err))
+		}
+	}
+
+	if c.SESSIONS_KEY != "" && len(c.SESSIONS_KEY) < minKeyLength {
+		errs = append(errs, fmt.Errorf("SESSIONS_KEY must be at least %d characters", minKeyLength))
+	}
+	if c.HASH_KEY != "" && len(c.HASH_KEY) < minKeyLength {
+		errs = append(errs, fmt.Errorf("HASH_KEY must be at least %d characters", minKeyLength))
+	}
+
+	if port, err := strconv.Atoi(c.MARIADB_PORT); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("MARIADB_PORT must be a port number, got %q", c.MARIADB_PORT))
+	}
 
-		if err := godotenv.Load(envPath); err != nil {
-			log.Fatal("No .env file found!")
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LOG_LEVEL)); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", c.LOG_LEVEL))
+	}
+	if c.LOG_FORMAT != "json" && c.LOG_FORMAT != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT must be json or text, got %q", c.LOG_FORMAT))
+	}
+
+	if c.DB_SLOW_QUERY_THRESHOLD < 0 {
+		errs = append(errs, errors.New("DB_SLOW_QUERY_THRESHOLD cannot be negative"))
+	}
+	if c.SHUTDOWN_TIMEOUT <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.MAX_UPLOAD_SIZE <= 0 {
+		errs = append(errs, errors.New("MAX_UPLOAD_SIZE must be positive"))
+	}
+
+	if currency, err := money.ParseCurrency(string(c.BASE_CURRENCY)); err != nil || currency != c.BASE_CURRENCY {
+		errs = append(errs, fmt.Errorf("BASE_CURRENCY must be one of %v, got %q", money.Currencies(), c.BASE_CURRENCY))
+	}
+	if _, ok := tax.ParseMode(string(c.TAX_MODE)); !ok {
+		errs = append(errs, fmt.Errorf("TAX_MODE must be inclusive or exclusive, got %q", c.TAX_MODE))
+	}
+	if strings.TrimSpace(c.STORE_NAME) == "" {
+		errs = append(errs, errors.New("STORE_NAME cannot be empty"))
+	}
+
+	if c.LOW_STOCK_THRESHOLD < 0 {
+		errs = append(errs, errors.New("LOW_STOCK_THRESHOLD cannot be negative"))
+	}
+	if c.LOW_STOCK_WEBHOOK_URL != "" {
+		if err := validateURL(c.LOW_STOCK_WEBHOOK_URL); err != nil {
+			errs = append(errs, fmt.Errorf("LOW_STOCK_WEBHOOK_URL: %w", err))
+		}
+	}
+	for _, recipient := range c.LowStockRecipients() {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			errs = append(errs, fmt.Errorf("LOW_STOCK_EMAIL: %q is not an email address", recipient))
 		}
+	}
+	if len(c.LowStockRecipients()) > 0 {
+		if _, _, err := net.SplitHostPort(c.SMTP_ADDR); err != nil {
+			errs = append(errs, fmt.Errorf("SMTP_ADDR must be host:port to send LOW_STOCK_EMAIL, got %q", c.SMTP_ADDR))
+		}
+		if _, err := mail.ParseAddress(c.SMTP_FROM); err != nil {
+			errs = append(errs, fmt.Errorf("SMTP_FROM must be an email address to send LOW_STOCK_EMAIL, got %q", c.SMTP_FROM))
+		}
+	}
+
+	if c.REVIEW_PURCHASE_POLICY != ReviewsFlagUnverified && c.REVIEW_PURCHASE_POLICY != ReviewsRequirePurchase {
+		errs = append(errs, fmt.Errorf("REVIEW_PURCHASE_POLICY must be flag or require, got %q", c.REVIEW_PURCHASE_POLICY))
+	}
+
+	if c.RECOMMENDATIONS_INTERVAL <= 0 {
+		errs = append(errs, errors.New("RECOMMENDATIONS_INTERVAL must be positive"))
+	}
 
-		cfg = &Config{}
-		if err := env.Parse(cfg); err != nil {
-			log.Fatalf("Failed to load configuration: %v", err)
+	if c.RECOMMENDATIONS_WINDOW <= 0 {
+		errs = append(errs, errors.New("RECOMMENDATIONS_WINDOW must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// LowStockRecipients returns the comma separated addresses of LOW_STOCK_EMAIL.
+func (c Config) LowStockRecipients() []string {
+	var recipients []string
+	for _, recipient := range strings.Split(c.LOW_STOCK_EMAIL, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
 		}
-	})
+	}
+
+	return recipients
+}
+
+func validateURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%q must start with http:// or https://", value)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%q has no host", value)
+	}
+
+	return nil
+}
+
+// WriteRedacted writes the configuration as a YAML config file with secrets replaced by
+// [REDACTED].
+func (c Config) WriteRedacted(w io.Writer) error {
+	document := &yaml.Node{Kind: yaml.MappingNode}
+
+	v := reflect.ValueOf(c)
+	for i, name := range settingNames() {
+		value := fmt.Sprint(v.Field(i).Interface())
+		if logging.IsSecret(name) && value != "" {
+			value = logging.Redacted
+		}
+
+		document.Content = append(document.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: fileKey(name)},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+		)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	defer encoder.Close()
+
+	return encoder.Encode(document)
+}
+
+// Load builds the configuration from all layers without validating it. args are command line
+// arguments without the program name.
+func Load(args []string) (*Config, error) {
+	return LoadFlags(flag.NewFlagSet("keylab", flag.ContinueOnError), args)
+}
+
+// LoadFlags is Load for commands with flags of their own: the configuration flags are added to fs,
+// which then parses args. Flags may follow positional arguments, which are left in fs.Args().
+func LoadFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	sources, err := newSources(fs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := sources.values()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &Config{}
+	if err := env.ParseWithOptions(loaded, env.Options{Environment: values}); err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	return loaded, nil
+}
+
+var (
+	cfg *Config
+	mu  sync.Mutex
+)
+
+// Initialize loads and validates the configuration and makes it available through Get.
+func Initialize(args []string) (*Config, error) {
+	return InitializeFlags(flag.NewFlagSet("keylab", flag.ContinueOnError), args)
+}
+
+// InitializeFlags is Initialize for commands with flags of their own, see LoadFlags.
+func InitializeFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	loaded, err := LoadFlags(fs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loaded.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	mu.Lock()
+	cfg = loaded
+	mu.Unlock()
+
+	return loaded, nil
+}
+
+// Get returns the configuration loaded by Initialize. If Initialize has not been called it loads
+// the configuration without flags and panics when that fails, so commands should call Initialize
+// first to report errors properly.
+func Get() *Config {
+	mu.Lock()
+	loaded := cfg
+	mu.Unlock()
+
+	if loaded != nil {
+		return loaded
+	}
+
+	loaded, err := Initialize(nil)
+	if err != nil {
+		panic(err)
+	}
 
-	return cfg
+	return loaded
 }
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"flag"
+	"keylab/money"
+	"keylab/tax"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+
+	return path
+}
+
+func validConfig() Config {
+	return Config{
+		CLIENT_URL:       "http://localhost:5173",
+		SERVER_URL:       "http://localhost:8080",
+		SESSIONS_KEY:     testKey,
+		HASH_KEY:         testKey,
+		MARIADB_USER:     "keylab",
+		MARIADB_PASSWORD: "password",
+		MARIADB_HOST:     "localhost",
+		MARIADB_PORT:     "3306",
+		MARIADB_DATABASE: "keylab",
+		LOG_LEVEL:        "info",
+		LOG_FORMAT:       "json",
+		SHUTDOWN_TIMEOUT: time.Second,
+		MAX_UPLOAD_SIZE:  Megabyte,
+		BASE_CURRENCY:    money.GBP,
+		TAX_MODE:         tax.Inclusive,
+		STORE_NAME:       "Keylab",
+
+		REVIEW_PURCHASE_POLICY:   ReviewsFlagUnverified,
+		RECOMMENDATIONS_INTERVAL: time.Hour,
+		RECOMMENDATIONS_WINDOW:   365 * 24 * time.Hour,
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	file := writeFile(t, "keylab.yaml", "server_url: http://file:8080\nclient_url: http://file:5173\nshutdown_timeout: 30s\nmax_upload_size: 2MB\n")
+	t.Setenv("CLIENT_URL", "http://env:5173")
+	t.Setenv("LOG_FORMAT", "text")
+
+	loaded, err := Load([]string{"-config", file, "-env-file", "", "--log-format", "json"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://file:8080", loaded.SERVER_URL, "config file overrides defaults")
+	assert.Equal(t, "http://env:5173", loaded.CLIENT_URL, "environment overrides config file")
+	assert.Equal(t, "json", loaded.LOG_FORMAT, "flags override environment")
+	assert.Equal(t, 30*time.Second, loaded.SHUTDOWN_TIMEOUT)
+	assert.Equal(t, 2*Megabyte, loaded.MAX_UPLOAD_SIZE)
+	assert.Equal(t, "3306", loaded.MARIADB_PORT, "defaults apply when nothing is set")
+}
+
+func TestLoadTOMLAndEnvFile(t *testing.T) {
+	file := writeFile(t, "keylab.toml", "mariadb_port = 3307\nmariadb_host = \"file\"\n")
+	envFile := writeFile(t, "test.env", "MARIADB_HOST=dotenv\n")
+
+	loaded, err := Load([]string{"-config", file, "-env-file", envFile})
+	assert.NoError(t, err)
+	assert.Equal(t, "3307", loaded.MARIADB_PORT)
+	assert.Equal(t, "dotenv", loaded.MARIADB_HOST, ".env overrides config file")
+}
+
+func TestDefaultEnvFile(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer os.Chdir(wd)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+
+	assert.Equal(t, ".env", defaultEnvFile(), "the working directory when there is no .env anywhere")
+
+	executable, err := os.Executable()
+	assert.NoError(t, err)
+	nextToExecutable := filepath.Join(filepath.Dir(executable), ".env")
+	assert.NoError(t, os.WriteFile(nextToExecutable, nil, 0o600))
+	defer os.Remove(nextToExecutable)
+	assert.Equal(t, nextToExecutable, defaultEnvFile())
+
+	assert.NoError(t, os.WriteFile(".env", nil, 0o600))
+	assert.Equal(t, ".env", defaultEnvFile(), "the working directory comes first")
+}
+
+func TestLoadErrors(t *testing.T) {
+	unknown := writeFile(t, "keylab.yaml", "sever_url: http://typo:8080\n")
+	_, err := Load([]string{"-config", unknown, "-env-file", ""})
+	assert.ErrorContains(t, err, "unknown setting")
+
+	_, err = Load([]string{"-env-file", filepath.Join(t.TempDir(), "missing.env")})
+	assert.Error(t, err, "an explicitly requested env file must exist")
+
+	_, err = Load([]string{"-env-file", "", "--max-upload-size", "ten"})
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+
+	config := validConfig()
+	config.SERVER_URL = "localhost:8080"
+	config.SESSIONS_KEY = "short"
+	config.MARIADB_PORT = "abc"
+	config.LOG_FORMAT = "xml"
+	config.MARIADB_USER = ""
+	config.BASE_CURRENCY = "gbp"
+	config.TAX_MODE = "net"
+	config.STORE_NAME = " "
+	config.LOW_STOCK_THRESHOLD = -1
+	config.LOW_STOCK_EMAIL = "staff@example.com, not an address"
+	config.REVIEW_PURCHASE_POLICY = "verified"
+	config.RECOMMENDATIONS_INTERVAL = 0
+	config.RECOMMENDATIONS_WINDOW = 0
+
+	err := config.Validate()
+	for _, expected := range []string{"SERVER_URL", "SESSIONS_KEY", "MARIADB_PORT", "LOG_FORMAT", "MARIADB_USER is required", "BASE_CURRENCY", "TAX_MODE", "STORE_NAME", "LOW_STOCK_THRESHOLD", `"not an address" is not an email address`, "SMTP_ADDR", "SMTP_FROM", "REVIEW_PURCHASE_POLICY", "RECOMMENDATIONS_INTERVAL", "RECOMMENDATIONS_WINDOW"} {
+		assert.ErrorContains(t, err, expected)
+	}
+}
+
+func TestWriteRedacted(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, validConfig().WriteRedacted(&out))
+
+	assert.Contains(t, out.String(), "server_url: http://localhost:8080")
+	assert.Contains(t, out.String(), "max_upload_size: 1MB")
+	assert.Contains(t, out.String(), "sessions_key: '[REDACTED]'")
+	assert.NotContains(t, out.String(), testKey)
+	assert.NotContains(t, out.String(), "password\n")
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ByteSize
+		isError  bool
+	}{
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"4KB", 4 * Kilobyte, false},
+		{"1.5 MiB", Megabyte + Megabyte/2, false},
+		{"2gb", 2 * Gigabyte, false},
+		{"10XB", 0, true},
+		{"-1MB", 0, true},
+	}
+
+	for _, test := range tests {
+		var size ByteSize
+		err := size.UnmarshalText([]byte(test.input))
+		if test.isError {
+			assert.Error(t, err, test.input)
+		} else {
+			assert.NoError(t, err, test.input)
+			assert.Equal(t, test.expected, size, test.input)
+		}
+	}
+}
+
+func TestLoadFlagsKeepsPositionalArguments(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", false, "")
+
+	loaded, err := LoadFlags(fs, []string{"goto", "--env-file", "", "12", "--verbose", "--log-level", "debug"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"goto", "12"}, fs.Args())
+	assert.True(t, *verbose)
+	assert.Equal(t, "debug", loaded.LOG_LEVEL)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes that parses from values such as "512KB", "10MB" or "1GiB". Units are
+// binary, so KB and KiB both mean 1024 bytes. A plain number is a count of bytes.
+type ByteSize int64
+
+const (
+	Byte     ByteSize = 1
+	Kilobyte          = 1024 * Byte
+	Megabyte          = 1024 * Kilobyte
+	Gigabyte          = 1024 * Megabyte
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"B":   Byte,
+	"KB":  Kilobyte,
+	"KIB": Kilobyte,
+	"MB":  Megabyte,
+	"MIB": Megabyte,
+	"GB":  Gigabyte,
+	"GIB": Gigabyte,
+}
+
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := value, ""
+	if split >= 0 {
+		number, unit = value[:split], strings.ToUpper(strings.TrimSpace(value[split:]))
+	}
+
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return fmt.Errorf("invalid size %q: unknown unit %q", value, unit)
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || parsed < 0 {
+		return fmt.Errorf("invalid size %q", value)
+	}
+
+	*s = ByteSize(parsed * float64(multiplier))
+	return nil
+}
+
+func (s ByteSize) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// String uses the largest unit that represents the size exactly.
+func (s ByteSize) String() string {
+	switch {
+	case s != 0 && s%Gigabyte == 0:
+		return fmt.Sprintf("%dGB", s/Gigabyte)
+	case s != 0 && s%Megabyte == 0:
+		return fmt.Sprintf("%dMB", s/Megabyte)
+	case s != 0 && s%Kilobyte == 0:
+		return fmt.Sprintf("%dKB", s/Kilobyte)
+	default:
+		return fmt.Sprintf("%dB", int64(s))
+	}
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// settingNames lists the environment variable name of every Config field, in declaration order.
+func settingNames() []string {
+	t := reflect.TypeOf(Config{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("env"), ",")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// flagName turns SERVER_URL into server-url.
+func flagName(setting string) string {
+	return strings.ReplaceAll(strings.ToLower(setting), "_", "-")
+}
+
+// fileKey turns SERVER_URL into server_url, the key used in config files.
+func fileKey(setting string) string {
+	return strings.ToLower(setting)
+}
+
+// normalizeKey accepts server_url, server-url and SERVER_URL alike.
+func normalizeKey(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// readConfigFile reads a flat YAML or TOML file into setting values keyed by environment variable
+// name. Unknown keys are rejected so typos do not go unnoticed.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+
+	known := map[string]bool{}
+	for _, name := range settingNames() {
+		known[name] = true
+	}
+
+	values := map[string]string{}
+	for key, value := range raw {
+		name := normalizeKey(key)
+		if !known[name] {
+			return nil, fmt.Errorf("config file %s: unknown setting %q", path, key)
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("config file %s: setting %q must be a single value", path, key)
+		case nil:
+			continue
+		}
+
+		values[name] = fmt.Sprint(value)
+	}
+
+	return values, nil
+}
+
+// defaultEnvFile is the .env file in the working directory, or else the one next to the
+// executable, so an installed binary finds its settings wherever it is started from.
+func defaultEnvFile() string {
+	if _, err := os.Stat(".env"); err == nil {
+		return ".env"
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		nextToExecutable := filepath.Join(filepath.Dir(executable), ".env")
+		if _, err := os.Stat(nextToExecutable); err == nil {
+			return nextToExecutable
+		}
+	}
+
+	return ".env"
+}
+
+type sources struct {
+	flags       *flag.FlagSet
+	configFile  *string
+	envFile     *string
+	envFileSet  bool
+	flagValues  map[string]*string
+	settingName map[string]string
+}
+
+// newSources adds the configuration flags to fs and parses args with it.
+func newSources(fs *flag.FlagSet, args []string) (*sources, error) {
+	s := &sources{
+		flags:       fs,
+		flagValues:  map[string]*string{},
+		settingName: map[string]string{},
+	}
+
+	s.configFile = s.flags.String("config", os.Getenv("KEYLAB_CONFIG"), "path to a YAML or TOML config file (env KEYLAB_CONFIG)")
+	s.envFile = s.flags.String("env-file", defaultEnvFile(), "dotenv file to load if it exists")
+	for _, name := range settingNames() {
+		s.flagValues[name] = s.flags.String(flagName(name), "", "overrides "+name)
+		s.settingName[flagName(name)] = name
+	}
+
+	// Flags and positional arguments may be mixed, as in "migrate goto 12 --config keylab.yaml"
+	var positional []string
+	for {
+		if err := s.flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if s.flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, s.flags.Arg(0))
+		args = s.flags.Args()[1:]
+	}
+
+	// Parsing only the terminator leaves the positional arguments in fs.Args()
+	if err := s.flags.Parse(append([]string{"--"}, positional...)); err != nil {
+		return nil, err
+	}
+
+	s.flags.Visit(func(f *flag.Flag) {
+		if f.Name == "env-file" {
+			s.envFileSet = true
+		}
+	})
+
+	return s, nil
+}
+
+// values merges every layer above the defaults, later layers winning: config file, .env file,
+// environment variables and finally flags.
+func (s *sources) values() (map[string]string, error) {
+	values := map[string]string{}
+
+	if *s.configFile != "" {
+		fileValues, err := readConfigFile(*s.configFile)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range fileValues {
+			values[name] = value
+		}
+	}
+
+	if *s.envFile != "" {
+		dotenv, err := godotenv.Read(*s.envFile)
+		if err != nil && (s.envFileSet || !errors.Is(err, fs.ErrNotExist)) {
+			return nil, fmt.Errorf("could not read env file: %w", err)
+		}
+		for name, value := range dotenv {
+			values[name] = value
+		}
+	}
+
+	for _, pair := range os.Environ() {
+		name, value, _ := strings.Cut(pair, "=")
+		values[name] = value
+	}
+
+	s.flags.Visit(func(f *flag.Flag) {
+		if name, ok := s.settingName[f.Name]; ok {
+			values[name] = *s.flagValues[name]
+		}
+	})
+
+	return values, nil
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"keylab/config"
-	"keylab/database/seeders"
+	"keylab/logging"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -21,8 +24,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// InitDB connects to the database. Migrations are not applied here, see MigrateUp and the migrate
+// command.
 func InitDB() *gorm.DB {
-	config := config.Initialize()
+	config := config.Get()
 
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		config.MARIADB_USER,
@@ -32,96 +37,173 @@ func InitDB() *gorm.DB {
 		config.MARIADB_DATABASE,
 	)
 
-	fmt.Println(config)
+	slog.Info("Connecting to the database", "config", config)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logging.NewGormLogger(slog.Default(), config.DB_SLOW_QUERY_THRESHOLD),
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect to the database: %v", err)
 	}
 
-	if err := runMigrations(db, config.MARIADB_DATABASE); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
-	}
+	slog.Info("Connected to the database")
 
-	log.Println("Connected to the database and migrations completed")
+	return db
+}
 
-	if err := runSeeder(db); err != nil {
-		log.Fatalf("Failed to seed database: %v", err)
+func runMigrations(db *gorm.DB, dbName string) error {
+	m, err := newMigrator(db, dbName)
+	if err != nil {
+		return err
 	}
 
-	return db
+	if err := m.Up(); err != nil {
+		if errors.Is(err, migrate.ErrNoChange) {
+			slog.Info("Migrations are up to date, no changes were made")
+			return nil
+		}
+		return fmt.Errorf("could not run migrations: %w", err)
+	}
+
+	slog.Info("Migrations completed successfully")
+	return nil
 }
 
-func runMigrations(db *gorm.DB, dbName string) error {
+// newMigrator creates a golang-migrate instance on the shared connection pool. It is never closed,
+// since closing it would close the pool as well.
+func newMigrator(db *gorm.DB, dbName string) (*migrate.Migrate, error) {
 	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("could not get SQL DB: %w", err)
+		return nil, fmt.Errorf("could not get SQL DB: %w", err)
 	}
 
+	migrationsPath, err := findMigrationsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Using migrations path", "path", migrationsPath)
+
+	driver, err := mysqlDriver.WithInstance(sqlDB, &mysqlDriver.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("could not create MySQL driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(
+		fmt.Sprintf("file://%s", migrationsPath),
+		dbName,
+		driver,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create migrate instance: %w", err)
+	}
+
+	return m, nil
+}
+
+// findMigrationsPath walks up from the working directory until it finds database/migrations.
+func findMigrationsPath() (string, error) {
 	projectRoot, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("could not get working directory: %w", err)
+		return "", fmt.Errorf("could not get working directory: %w", err)
 	}
 
 	migrationsPath := filepath.Join(projectRoot, "database", "migrations")
 	for {
 		if _, err := os.Stat(migrationsPath); err == nil {
-			break
+			return migrationsPath, nil
 		}
 
 		parent := filepath.Dir(projectRoot)
 		if parent == projectRoot {
 			// We've reached the root
-			return fmt.Errorf("migrations directory not found")
+			return "", fmt.Errorf("migrations directory not found")
 		}
 		projectRoot = parent
 		migrationsPath = filepath.Join(projectRoot, "database", "migrations")
 	}
+}
 
-	log.Printf("Using migrations path: %s", migrationsPath)
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
 
-	driver, err := mysqlDriver.WithInstance(sqlDB, &mysqlDriver.Config{})
+// LatestMigrationVersion returns the highest version found in the migrations directory.
+func LatestMigrationVersion() (uint, error) {
+	migrationsPath, err := findMigrationsPath()
 	if err != nil {
-		return fmt.Errorf("could not create MySQL driver: %w", err)
+		return 0, err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		dbName,
-		driver,
-	)
+	entries, err := os.ReadDir(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("could not create migrate instance: %w", err)
+		return 0, fmt.Errorf("could not read migrations directory: %w", err)
 	}
 
-	if err := m.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			log.Println("Migrations are up to date, no changes were made")
-			return nil
+	var latest uint
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(version) > latest {
+			latest = uint(version)
 		}
-		return fmt.Errorf("could not run migrations: %w", err)
 	}
 
-	log.Println("Migrations completed successfully")
-	return nil
+	return latest, nil
 }
 
-func runSeeder(db *gorm.DB) error {
-	if os.Getenv("RUN_TEST_SEEDER") == "true" {
-		if err := seeders.SeedAll(db); err != nil {
-			return fmt.Errorf("could not seed database: %w", err)
-		}
+// CurrentMigrationVersion reads the applied version from golang-migrate's schema_migrations table.
+// It queries the table directly because closing a migrate instance would also close the shared
+// connection pool.
+func CurrentMigrationVersion(ctx context.Context, db *gorm.DB) (uint, bool, error) {
+	var state struct {
+		Version uint
+		Dirty   bool
+	}
 
-		log.Println("Database seeded successfully")
-		return nil
-	} else {
-		// if err := seeders.CleanTables(db); err != nil {
-		// 	return fmt.Errorf("could not clean tables: %w", err)
-		// }
+	if !db.WithContext(ctx).Migrator().HasTable("schema_migrations") {
+		return 0, false, nil
+	}
 
-		log.Println("Database test seeding is disabled")
-		return nil
+	result := db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&state)
+	if result.Error != nil {
+		return 0, false, fmt.Errorf("could not read schema_migrations: %w", result.Error)
 	}
+	if result.RowsAffected == 0 {
+		return 0, false, nil
+	}
+
+	return state.Version, state.Dirty, nil
+}
+
+// CheckReady reports whether the database is reachable and fully migrated.
+func CheckReady(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("could not get SQL DB: %w", err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+
+	state, err := GetMigrationState(ctx, db)
+	if err != nil {
+		return err
+	}
+	if state.Dirty {
+		return fmt.Errorf("migration %d is dirty", state.Current)
+	}
+	if state.Pending() > 0 {
+		return fmt.Errorf("database is at migration %d, expected %d", state.Current, state.Latest)
+	}
+
+	return nil
 }
 
 type TestDB struct {
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"keylab/config"
+
+	"github.com/golang-migrate/migrate/v4"
+	"gorm.io/gorm"
+)
+
+// MigrationState describes how far the database schema is from the migrations on disk.
+type MigrationState struct {
+	Current uint
+	Latest  uint
+	Dirty   bool
+}
+
+func (s MigrationState) Pending() uint {
+	if s.Current >= s.Latest {
+		return 0
+	}
+
+	return s.Latest - s.Current
+}
+
+// MigrateUp applies every pending migration.
+func MigrateUp(db *gorm.DB) error {
+	return runMigrations(db, config.Get().MARIADB_DATABASE)
+}
+
+// MigrateDown rolls back the given number of migrations.
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+
+	m, err := newMigrator(db, config.Get().MARIADB_DATABASE)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("could not roll back migrations: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateGoto migrates up or down to the given version.
+func MigrateGoto(db *gorm.DB, version uint) error {
+	m, err := newMigrator(db, config.Get().MARIADB_DATABASE)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("could not migrate to version %d: %w", version, err)
+	}
+
+	return nil
+}
+
+// GetMigrationState compares the applied migration version with the migrations on disk.
+func GetMigrationState(ctx context.Context, db *gorm.DB) (MigrationState, error) {
+	var state MigrationState
+
+	current, dirty, err := CurrentMigrationVersion(ctx, db)
+	if err != nil {
+		return state, err
+	}
+
+	latest, err := LatestMigrationVersion()
+	if err != nil {
+		return state, err
+	}
+
+	state.Current, state.Dirty, state.Latest = current, dirty, latest
+	return state, nil
+}
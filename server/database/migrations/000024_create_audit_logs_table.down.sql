@@ -0,0 +1 @@
+drop table audit_logs;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE exchange_rates (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    currency CHAR(3) NOT NULL UNIQUE,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
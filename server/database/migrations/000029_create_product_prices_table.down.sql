@@ -0,0 +1 @@
+DROP TABLE product_prices;
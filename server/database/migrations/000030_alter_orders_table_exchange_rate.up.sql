@@ -0,0 +1,2 @@
+ALTER TABLE orders
+ADD COLUMN exchange_rate DECIMAL(18,8) NOT NULL DEFAULT 1 AFTER currency;
@@ -0,0 +1,3 @@
+ALTER TABLE ordered_items
+DROP COLUMN exchange_rate,
+DROP COLUMN currency;
@@ -0,0 +1,3 @@
+ALTER TABLE ordered_items
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'GBP' AFTER price,
+ADD COLUMN exchange_rate DECIMAL(18,8) NOT NULL DEFAULT 1 AFTER currency;
@@ -0,0 +1 @@
+DELETE FROM tax_classes WHERE slug = 'standard';
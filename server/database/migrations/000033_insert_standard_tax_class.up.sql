@@ -0,0 +1 @@
+INSERT INTO tax_classes (name, slug) VALUES ('Standard', 'standard');
@@ -0,0 +1,3 @@
+ALTER TABLE product_categories
+DROP FOREIGN KEY fk_product_categories_tax_class,
+DROP COLUMN tax_class_id;
@@ -0,0 +1,3 @@
+ALTER TABLE product_categories
+ADD COLUMN tax_class_id BIGINT NULL AFTER parent_id,
+ADD CONSTRAINT fk_product_categories_tax_class FOREIGN KEY (tax_class_id) REFERENCES tax_classes(id) ON DELETE SET NULL;
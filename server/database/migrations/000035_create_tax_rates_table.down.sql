@@ -0,0 +1 @@
+DROP TABLE tax_rates;
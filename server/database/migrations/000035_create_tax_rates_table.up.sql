@@ -0,0 +1,12 @@
+CREATE TABLE tax_rates (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tax_class_id BIGINT NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    county VARCHAR(100) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (tax_class_id) REFERENCES tax_classes(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_tax_rates_region (tax_class_id, country, county)
+);
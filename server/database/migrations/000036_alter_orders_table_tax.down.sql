@@ -0,0 +1,3 @@
+ALTER TABLE orders
+DROP COLUMN tax_mode,
+DROP COLUMN tax_total;
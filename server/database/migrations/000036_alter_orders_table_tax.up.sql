@@ -0,0 +1,3 @@
+ALTER TABLE orders
+ADD COLUMN tax_total DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER total,
+ADD COLUMN tax_mode ENUM('inclusive','exclusive') NOT NULL DEFAULT 'inclusive' AFTER tax_total;
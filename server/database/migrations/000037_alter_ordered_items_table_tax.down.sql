@@ -0,0 +1,4 @@
+ALTER TABLE ordered_items
+DROP COLUMN tax_amount,
+DROP COLUMN tax_rate,
+DROP COLUMN tax_name;
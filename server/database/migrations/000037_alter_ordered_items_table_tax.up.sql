@@ -0,0 +1,4 @@
+ALTER TABLE ordered_items
+ADD COLUMN tax_name VARCHAR(100) NOT NULL DEFAULT '' AFTER price,
+ADD COLUMN tax_rate DECIMAL(18,8) NOT NULL DEFAULT 0 AFTER tax_name,
+ADD COLUMN tax_amount DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER tax_rate;
@@ -0,0 +1,10 @@
+CREATE TABLE order_taxes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL,
+    taxable DECIMAL(10,2) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE shipping_methods;
@@ -0,0 +1,13 @@
+CREATE TABLE shipping_methods (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    zone_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type ENUM('standard','express','pickup') NOT NULL,
+    rate_type ENUM('flat','weight','free_over') NOT NULL,
+    price DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    per_kg DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (per_kg >= 0),
+    free_over DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (free_over >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (zone_id) REFERENCES shipping_zones(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE products
+ADD COLUMN weight INT NOT NULL DEFAULT 0 CHECK (weight >= 0) AFTER stock;
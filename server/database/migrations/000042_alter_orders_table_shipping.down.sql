@@ -0,0 +1,5 @@
+ALTER TABLE orders
+DROP FOREIGN KEY fk_orders_shipping_method,
+DROP COLUMN shipping_cost,
+DROP COLUMN shipping_method,
+DROP COLUMN shipping_method_id;
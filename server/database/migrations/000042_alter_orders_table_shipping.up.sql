@@ -0,0 +1,5 @@
+ALTER TABLE orders
+ADD COLUMN shipping_method_id BIGINT NULL AFTER tax_mode,
+ADD COLUMN shipping_method VARCHAR(100) NOT NULL DEFAULT '' AFTER shipping_method_id,
+ADD COLUMN shipping_cost DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER shipping_method,
+ADD CONSTRAINT fk_orders_shipping_method FOREIGN KEY (shipping_method_id) REFERENCES shipping_methods(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE products
+ADD COLUMN low_stock_threshold INT NULL CHECK (low_stock_threshold >= 0) AFTER weight,
+ADD COLUMN low_stock_alerted_at TIMESTAMP NULL AFTER low_stock_threshold;
@@ -0,0 +1,4 @@
+ALTER TABLE product_reviews
+DROP COLUMN status,
+DROP COLUMN report_count,
+DROP COLUMN moderated_at;
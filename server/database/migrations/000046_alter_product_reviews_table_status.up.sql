@@ -0,0 +1,4 @@
+ALTER TABLE product_reviews
+ADD COLUMN status ENUM('pending','approved','rejected','hidden') NOT NULL DEFAULT 'approved' AFTER comment,
+ADD COLUMN report_count INT NOT NULL DEFAULT 0 AFTER status,
+ADD COLUMN moderated_at TIMESTAMP NULL AFTER report_count;
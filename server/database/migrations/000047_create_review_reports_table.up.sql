@@ -0,0 +1,11 @@
+CREATE TABLE review_reports (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    review_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    reason ENUM('spam','abuse','off_topic','other') NOT NULL,
+    comment VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_review_reports_review_user (review_id, user_id),
+    FOREIGN KEY (review_id) REFERENCES product_reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE product_reviews
+DROP COLUMN verified_purchase;
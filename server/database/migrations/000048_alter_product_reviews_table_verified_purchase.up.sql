@@ -0,0 +1,2 @@
+ALTER TABLE product_reviews
+ADD COLUMN verified_purchase BOOLEAN NOT NULL DEFAULT FALSE AFTER comment;
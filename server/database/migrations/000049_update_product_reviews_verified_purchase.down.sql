@@ -0,0 +1 @@
+UPDATE product_reviews SET verified_purchase = FALSE;
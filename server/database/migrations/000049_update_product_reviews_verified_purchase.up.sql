@@ -0,0 +1,9 @@
+UPDATE product_reviews
+SET verified_purchase = TRUE
+WHERE EXISTS (
+    SELECT 1 FROM ordered_items
+    JOIN orders ON orders.id = ordered_items.order_id
+    WHERE orders.user_id = product_reviews.user_id
+        AND ordered_items.product_id = product_reviews.product_id
+        AND orders.status = 'delivered'
+);
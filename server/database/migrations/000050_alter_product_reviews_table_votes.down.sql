@@ -0,0 +1,3 @@
+ALTER TABLE product_reviews
+DROP COLUMN helpful_count,
+DROP COLUMN not_helpful_count;
@@ -0,0 +1,3 @@
+ALTER TABLE product_reviews
+ADD COLUMN helpful_count INT NOT NULL DEFAULT 0 AFTER verified_purchase,
+ADD COLUMN not_helpful_count INT NOT NULL DEFAULT 0 AFTER helpful_count;
@@ -0,0 +1,4 @@
+-- comment stays TEXT: shortening it back to VARCHAR(255) would fail on, or truncate, the
+-- reviews written since, so this part of the migration is not reversed.
+ALTER TABLE product_reviews
+DROP COLUMN title;
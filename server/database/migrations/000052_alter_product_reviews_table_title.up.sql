@@ -0,0 +1,3 @@
+ALTER TABLE product_reviews
+ADD COLUMN title VARCHAR(100) NOT NULL DEFAULT '' AFTER rating,
+MODIFY comment TEXT NOT NULL;
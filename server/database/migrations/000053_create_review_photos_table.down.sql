@@ -0,0 +1 @@
+DROP TABLE review_photos;
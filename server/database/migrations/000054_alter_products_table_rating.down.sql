@@ -0,0 +1,3 @@
+ALTER TABLE products
+DROP COLUMN average_rating,
+DROP COLUMN review_count;
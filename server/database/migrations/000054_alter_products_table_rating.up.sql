@@ -0,0 +1,3 @@
+ALTER TABLE products
+ADD COLUMN average_rating DECIMAL(3,2) NOT NULL DEFAULT 0,
+ADD COLUMN review_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+UPDATE products SET review_count = 0, average_rating = 0, updated_at = updated_at;
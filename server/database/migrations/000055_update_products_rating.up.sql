@@ -0,0 +1,4 @@
+UPDATE products
+SET review_count = (SELECT COUNT(*) FROM product_reviews WHERE product_reviews.product_id = products.id AND product_reviews.status = 'approved'),
+    average_rating = (SELECT COALESCE(AVG(rating), 0) FROM product_reviews WHERE product_reviews.product_id = products.id AND product_reviews.status = 'approved'),
+    updated_at = updated_at;
@@ -0,0 +1,15 @@
+CREATE TABLE product_answers (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    question_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    staff BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_purchase BOOLEAN NOT NULL DEFAULT FALSE,
+    official BOOLEAN NOT NULL DEFAULT FALSE,
+    upvote_count INT NOT NULL DEFAULT 0,
+    status ENUM('published', 'hidden') NOT NULL DEFAULT 'published',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (question_id) REFERENCES product_questions(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
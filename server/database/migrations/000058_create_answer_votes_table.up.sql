@@ -0,0 +1,9 @@
+CREATE TABLE answer_votes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    answer_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_answer_votes_answer_user (answer_id, user_id),
+    FOREIGN KEY (answer_id) REFERENCES product_answers(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE product_recommendations (
+    product_id BIGINT NOT NULL,
+    recommended_id BIGINT NOT NULL,
+    score INT NOT NULL,
+    PRIMARY KEY (product_id, recommended_id),
+    INDEX idx_product_recommendations_score (product_id, score),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (recommended_id) REFERENCES products(id) ON DELETE CASCADE
+);
@@ -2,8 +2,6 @@ package models
 
 import (
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type AddressType string
@@ -28,6 +26,5 @@ type Address struct {
 }
 
 func (a *Address) Validate() error {
-	validate := validator.New()
 	return validate.Struct(a)
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AnswerVote is a user's upvote of an answer to a product question. Each user can upvote an answer
+// once.
+type AnswerVote struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AnswerID  int64     `gorm:"not null" json:"answer_id"`
+	UserID    int64     `gorm:"not null" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
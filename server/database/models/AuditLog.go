@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityUser         = "user"
+	AuditEntityOrder        = "order"
+	AuditEntityRole         = "role"
+	AuditEntityProduct      = "product"
+	AuditEntityProductImage = "product_image"
+	AuditEntityCategory     = "category"
+
+	AuditEntityProductTranslation  = "product_translation"
+	AuditEntityCategoryTranslation = "category_translation"
+	AuditEntityExchangeRate        = "exchange_rate"
+	AuditEntityProductPrice        = "product_price"
+	AuditEntityTaxClass            = "tax_class"
+	AuditEntityTaxRate             = "tax_rate"
+	AuditEntityShippingZone        = "shipping_zone"
+	AuditEntityShippingMethod      = "shipping_method"
+	AuditEntityReview              = "review"
+	AuditEntityQuestion            = "question"
+	AuditEntityAnswer              = "answer"
+)
+
+// AuditLog is an append-only record of a privileged action. OldValues and NewValues only hold
+// the fields that changed, so a create has no OldValues and a delete has no NewValues.
+type AuditLog struct {
+	ID         int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID    *int64          `gorm:"default:null" json:"actor_id"`
+	Actor      *User           `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Action     string          `gorm:"type:varchar(100);not null" json:"action"`
+	EntityType string          `gorm:"type:varchar(100);not null" json:"entity_type"`
+	EntityID   int64           `gorm:"not null" json:"entity_id"`
+	OldValues  json.RawMessage `gorm:"type:json" json:"before"`
+	NewValues  json.RawMessage `gorm:"type:json" json:"after"`
+	IPAddress  string          `gorm:"type:varchar(45)" json:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
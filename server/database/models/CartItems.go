@@ -2,8 +2,6 @@ package models
 
 import (
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type CartItems struct {
@@ -18,8 +16,6 @@ type CartItems struct {
 }
 
 func (c *CartItems) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(c, fields...)
 	}
@@ -2,8 +2,6 @@ package models
 
 import (
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type ContactUsRequest struct {
@@ -18,8 +16,6 @@ type ContactUsRequest struct {
 }
 
 func (c *ContactUsRequest) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(c, fields...)
 	}
@@ -1,28 +1,46 @@
 package models
 
 import (
+	"keylab/money"
 	"time"
-	"github.com/go-playground/validator/v10"
 )
 
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// Discount takes Value off an order, either as a percentage (12.50 is 12.5%) or as a fixed amount.
 type Discount struct {
-	ID             int64     `gorm:"primaryKey;autoIncrement" json:"id" form:"id" validate:"omitempty,numeric"`
-	Code           string    `gorm:"type:varchar(50);not null;unique" json:"code" form:"code" validate:"required,max=50"`
-	DiscountType   string    `gorm:"type:enum('percentage','fixed');not null" json:"discount_type" form:"discount_type" validate:"required,oneof=percentage fixed"`
-	Value          float64   `gorm:"type:decimal(10,2);not null" json:"value" form:"value" validate:"required,gte=0"`
-	StartDate      time.Time `gorm:"not null" json:"start_date" form:"start_date" validate:"required"`
-	EndDate        time.Time `gorm:"not null" json:"end_date" form:"end_date" validate:"required,gtfield=StartDate"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID           int64       `gorm:"primaryKey;autoIncrement" json:"id" form:"id" validate:"omitempty,numeric"`
+	Code         string      `gorm:"type:varchar(50);not null;unique" json:"code" form:"code" validate:"required,max=50"`
+	DiscountType string      `gorm:"type:enum('percentage','fixed');not null" json:"discount_type" form:"discount_type" validate:"required,oneof=percentage fixed"`
+	Value        money.Money `gorm:"type:decimal(10,2);not null" json:"value" form:"value" validate:"required,gte=0"`
+	StartDate    time.Time   `gorm:"not null" json:"start_date" form:"start_date" validate:"required"`
+	EndDate      time.Time   `gorm:"not null" json:"end_date" form:"end_date" validate:"required,gtfield=StartDate"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
-
 func (d *Discount) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(d, fields...)
 	}
 
 	return validate.Struct(d)
-}
\ No newline at end of file
+}
+
+// Amount returns how much the discount takes off subtotal. Percentages are rounded half away from
+// zero, and neither kind ever takes off more than the subtotal.
+func (d *Discount) Amount(subtotal money.Money) money.Money {
+	amount := d.Value
+	if d.DiscountType == DiscountPercentage {
+		amount = subtotal.Percent(d.Value)
+	}
+
+	if amount.Cmp(subtotal) > 0 {
+		return subtotal
+	}
+
+	return amount
+}
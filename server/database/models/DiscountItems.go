@@ -1,7 +1,6 @@
 package models
 
 import (
-	"github.com/go-playground/validator/v10"
 )
 
 type DiscountItems struct { 
@@ -13,8 +12,6 @@ type DiscountItems struct {
 }
 
 func (di *DiscountItems) Validate(fields ...string) error { 
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(di, fields...)
 	}
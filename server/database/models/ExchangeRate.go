@@ -0,0 +1,24 @@
+package models
+
+import (
+	"keylab/money"
+	"time"
+)
+
+// ExchangeRate is how much of Currency one unit of the store's base currency buys. Catalog prices
+// are converted with it unless a product has a price of its own in that currency.
+type ExchangeRate struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Currency  money.Currency `gorm:"type:CHAR(3);not null;unique" validate:"required" json:"currency"`
+	Rate      money.Rate     `gorm:"type:DECIMAL(18,8);not null" validate:"gt=0" json:"rate"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (er *ExchangeRate) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(er, fields...)
+	}
+
+	return validate.Struct(er)
+}
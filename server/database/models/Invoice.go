@@ -0,0 +1,20 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Invoice gives an order its invoice number. Numbers are issued in sequence, without gaps, the
+// first time the order's invoice is requested, and never change.
+type Invoice struct {
+	ID       int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID  int64     `gorm:"not null;unique" json:"order_id"`
+	Number   int64     `gorm:"not null;unique" json:"number"`
+	IssuedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"issued_at"`
+}
+
+// Reference is the invoice number as printed, e.g. INV-000042.
+func (i Invoice) Reference() string {
+	return fmt.Sprintf("INV-%06d", i.Number)
+}
@@ -1,9 +1,9 @@
 package models
 
 import (
+	"keylab/money"
+	"keylab/tax"
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type OrderStatus string
@@ -17,24 +17,31 @@ const (
 )
 
 type Order struct {
-	ID                int64         `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID            int64         `gorm:"not null" json:"user_id"`
-	User              User          `gorm:"foreignKey:UserID" json:"user"`
-	OrderDate         time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"order_date"`
-	Status            OrderStatus   `gorm:"type:ENUM('pending','shipped','delivered','cancelled', 'returned');not null" json:"status"`
-	Total             float64       `gorm:"type:DECIMAL(10,2);not null" json:"total"`
-	ShippingAddressID int64         `gorm:"column:shipping_address;not null" json:"shipping_address_id"`
-	BillingAddressID  int64         `gorm:"column:billing_address;not null" json:"billing_address_id"`
-	ShippingAddress   *Address      `gorm:"foreignKey:ShippingAddressID" json:"shipping_address"`
-	BillingAddress    *Address      `gorm:"foreignKey:BillingAddressID" json:"billing_address"`
-	OrderItems        []OrderedItem `gorm:"foreignKey:OrderID" json:"order_items"`
-	CreatedAt         time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt         time.Time     `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
+	ID                int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID            int64          `gorm:"not null" json:"user_id"`
+	User              User           `gorm:"foreignKey:UserID" json:"user"`
+	OrderDate         time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"order_date"`
+	Status            OrderStatus    `gorm:"type:ENUM('pending','shipped','delivered','cancelled', 'returned');not null" json:"status"`
+	Total             money.Money    `gorm:"type:DECIMAL(10,2);not null" json:"total"`
+	TaxTotal          money.Money    `gorm:"type:DECIMAL(10,2);not null;default:0" json:"tax_total"`
+	TaxMode           tax.Mode       `gorm:"type:ENUM('inclusive','exclusive');not null;default:inclusive" json:"tax_mode"`
+	ShippingMethodID  *int64         `gorm:"default:null" json:"shipping_method_id"`
+	ShippingMethod    string         `gorm:"type:varchar(100);not null;default:''" json:"shipping_method"`
+	ShippingCost      money.Money    `gorm:"type:DECIMAL(10,2);not null;default:0" json:"shipping_cost"`
+	Currency          money.Currency `gorm:"type:CHAR(3);not null;default:GBP" json:"currency"`
+	ExchangeRate      money.Rate     `gorm:"type:DECIMAL(18,8);not null;default:1" json:"exchange_rate"`
+	ShippingAddressID int64          `gorm:"column:shipping_address;not null" json:"shipping_address_id"`
+	BillingAddressID  int64          `gorm:"column:billing_address;not null" json:"billing_address_id"`
+	ShippingAddress   *Address       `gorm:"foreignKey:ShippingAddressID" json:"shipping_address"`
+	BillingAddress    *Address       `gorm:"foreignKey:BillingAddressID" json:"billing_address"`
+	OrderItems        []OrderedItem  `gorm:"foreignKey:OrderID" json:"order_items"`
+	Taxes             []OrderTax     `gorm:"foreignKey:OrderID" json:"taxes"`
+	Invoice           *Invoice       `gorm:"foreignKey:OrderID" json:"invoice,omitempty"`
+	CreatedAt         time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
 }
 
 func (o *Order) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(o, fields...)
 	}
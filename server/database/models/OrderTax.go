@@ -0,0 +1,18 @@
+package models
+
+import (
+	"keylab/money"
+	"time"
+)
+
+// OrderTax is one line of an order's tax breakdown: the tax charged at one rate, and the net
+// amount it was charged on.
+type OrderTax struct {
+	ID        int64       `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID   int64       `gorm:"not null" json:"order_id"`
+	Name      string      `gorm:"type:varchar(100);not null" json:"name"`
+	Rate      money.Rate  `gorm:"type:DECIMAL(18,8);not null" json:"rate"`
+	Taxable   money.Money `gorm:"type:DECIMAL(10,2);not null" json:"taxable"`
+	Amount    money.Money `gorm:"type:DECIMAL(10,2);not null" json:"amount"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -1,26 +1,28 @@
 package models
 
 import (
+	"keylab/money"
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type OrderedItem struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
-	OrderID   int64     `gorm:"not null" json:"order_id"`
-	Order     Order     `gorm:"foreignKey:OrderID" json:"order"`
-	ProductID int64     `gorm:"not null" json:"product_id"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product"`
-	Quantity  int       `gorm:"not null" json:"quantity"`
-	Price     float64   `gorm:"type:DECIMAL(10,2);not null" json:"price"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
+	ID           int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID      int64          `gorm:"not null" json:"order_id"`
+	Order        Order          `gorm:"foreignKey:OrderID" json:"order"`
+	ProductID    int64          `gorm:"not null" json:"product_id"`
+	Product      Product        `gorm:"foreignKey:ProductID" json:"product"`
+	Quantity     int            `gorm:"not null" json:"quantity"`
+	Price        money.Money    `gorm:"type:DECIMAL(10,2);not null" json:"price"`
+	TaxName      string         `gorm:"type:varchar(100);not null;default:''" json:"tax_name"`
+	TaxRate      money.Rate     `gorm:"type:DECIMAL(18,8);not null;default:0" json:"tax_rate"`
+	TaxAmount    money.Money    `gorm:"type:DECIMAL(10,2);not null;default:0" json:"tax_amount"`
+	Currency     money.Currency `gorm:"type:CHAR(3);not null;default:GBP" json:"currency"`
+	ExchangeRate money.Rate     `gorm:"type:DECIMAL(18,8);not null;default:1" json:"exchange_rate"`
+	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP;autoUpdateTime" json:"updated_at"`
 }
 
 func (oi *OrderedItem) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(oi, fields...)
 	}
@@ -2,8 +2,6 @@ package models
 
 import (
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type Permission struct {
@@ -20,8 +18,6 @@ type RolePermission struct {
 }
 
 func (p *Permission) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(p, fields...)
 	}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// ProductCategoryTranslation holds a category's name and description in a language other than the
+// default.
+type ProductCategoryTranslation struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	CategoryID  int64     `gorm:"not null;uniqueIndex:uq_product_category_translations_locale" json:"category_id"`
+	Locale      string    `gorm:"type:varchar(10);not null;uniqueIndex:uq_product_category_translations_locale" validate:"required,max=10" json:"locale"`
+	Name        string    `gorm:"type:varchar(255);not null" validate:"required,max=255" json:"name"`
+	Description string    `gorm:"type:varchar(255);not null" validate:"required,max=255" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (pct *ProductCategoryTranslation) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(pct, fields...)
+	}
+
+	return validate.Struct(pct)
+}
@@ -2,8 +2,6 @@ package models
 
 import (
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type ProductImage struct {
@@ -17,8 +15,6 @@ type ProductImage struct {
 }
 
 func (pi *ProductImage) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(pi, fields...)
 	}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"keylab/productcsv"
+	"time"
+)
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+// ProductImport is a CSV file of products being imported. Rows with errors are skipped and listed
+// in Errors; a failed import is one that stopped early, with the reason in Message. A dry run
+// counts what would be created and updated without changing anything.
+type ProductImport struct {
+	ID            int64                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        *int64                `gorm:"default:null" json:"user_id"`
+	Filename      string                `gorm:"type:varchar(255);not null" json:"filename"`
+	DryRun        bool                  `gorm:"not null;default:false" json:"dry_run"`
+	Status        ImportStatus          `gorm:"type:ENUM('pending','running','completed','failed');not null" json:"status"`
+	TotalRows     int                   `gorm:"not null;default:0" json:"total_rows"`
+	ProcessedRows int                   `gorm:"not null;default:0" json:"processed_rows"`
+	Created       int                   `gorm:"not null;default:0" json:"created"`
+	Updated       int                   `gorm:"not null;default:0" json:"updated"`
+	Failed        int                   `gorm:"not null;default:0" json:"failed"`
+	Errors        []productcsv.RowError `gorm:"type:json;serializer:json" json:"errors"`
+	Message       string                `gorm:"type:varchar(255);not null;default:''" json:"message,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+	FinishedAt    *time.Time            `gorm:"default:null" json:"finished_at"`
+}
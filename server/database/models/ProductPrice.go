@@ -0,0 +1,24 @@
+package models
+
+import (
+	"keylab/money"
+	"time"
+)
+
+// ProductPrice overrides the converted price of a product in one currency.
+type ProductPrice struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID int64          `gorm:"not null;uniqueIndex:uq_product_prices_currency" json:"product_id"`
+	Currency  money.Currency `gorm:"type:CHAR(3);not null;uniqueIndex:uq_product_prices_currency" validate:"required" json:"currency"`
+	Price     money.Money    `gorm:"type:DECIMAL(10,2);not null" validate:"gte=0" json:"price"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (pp *ProductPrice) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(pp, fields...)
+	}
+
+	return validate.Struct(pp)
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+type QuestionStatus string
+
+const (
+	QuestionPublished QuestionStatus = "published"
+	QuestionHidden    QuestionStatus = "hidden"
+)
+
+func ParseQuestionStatus(s string) (QuestionStatus, bool) {
+	switch status := QuestionStatus(s); status {
+	case QuestionPublished, QuestionHidden:
+		return status, true
+	}
+
+	return "", false
+}
+
+// ProductQuestion is a question a user asked about a product. Questions and answers are published
+// straight away, moderators hide the ones shoppers should not see.
+type ProductQuestion struct {
+	ID        int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID int64           `gorm:"not null" json:"product_id"`
+	UserID    int64           `gorm:"not null" json:"user_id"`
+	Body      string          `gorm:"type:varchar(1000);not null" json:"body" validate:"required,max=1000"`
+	Status    QuestionStatus  `gorm:"type:ENUM('published','hidden');not null;default:published" json:"status" validate:"-"`
+	User      User            `gorm:"foreignKey:UserID" json:"user" validate:"-"`
+	Answers   []ProductAnswer `gorm:"foreignKey:QuestionID" json:"answers" validate:"-"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (q *ProductQuestion) Validate() error {
+	return validate.Struct(q)
+}
+
+// ProductAnswer is an answer to a product question by a staff member or a user who had the
+// product delivered. Moderators mark answers official to show them first. UpvoteCount is kept in
+// step with the answer's AnswerVotes.
+type ProductAnswer struct {
+	ID          int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuestionID  int64          `gorm:"not null" json:"question_id"`
+	UserID      int64          `gorm:"not null" json:"user_id"`
+	Body        string         `gorm:"type:text;not null" json:"body" validate:"required,max=5000"`
+	Staff       bool           `gorm:"not null;default:false" json:"staff" validate:"-"`
+	Verified    bool           `gorm:"column:verified_purchase;not null;default:false" json:"verified_purchase" validate:"-"`
+	Official    bool           `gorm:"not null;default:false" json:"official" validate:"-"`
+	UpvoteCount int            `gorm:"not null;default:0" json:"upvote_count" validate:"-"`
+	Status      QuestionStatus `gorm:"type:ENUM('published','hidden');not null;default:published" json:"status" validate:"-"`
+	User        User           `gorm:"foreignKey:UserID" json:"user" validate:"-"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (a *ProductAnswer) Validate() error {
+	return validate.Struct(a)
+}
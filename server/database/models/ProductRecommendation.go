@@ -0,0 +1,9 @@
+package models
+
+// ProductRecommendation is a product bought together with another, scored by the number of orders
+// that had both. The rows are recomputed from the orders by a background job.
+type ProductRecommendation struct {
+	ProductID     int64 `gorm:"primaryKey" json:"product_id"`
+	RecommendedID int64 `gorm:"primaryKey" json:"recommended_id"`
+	Score         int   `gorm:"not null" json:"score"`
+}
@@ -2,25 +2,51 @@ package models
 
 import (
 	"time"
+)
+
+type ReviewStatus string
 
-	"github.com/go-playground/validator/v10"
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+	ReviewHidden   ReviewStatus = "hidden"
 )
 
+func ParseReviewStatus(s string) (ReviewStatus, bool) {
+	switch status := ReviewStatus(s); status {
+	case ReviewPending, ReviewApproved, ReviewRejected, ReviewHidden:
+		return status, true
+	}
+
+	return "", false
+}
+
+// Only approved reviews are shown to shoppers and counted in the product's rating. ReportCount is
+// the number of open reports, cleared when a moderator reviews it. Verified reviews are by users
+// who had the product delivered. The vote counts are kept in step with the review's ReviewVotes.
+// Photos are uploaded with the review and deleted with it.
 type ProductReviews struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id" validate:"omitempty,numeric"`
-	ProductID int64     `gorm:"not null" json:"product_id" validate:"omitempty,numeric"`
-	UserID    int64     `gorm:"not null" json:"user_id" validate:"omitempty,numeric"`
-	Rating    int16     `gorm:"not null;check:rating BETWEEN 1 AND 5" json:"rating" validate:"required,gte=1,lte=5"`
-	Comment   string    `gorm:"type:varchar(255);not null" validate:"required,max=255" json:"comment"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product" validate:"omitempty"`
-	User      User      `gorm:"foreignKey:UserID" json:"user" validate:"omitempty"`
-	CreatedAt time.Time `json:"created_at" validate:"omitempty"`
-	UpdatedAt time.Time `json:"updated_at" validate:"omitempty"`
+	ID              int64         `gorm:"primaryKey;autoIncrement" json:"id" validate:"omitempty,numeric"`
+	ProductID       int64         `gorm:"not null" json:"product_id" validate:"omitempty,numeric"`
+	UserID          int64         `gorm:"not null" json:"user_id" validate:"omitempty,numeric"`
+	Rating          int16         `gorm:"not null;check:rating BETWEEN 1 AND 5" form:"rating" json:"rating" validate:"required,gte=1,lte=5"`
+	Title           string        `gorm:"type:varchar(100);not null;default:''" form:"title" json:"title" validate:"max=100"`
+	Comment         string        `gorm:"type:text;not null" form:"comment" validate:"required,max=5000" json:"comment"`
+	Verified        bool          `gorm:"column:verified_purchase;not null;default:false" json:"verified_purchase" validate:"-"`
+	HelpfulCount    int           `gorm:"not null;default:0" json:"helpful_count" validate:"-"`
+	NotHelpfulCount int           `gorm:"not null;default:0" json:"not_helpful_count" validate:"-"`
+	Status          ReviewStatus  `gorm:"type:ENUM('pending','approved','rejected','hidden');not null;default:approved" json:"status" validate:"-"`
+	ReportCount     int           `gorm:"not null;default:0" json:"report_count" validate:"-"`
+	ModeratedAt     *time.Time    `json:"moderated_at" validate:"-"`
+	Product         Product       `gorm:"foreignKey:ProductID" json:"product" validate:"omitempty"`
+	User            User          `gorm:"foreignKey:UserID" json:"user" validate:"omitempty"`
+	Photos          []ReviewPhoto `gorm:"foreignKey:ReviewID" json:"photos" validate:"-"`
+	CreatedAt       time.Time     `json:"created_at" validate:"omitempty"`
+	UpdatedAt       time.Time     `json:"updated_at" validate:"omitempty"`
 }
 
 func (pr *ProductReviews) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(pr, fields...)
 	}
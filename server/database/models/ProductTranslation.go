@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// ProductTranslation holds a product's name and description in a language other than the default,
+// which the catalog shows to clients asking for that language.
+type ProductTranslation struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID   int64     `gorm:"not null;uniqueIndex:uq_product_translations_locale" json:"product_id"`
+	Locale      string    `gorm:"type:varchar(10);not null;uniqueIndex:uq_product_translations_locale" validate:"required,max=10" json:"locale"`
+	Name        string    `gorm:"type:varchar(255);not null" validate:"required,max=255" json:"name"`
+	Description string    `gorm:"type:text;not null" validate:"required" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (pt *ProductTranslation) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(pt, fields...)
+	}
+
+	return validate.Struct(pt)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ReviewPhoto is a photo attached to a review. Image is the file's name in the review photo
+// directory and URL where it is served.
+type ReviewPhoto struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ReviewID  int64     `gorm:"not null" json:"review_id"`
+	Image     string    `gorm:"type:varchar(255);not null" json:"filename"`
+	URL       string    `gorm:"-" json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
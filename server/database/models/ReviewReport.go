@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type ReportReason string
+
+const (
+	ReportSpam     ReportReason = "spam"
+	ReportAbuse    ReportReason = "abuse"
+	ReportOffTopic ReportReason = "off_topic"
+	ReportOther    ReportReason = "other"
+)
+
+// ReviewReport is a user flagging a review for moderators. Each user can report a review once.
+type ReviewReport struct {
+	ID        int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ReviewID  int64        `gorm:"not null" json:"review_id"`
+	UserID    int64        `gorm:"not null" json:"user_id"`
+	Reason    ReportReason `gorm:"type:ENUM('spam','abuse','off_topic','other');not null" validate:"required,oneof=spam abuse off_topic other" json:"reason"`
+	Comment   string       `gorm:"type:varchar(500);not null;default:''" validate:"max=500" json:"comment"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func (r *ReviewReport) Validate() error {
+	return validate.Struct(r)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ReviewVote is a user's vote on whether a review was helpful. Each user has one vote per review,
+// which they can change or withdraw.
+type ReviewVote struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ReviewID  int64     `gorm:"not null" json:"review_id"`
+	UserID    int64     `gorm:"not null" json:"user_id"`
+	Helpful   bool      `gorm:"not null" json:"helpful"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -2,8 +2,6 @@ package models
 
 import (
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type Role struct {
@@ -14,8 +12,6 @@ type Role struct {
 }
 
 func (r *Role) Validate(fields ...string) error {
-	validate := validator.New()
-
 	if len(fields) > 0 {
 		return validate.StructPartial(r, fields...)
 	}
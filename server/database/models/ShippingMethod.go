@@ -0,0 +1,30 @@
+package models
+
+import (
+	"keylab/money"
+	"keylab/shipping"
+	"time"
+)
+
+// ShippingMethod is a way of delivering to a zone. Prices are in the base currency; see
+// shipping.RateType for how they make up the cost.
+type ShippingMethod struct {
+	ID        int64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	ZoneID    int64               `gorm:"not null" validate:"required" json:"zone_id"`
+	Name      string              `gorm:"type:varchar(100);not null" validate:"required,max=100" json:"name"`
+	Type      shipping.MethodType `gorm:"type:ENUM('standard','express','pickup');not null" validate:"required,oneof=standard express pickup" json:"type"`
+	RateType  shipping.RateType   `gorm:"type:ENUM('flat','weight','free_over');not null" validate:"required,oneof=flat weight free_over" json:"rate_type"`
+	Price     money.Money         `gorm:"type:DECIMAL(10,2);not null;default:0" validate:"gte=0" json:"price"`
+	PerKg     money.Money         `gorm:"type:DECIMAL(10,2);not null;default:0" validate:"gte=0" json:"per_kg"`
+	FreeOver  money.Money         `gorm:"type:DECIMAL(10,2);not null;default:0" validate:"gte=0" json:"free_over"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+func (sm *ShippingMethod) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(sm, fields...)
+	}
+
+	return validate.Struct(sm)
+}
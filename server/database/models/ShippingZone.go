@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// ShippingZone is a country, or the part of it whose postal codes start with PostalPrefix, and
+// the shipping methods offered there. The zone with the longest matching prefix applies.
+type ShippingZone struct {
+	ID           int64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string           `gorm:"type:varchar(100);not null" validate:"required,max=100" json:"name"`
+	Country      string           `gorm:"type:varchar(100);not null;uniqueIndex:uq_shipping_zones_region" validate:"required,max=100" json:"country"`
+	PostalPrefix string           `gorm:"type:varchar(20);not null;default:'';uniqueIndex:uq_shipping_zones_region" validate:"max=20" json:"postal_prefix"`
+	Methods      []ShippingMethod `gorm:"foreignKey:ZoneID" validate:"-" json:"methods,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+func (sz *ShippingZone) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(sz, fields...)
+	}
+
+	return validate.Struct(sz)
+}
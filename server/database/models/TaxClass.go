@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// StandardTaxClass is the slug of the tax class for categories without one of their own.
+const StandardTaxClass = "standard"
+
+// TaxClass groups categories that are taxed alike, e.g. standard, reduced or zero rated goods.
+type TaxClass struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" validate:"required,max=100" json:"name"`
+	Slug      string    `gorm:"type:varchar(100);not null;unique" validate:"required,max=100" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (tc *TaxClass) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(tc, fields...)
+	}
+
+	return validate.Struct(tc)
+}
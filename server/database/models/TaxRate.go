@@ -0,0 +1,28 @@
+package models
+
+import (
+	"keylab/money"
+	"time"
+)
+
+// TaxRate is the percentage charged on a tax class in a country, or in one county of it when
+// County is set. Country and County are matched against the shipping address. Rate is validated in
+// units of 1/money.RateScale, so its limit is 100%.
+type TaxRate struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaxClassID int64      `gorm:"not null;uniqueIndex:uq_tax_rates_region" validate:"required" json:"tax_class_id"`
+	Country    string     `gorm:"type:varchar(100);not null;uniqueIndex:uq_tax_rates_region" validate:"required,max=100" json:"country"`
+	County     string     `gorm:"type:varchar(100);not null;default:'';uniqueIndex:uq_tax_rates_region" validate:"max=100" json:"county"`
+	Name       string     `gorm:"type:varchar(100);not null" validate:"required,max=100" json:"name"`
+	Rate       money.Rate `gorm:"type:DECIMAL(18,8);not null" validate:"gte=0,lte=10000000000" json:"rate"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (tr *TaxRate) Validate(fields ...string) error {
+	if len(fields) > 0 {
+		return validate.StructPartial(tr, fields...)
+	}
+
+	return validate.Struct(tr)
+}
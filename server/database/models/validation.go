@@ -0,0 +1,37 @@
+package models
+
+import (
+	"keylab/money"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is shared by every model. Field errors carry the JSON field name, so they can be shown
+// to API clients as they are.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	// Amounts are validated as their minor units, so gte=0 on a price means at least 0.00.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(money.Money).Minor()
+	}, money.Money{})
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(money.Rate).Scaled()
+	}, money.Rate{})
+
+	return v
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"keylab/database/models"
+	"keylab/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMoneyColumnsRoundTrip writes DECIMAL(10,2) amounts the way the float64 models did, applies
+// the migration that came with money.Money and checks that loading and saving every row through
+// the models leaves the stored amounts unchanged to the penny.
+func TestMoneyColumnsRoundTrip(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer CleanupTestDB(t, testDB)
+	database := testDB.DB
+
+	m, err := newMigrator(database, "keylab_test")
+	require.NoError(t, err)
+	require.NoError(t, m.Migrate(26))
+
+	amounts := []string{"0.01", "0.10", "0.30", "19.99", "69.99", "1234.50", "99999999.99"}
+
+	require.NoError(t, database.Exec("INSERT INTO product_categories (id, name, slug, description) VALUES (1, 'Keyboards', 'keyboards', 'Keyboards')").Error)
+	require.NoError(t, database.Exec("INSERT INTO users (id, forename, surname, email, password) VALUES (1, 'Jane', 'Doe', 'jane@example.com', 'x')").Error)
+	require.NoError(t, database.Exec("INSERT INTO addresses (id, user_id, street, city, county, postal_code, country, type) VALUES (1, 1, '1 High Street', 'Leeds', 'West Yorkshire', 'LS1 1AA', 'United Kingdom', 'billing')").Error)
+
+	for i, amount := range amounts {
+		id := i + 1
+		require.NoError(t, database.Exec("INSERT INTO products (id, name, slug, description, price, stock, category_id) VALUES (?, 'Product', CONCAT('product-', ?), 'Product', ?, 1, 1)", id, id, amount).Error)
+		require.NoError(t, database.Exec("INSERT INTO orders (id, user_id, order_date, status, total, shipping_address, billing_address) VALUES (?, 1, NOW(), 'pending', ?, 1, 1)", id, amount).Error)
+		require.NoError(t, database.Exec("INSERT INTO ordered_items (order_id, product_id, quantity, price) VALUES (?, ?, 1, ?)", id, id, amount).Error)
+		require.NoError(t, database.Exec("INSERT INTO discounts (code, discount_type, value, start_date, end_date) VALUES (CONCAT('CODE', ?), 'fixed', ?, NOW(), NOW() + INTERVAL 1 DAY)", id, amount).Error)
+	}
+
+	require.NoError(t, m.Up())
+
+	var products []models.Product
+	var orders []models.Order
+	var orderedItems []models.OrderedItem
+	var discounts []models.Discount
+	require.NoError(t, database.Order("id").Find(&products).Error)
+	require.NoError(t, database.Order("id").Find(&orders).Error)
+	require.NoError(t, database.Order("id").Find(&orderedItems).Error)
+	require.NoError(t, database.Order("id").Find(&discounts).Error)
+
+	for i, amount := range amounts {
+		assert.Equal(t, amount, products[i].Price.String())
+		assert.Equal(t, amount, orders[i].Total.String())
+		assert.Equal(t, amount, orderedItems[i].Price.String())
+		assert.Equal(t, amount, discounts[i].Value.String())
+		assert.Equal(t, money.GBP, orders[i].Currency, "existing orders are in pounds")
+
+		require.NoError(t, database.Save(&products[i]).Error)
+		require.NoError(t, database.Model(&orders[i]).Update("total", orders[i].Total).Error)
+		require.NoError(t, database.Save(&orderedItems[i]).Error)
+		require.NoError(t, database.Save(&discounts[i]).Error)
+	}
+
+	for _, column := range []struct{ table, name string }{
+		{"products", "price"},
+		{"orders", "total"},
+		{"ordered_items", "price"},
+		{"discounts", "value"},
+	} {
+		var stored []string
+		require.NoError(t, database.Raw("SELECT CAST("+column.name+" AS CHAR) FROM "+column.table+" ORDER BY id").Scan(&stored).Error)
+		assert.Equal(t, amounts, stored, "%s.%s", column.table, column.name)
+	}
+
+	var sum string
+	require.NoError(t, database.Raw("SELECT CAST(SUM(price) AS CHAR) FROM products").Scan(&sum).Error)
+
+	var total money.Money
+	for _, product := range products {
+		total = total.Add(product.Price)
+	}
+	assert.Equal(t, sum, total.String())
+}
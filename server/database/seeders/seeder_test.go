@@ -0,0 +1,13 @@
+package seeders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedNamedRejectsUnknownSeeders(t *testing.T) {
+	// Names are checked before any seeder runs, so no database is needed
+	err := SeedNamed(nil, "roles", "widgets")
+	assert.EqualError(t, err, `unknown seeder "widgets"`)
+}
@@ -0,0 +1,280 @@
+// Package documents renders the PDFs that go with an order: its invoice and the packing slip the
+// warehouse ships it with.
+//
+// Documents only use the PDF core fonts, so they are generated without font files or network
+// access, and the same order always renders to the same bytes.
+package documents
+
+import (
+	"fmt"
+	"io"
+	"keylab/database/models"
+	"keylab/money"
+	"keylab/tax"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Seller is who issues the documents.
+type Seller struct {
+	Name    string
+	Address []string
+}
+
+// ParseSeller builds the seller from a name and an address with comma separated lines.
+func ParseSeller(name string, address string) Seller {
+	seller := Seller{Name: name}
+	for _, line := range strings.Split(address, ",") {
+		if line = strings.TrimSpace(line); line != "" {
+			seller.Address = append(seller.Address, line)
+		}
+	}
+
+	return seller
+}
+
+const (
+	margin     = 15.0
+	lineHeight = 5.0
+	dateLayout = "2 January 2006"
+)
+
+// document wraps the PDF with the text encoding of the core fonts.
+type document struct {
+	pdf       *fpdf.Fpdf
+	translate func(string) string
+}
+
+func newDocument(title string, created time.Time) *document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetCompression(false)
+	pdf.SetCatalogSort(true)
+	pdf.SetCreationDate(created.UTC())
+	pdf.SetModificationDate(created.UTC())
+	pdf.SetTitle(title, true)
+	pdf.AddPage()
+
+	return &document{pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor("")}
+}
+
+func (d *document) font(style string, size float64) {
+	d.pdf.SetFont("Helvetica", style, size)
+}
+
+// cell writes text in a cell of width w, moving right, or to the next line when ln is true.
+func (d *document) cell(w float64, text string, align string, ln bool) {
+	next := 0
+	if ln {
+		next = 1
+	}
+	d.pdf.CellFormat(w, lineHeight, d.translate(text), "", next, align, false, 0, "")
+}
+
+// lines writes each line in its own row of width w starting at x.
+func (d *document) lines(x float64, w float64, lines []string) {
+	for _, line := range lines {
+		d.pdf.SetX(x)
+		d.cell(w, line, "L", true)
+	}
+}
+
+func (d *document) rule() {
+	y := d.pdf.GetY() + 1
+	width, _ := d.pdf.GetPageSize()
+	d.pdf.Line(margin, y, width-margin, y)
+	d.pdf.SetY(y + 2)
+}
+
+// header writes the seller on the left and the title with the document's details on the right.
+func (d *document) header(seller Seller, title string, details [][2]string) {
+	top := d.pdf.GetY()
+
+	d.font("B", 16)
+	d.cell(100, seller.Name, "L", true)
+	d.font("", 9)
+	d.lines(margin, 100, seller.Address)
+	bottom := d.pdf.GetY()
+
+	d.pdf.SetXY(120, top)
+	d.font("B", 16)
+	d.cell(75, title, "R", true)
+	d.font("", 9)
+	for _, detail := range details {
+		d.pdf.SetX(120)
+		d.cell(40, detail[0], "L", false)
+		d.cell(35, detail[1], "R", true)
+	}
+
+	d.pdf.SetY(max(bottom, d.pdf.GetY()) + 8)
+}
+
+// addresses writes the labelled addresses side by side.
+func (d *document) addresses(blocks ...addressBlock) {
+	top := d.pdf.GetY()
+	bottom := top
+	for i, block := range blocks {
+		x := margin + float64(i)*90
+		d.pdf.SetXY(x, top)
+		d.font("B", 9)
+		d.cell(85, block.label, "L", true)
+		d.font("", 9)
+		d.lines(x, 85, block.lines)
+		bottom = max(bottom, d.pdf.GetY())
+	}
+
+	d.pdf.SetY(bottom + 8)
+}
+
+type addressBlock struct {
+	label string
+	lines []string
+}
+
+func addressLines(name string, address *models.Address) []string {
+	lines := []string{}
+	if name != "" {
+		lines = append(lines, name)
+	}
+	if address == nil {
+		return lines
+	}
+
+	for _, line := range []string{address.Street, address.City, address.County, address.PostalCode, address.Country} {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func customerName(user models.User) string {
+	return strings.TrimSpace(user.Forename + " " + user.Surname)
+}
+
+// row writes a row of cells with the given widths and alignments.
+func (d *document) row(widths []float64, aligns []string, cells ...string) {
+	for i, text := range cells {
+		d.cell(widths[i], text, aligns[i], i == len(cells)-1)
+	}
+}
+
+func (d *document) output(w io.Writer) error {
+	if err := d.pdf.Output(w); err != nil {
+		return fmt.Errorf("rendering pdf: %w", err)
+	}
+
+	return nil
+}
+
+// Invoice renders the invoice for the order, which needs its user, addresses, taxes and items with
+// their products loaded.
+func Invoice(w io.Writer, seller Seller, invoice models.Invoice, order models.Order) error {
+	d := newDocument("Invoice "+invoice.Reference(), invoice.IssuedAt)
+	currency := order.Currency
+
+	d.header(seller, "INVOICE", [][2]string{
+		{"Invoice number", invoice.Reference()},
+		{"Invoice date", invoice.IssuedAt.UTC().Format(dateLayout)},
+		{"Order number", fmt.Sprintf("#%d", order.ID)},
+		{"Order date", order.OrderDate.UTC().Format(dateLayout)},
+	})
+
+	name := customerName(order.User)
+	d.addresses(
+		addressBlock{label: "Bill to", lines: addressLines(name, order.BillingAddress)},
+		addressBlock{label: "Ship to", lines: addressLines(name, order.ShippingAddress)},
+	)
+
+	widths := []float64{85, 15, 25, 20, 35}
+	aligns := []string{"L", "R", "R", "R", "R"}
+	d.font("B", 9)
+	d.row(widths, aligns, "Product", "Qty", "Unit price", "Tax", "Amount")
+	d.rule()
+	d.font("", 9)
+
+	var subtotal money.Money
+	for _, item := range order.OrderItems {
+		amount := item.Price.Mul(int64(item.Quantity))
+		subtotal = subtotal.Add(amount)
+
+		rate := "-"
+		if item.TaxName != "" {
+			rate = item.TaxRate.String() + "%"
+		}
+		d.row(widths, aligns, item.Product.Name, fmt.Sprint(item.Quantity), currency.Format(item.Price), rate, currency.Format(amount))
+	}
+	d.rule()
+
+	totals := [][2]string{{"Subtotal", currency.Format(subtotal)}}
+	if order.ShippingMethod != "" {
+		totals = append(totals, [2]string{"Shipping (" + order.ShippingMethod + ")", currency.Format(order.ShippingCost)})
+	}
+	for _, orderTax := range order.Taxes {
+		label := fmt.Sprintf("%s %s%%", orderTax.Name, orderTax.Rate)
+		if order.TaxMode == tax.Inclusive {
+			label = "Includes " + label
+		}
+		totals = append(totals, [2]string{label, currency.Format(orderTax.Amount)})
+	}
+	for _, total := range totals {
+		d.cell(145, total[0], "R", false)
+		d.cell(35, total[1], "R", true)
+	}
+
+	d.font("B", 10)
+	d.cell(145, "Total", "R", false)
+	d.cell(35, currency.Format(order.Total), "R", true)
+
+	d.pdf.Ln(8)
+	d.font("", 8)
+	if order.TaxMode == tax.Inclusive {
+		d.cell(0, "Prices include tax.", "L", true)
+	} else {
+		d.cell(0, "Tax is charged on top of the prices.", "L", true)
+	}
+	d.cell(0, "Amounts are in "+string(currency)+".", "L", true)
+
+	return d.output(w)
+}
+
+// PackingSlip renders the packing slip for the order, which needs its user, shipping address and
+// items with their products loaded. It lists what to pack, without prices.
+func PackingSlip(w io.Writer, seller Seller, order models.Order) error {
+	d := newDocument(fmt.Sprintf("Packing slip #%d", order.ID), order.OrderDate)
+
+	details := [][2]string{
+		{"Order number", fmt.Sprintf("#%d", order.ID)},
+		{"Order date", order.OrderDate.UTC().Format(dateLayout)},
+	}
+	if order.ShippingMethod != "" {
+		details = append(details, [2]string{"Shipping", order.ShippingMethod})
+	}
+	d.header(seller, "PACKING SLIP", details)
+
+	d.addresses(addressBlock{label: "Ship to", lines: addressLines(customerName(order.User), order.ShippingAddress)})
+
+	widths := []float64{20, 130, 30}
+	aligns := []string{"L", "L", "R"}
+	d.font("B", 9)
+	d.row(widths, aligns, "Packed", "Product", "Qty")
+	d.rule()
+	d.font("", 9)
+
+	items := 0
+	for _, item := range order.OrderItems {
+		items += item.Quantity
+		d.row(widths, aligns, "[   ]", item.Product.Name, fmt.Sprint(item.Quantity))
+	}
+	d.rule()
+
+	d.font("B", 9)
+	d.cell(150, "Items", "R", false)
+	d.cell(30, fmt.Sprint(items), "R", true)
+
+	return d.output(w)
+}
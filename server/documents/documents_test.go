@@ -0,0 +1,87 @@
+package documents
+
+import (
+	"bytes"
+	"flag"
+	"keylab/database/models"
+	"keylab/money"
+	"keylab/tax"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the snapshots in testdata")
+
+var seller = ParseSeller("Keylab", "1 Switch Street, London, EC1A 1BB, United Kingdom")
+
+func testOrder() models.Order {
+	address := &models.Address{Street: "221B Baker Street", City: "London", County: "Greater London", PostalCode: "NW1 6XE", Country: "United Kingdom"}
+	shippingMethodID := int64(2)
+
+	return models.Order{
+		ID:               42,
+		User:             models.User{Forename: "Alice", Surname: "Doe"},
+		OrderDate:        time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+		Status:           models.Pending,
+		Total:            money.MustParse("158.95"),
+		TaxTotal:         money.MustParse("24.83"),
+		TaxMode:          tax.Inclusive,
+		ShippingMethodID: &shippingMethodID,
+		ShippingMethod:   "Express",
+		ShippingCost:     money.MustParse("9.00"),
+		Currency:         money.GBP,
+		BillingAddress:   address,
+		ShippingAddress:  address,
+		OrderItems: []models.OrderedItem{
+			{Product: models.Product{Name: "Ducky One 2 Mini"}, Quantity: 1, Price: money.MustParse("99.00"), TaxName: "VAT", TaxRate: money.MustParseRate("20"), TaxAmount: money.MustParse("16.50")},
+			{Product: models.Product{Name: "Gaming mousepad"}, Quantity: 2, Price: money.MustParse("25.00"), TaxName: "VAT", TaxRate: money.MustParseRate("20"), TaxAmount: money.MustParse("8.33")},
+			{Product: models.Product{Name: "Keycap puller"}, Quantity: 1, Price: money.MustParse("0.95")},
+		},
+		Taxes: []models.OrderTax{
+			{Name: "VAT", Rate: money.MustParseRate("20"), Taxable: money.MustParse("124.17"), Amount: money.MustParse("24.83")},
+		},
+	}
+}
+
+// assertSnapshot compares the PDF with testdata/name, or rewrites it when run with -update.
+func assertSnapshot(t *testing.T, name string, pdf []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		assert.NoError(t, os.WriteFile(path, pdf, 0o644))
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(expected, pdf), "%s differs from the snapshot, run go test ./documents -update after checking the change", name)
+}
+
+func TestInvoice(t *testing.T) {
+	invoice := models.Invoice{OrderID: 42, Number: 7, IssuedAt: time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)}
+
+	var first, second bytes.Buffer
+	assert.NoError(t, Invoice(&first, seller, invoice, testOrder()))
+	assert.NoError(t, Invoice(&second, seller, invoice, testOrder()))
+	assert.Equal(t, first.Bytes(), second.Bytes(), "rendering is deterministic")
+
+	assert.Contains(t, first.String(), "(INV-000007)")
+	assert.Contains(t, first.String(), "(Includes VAT 20%)")
+	assertSnapshot(t, "invoice.pdf", first.Bytes())
+}
+
+func TestPackingSlip(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, PackingSlip(&out, seller, testOrder()))
+
+	assert.NotContains(t, out.String(), "99.00", "packing slips have no prices")
+	assertSnapshot(t, "packing_slip.pdf", out.Bytes())
+}
+
+func TestParseSeller(t *testing.T) {
+	assert.Equal(t, Seller{Name: "Keylab"}, ParseSeller("Keylab", ""))
+	assert.Equal(t, []string{"1 Switch Street", "London"}, ParseSeller("Keylab", " 1 Switch Street,, London ").Address)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"keylab/analytics"
+	"keylab/apperr"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultAnalyticsDays = 30
+	defaultTopLimit      = 10
+	maxTopLimit          = 100
+)
+
+// analyticsRange reads the from and to query parameters, dates or RFC 3339 timestamps like the
+// audit log's. It defaults to the last 30 days, today included.
+func analyticsRange(c echo.Context) (repositories.AnalyticsRange, *apperr.Error) {
+	now := time.Now()
+	r := repositories.AnalyticsRange{To: now}
+
+	to, err := parseDateParam(c.QueryParam("to"), true)
+	if err != nil {
+		return r, apperr.BadRequest("Invalid to date")
+	}
+	if to != nil {
+		r.To = *to
+	}
+
+	from, err := parseDateParam(c.QueryParam("from"), false)
+	if err != nil {
+		return r, apperr.BadRequest("Invalid from date")
+	}
+	if from != nil {
+		r.From = *from
+	} else {
+		r.From = analytics.Day.Start(r.To.Add(-time.Nanosecond)).AddDate(0, 0, 1-defaultAnalyticsDays)
+	}
+
+	if !r.From.Before(r.To) {
+		return r, apperr.BadRequest("The from date must be before the to date")
+	}
+
+	return r, nil
+}
+
+// topParams reads the rank (revenue by default) and limit of a top products or categories report.
+func topParams(c echo.Context) (analytics.Rank, int, *apperr.Error) {
+	rank := analytics.ByRevenue
+	if value := c.QueryParam("rank"); value != "" {
+		parsed, ok := analytics.ParseRank(value)
+		if !ok {
+			return "", 0, apperr.BadRequest("Invalid rank, use revenue or units")
+		}
+		rank = parsed
+	}
+
+	limit := defaultTopLimit
+	if value := c.QueryParam("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTopLimit {
+			return "", 0, apperr.BadRequest("Invalid limit")
+		}
+		limit = parsed
+	}
+
+	return rank, limit, nil
+}
+
+func analyticsError(c echo.Context, report string, err error) error {
+	slog.ErrorContext(c.Request().Context(), "Error fetching analytics", "report", report, "error", err)
+	return apperr.Render(c, apperr.Internal("Error fetching analytics", err))
+}
+
+// Get Sales Handler [GET /admin/analytics/sales]
+// 1. Groups the orders in the date range by day, week or month (interval, day by default).
+// 2. Returns the order count and revenue per period, including empty ones, and the totals with the
+// average order value.
+// 3. Cancelled and returned orders are not counted as sales.
+func (h *Handlers) GetSales(c echo.Context) error {
+	r, appErr := analyticsRange(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	interval := analytics.Day
+	if value := c.QueryParam("interval"); value != "" {
+		parsed, ok := analytics.ParseInterval(value)
+		if !ok {
+			return apperr.Render(c, apperr.BadRequest("Invalid interval, use day, week or month"))
+		}
+		interval = parsed
+	}
+
+	series, err := repositories.GetSalesByPeriod(r, interval, h.db(c))
+	if err != nil {
+		return analyticsError(c, "sales", err)
+	}
+
+	totals, err := repositories.GetSalesTotals(r, h.db(c))
+	if err != nil {
+		return analyticsError(c, "sales", err)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Sales fetched successfully", map[string]interface{}{
+		"from":     r.From,
+		"to":       r.To,
+		"interval": interval,
+		"totals":   totals,
+		"series":   series,
+	})
+}
+
+// Get Top Products Handler [GET /admin/analytics/products]
+// 1. Ranks the products sold in the date range by revenue or units (rank), returning limit of them.
+func (h *Handlers) GetTopProducts(c echo.Context) error {
+	r, appErr := analyticsRange(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	rank, limit, appErr := topParams(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	products, err := repositories.GetTopProducts(r, rank, limit, h.db(c))
+	if err != nil {
+		return analyticsError(c, "products", err)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Top products fetched successfully", products)
+}
+
+// Get Top Categories Handler [GET /admin/analytics/categories]
+// 1. Ranks the categories of the products sold in the date range by revenue or units (rank),
+// returning limit of them.
+func (h *Handlers) GetTopCategories(c echo.Context) error {
+	r, appErr := analyticsRange(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	rank, limit, appErr := topParams(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	categories, err := repositories.GetTopCategories(r, rank, limit, h.db(c))
+	if err != nil {
+		return analyticsError(c, "categories", err)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Top categories fetched successfully", categories)
+}
+
+// Get Customers Handler [GET /admin/analytics/customers]
+// 1. Counts the customers who ordered in the date range, split into new customers and those who
+// had ordered before.
+func (h *Handlers) GetCustomerAnalytics(c echo.Context) error {
+	r, appErr := analyticsRange(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	customers, err := repositories.GetCustomerCounts(r, h.db(c))
+	if err != nil {
+		return analyticsError(c, "customers", err)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Customers fetched successfully", customers)
+}
+
+// Get Order Statuses Handler [GET /admin/analytics/statuses]
+// 1. Counts the orders placed in the date range per status, with their revenue.
+func (h *Handlers) GetOrderStatusAnalytics(c echo.Context) error {
+	r, appErr := analyticsRange(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	statuses, err := repositories.GetStatusBreakdown(r, h.db(c))
+	if err != nil {
+		return analyticsError(c, "statuses", err)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Order statuses fetched successfully", statuses)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"keylab/analytics"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/money"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalytics(t *testing.T) {
+	h, testDB, alice, product := setupCartTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	bob := models.User{Forename: "Bob", Surname: "Doe", Email: "bob@example.com", Password: "pass123", PhoneNumber: "1234567891"}
+	assert.NoError(t, testDB.DB.Create(&bob).Error)
+
+	address := models.Address{UserID: alice.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "United Kingdom", Type: models.Billing}
+	assert.NoError(t, testDB.DB.Create(&address).Error)
+
+	createOrder := func(user models.User, date string, status models.OrderStatus, quantity int, currency money.Currency, rate string) {
+		orderDate, _ := time.ParseInLocation(time.DateTime, date+" 12:00:00", time.Local)
+		price := money.MustParse("25.00").Convert(money.MustParseRate(rate))
+		order := models.Order{
+			UserID:            user.ID,
+			Status:            status,
+			Total:             price.Mul(int64(quantity)),
+			Currency:          currency,
+			ExchangeRate:      money.MustParseRate(rate),
+			ShippingAddressID: address.ID,
+			BillingAddressID:  address.ID,
+			OrderDate:         orderDate,
+		}
+		assert.NoError(t, testDB.DB.Create(&order).Error)
+		assert.NoError(t, testDB.DB.Create(&models.OrderedItem{OrderID: order.ID, ProductID: product.ID, Quantity: quantity, Price: price, Currency: currency, ExchangeRate: order.ExchangeRate}).Error)
+	}
+
+	createOrder(alice, "2024-02-20", models.Delivered, 1, money.GBP, "1")
+	createOrder(alice, "2024-03-04", models.Delivered, 2, money.GBP, "1")
+	createOrder(bob, "2024-03-06", models.Shipped, 1, money.EUR, "1.2")
+	createOrder(bob, "2024-03-12", models.Cancelled, 4, money.GBP, "1")
+
+	get := func(handler echo.HandlerFunc, query string, data interface{}) int {
+		req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, handler(e.NewContext(req, rec)))
+
+		if data != nil && rec.Code == http.StatusOK {
+			response := struct {
+				Data interface{} `json:"data"`
+			}{Data: data}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec.Code
+	}
+
+	march := "from=2024-03-01&to=2024-03-31"
+
+	t.Run("Sales", func(t *testing.T) {
+		var sales struct {
+			Totals analytics.Totals  `json:"totals"`
+			Series []analytics.Point `json:"series"`
+		}
+		assert.Equal(t, http.StatusOK, get(h.GetSales, march+"&interval=week", &sales))
+
+		// The cancelled order is not a sale, and the euro order counts at its price in pounds.
+		assert.Equal(t, int64(2), sales.Totals.Orders)
+		assert.Equal(t, "75.00", sales.Totals.Revenue.String())
+		assert.Equal(t, "37.50", sales.Totals.AverageOrderValue.String())
+
+		assert.Len(t, sales.Series, 5)
+		assert.Equal(t, analytics.Point{Period: "2024-03-04", Orders: 2, Revenue: money.MustParse("75.00")}, sales.Series[1])
+		assert.Equal(t, int64(0), sales.Series[2].Orders)
+
+		assert.Equal(t, http.StatusBadRequest, get(h.GetSales, march+"&interval=year", nil))
+		assert.Equal(t, http.StatusBadRequest, get(h.GetSales, "from=2024-03-31&to=2024-03-01", nil))
+	})
+
+	t.Run("Top Products And Categories", func(t *testing.T) {
+		var products []analytics.Top
+		assert.Equal(t, http.StatusOK, get(h.GetTopProducts, march+"&rank=units", &products))
+		if assert.Len(t, products, 1) {
+			assert.Equal(t, product.ID, products[0].ID)
+			assert.Equal(t, int64(3), products[0].Units)
+			assert.Equal(t, "75.00", products[0].Revenue.String())
+		}
+
+		var categories []analytics.Top
+		assert.Equal(t, http.StatusOK, get(h.GetTopCategories, march, &categories))
+		assert.Len(t, categories, 1)
+
+		assert.Equal(t, http.StatusBadRequest, get(h.GetTopProducts, march+"&limit=0", nil))
+	})
+
+	t.Run("Customers", func(t *testing.T) {
+		var customers analytics.Customers
+		assert.Equal(t, http.StatusOK, get(h.GetCustomerAnalytics, march, &customers))
+		assert.Equal(t, analytics.Customers{New: 1, Returning: 1, Total: 2}, customers)
+	})
+
+	t.Run("Statuses", func(t *testing.T) {
+		var statuses []analytics.StatusCount
+		assert.Equal(t, http.StatusOK, get(h.GetOrderStatusAnalytics, march, &statuses))
+		assert.Len(t, statuses, 3)
+	})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"keylab/database/models"
+	"keylab/repositories"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// recordAudit appends a privileged action to the audit log. The actor is the authenticated user,
+// and only the fields that differ between before and after are stored. Failures are logged rather
+// than returned, since the action itself has already been committed.
+func (h *Handlers) recordAudit(c echo.Context, action string, entityType string, entityID int64, before interface{}, after interface{}) {
+	h.auditor(c).record(c.Request().Context(), action, entityType, entityID, before, after)
+}
+
+// auditor records actions on behalf of a request's user, also once the request has been answered,
+// such as from a background job it started.
+type auditor struct {
+	db        *gorm.DB
+	actorID   *int64
+	ipAddress string
+}
+
+func (h *Handlers) auditor(c echo.Context) auditor {
+	a := auditor{db: h.DB, ipAddress: c.RealIP()}
+	if user, ok := c.Get("user").(models.User); ok {
+		a.actorID = &user.ID
+	}
+
+	return a
+}
+
+func (a auditor) record(ctx context.Context, action string, entityType string, entityID int64, before interface{}, after interface{}) {
+	entry := models.AuditLog{
+		ActorID:    a.actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IPAddress:  a.ipAddress,
+	}
+
+	repositories.RecordAudit(a.db.WithContext(ctx), entry, before, after)
+}
+
+// parseDateParam accepts either a date (2006-01-02) or an RFC 3339 timestamp. A bare date used as
+// an upper bound covers the whole day.
+func parseDateParam(value string, endOfRange bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+
+	parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	if endOfRange {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+
+	return &parsed, nil
+}
+
+// GetAuditLogs Handler [GET /admin/audit]
+// 1. Parses the optional actor_id, entity_type, entity_id, from and to filters.
+// 2. Fetches matching audit log entries, newest first, with pagination.
+// 3. Returns status 200 with the entries and pagination metadata if successful.
+// 4. Returns status 400 if a filter is invalid.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetAuditLogs(c echo.Context) error {
+	var filter repositories.AuditLogFilter
+
+	if actorID := c.QueryParam("actor_id"); actorID != "" {
+		id, err := convertToInt64(actorID)
+		if err != nil {
+			return jsonResponse(c, http.StatusBadRequest, "Invalid actor ID")
+		}
+		filter.ActorID = &id
+	}
+
+	if entityID := c.QueryParam("entity_id"); entityID != "" {
+		id, err := convertToInt64(entityID)
+		if err != nil {
+			return jsonResponse(c, http.StatusBadRequest, "Invalid entity ID")
+		}
+		filter.EntityID = &id
+	}
+
+	filter.EntityType = c.QueryParam("entity_type")
+
+	from, err := parseDateParam(c.QueryParam("from"), false)
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid from date")
+	}
+	filter.From = from
+
+	to, err := parseDateParam(c.QueryParam("to"), true)
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid to date")
+	}
+	filter.To = to
+
+	page, perPage, offset := getPaginationParams(c)
+
+	entries, total, err := repositories.GetAuditLogs(filter, perPage, offset, h.db(c))
+	if err != nil {
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching audit logs")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Audit logs fetched successfully", map[string]interface{}{
+		"audit_logs": entries,
+		"metadata":   generatePaginationResponse(page, perPage, int(total)),
+	})
+}
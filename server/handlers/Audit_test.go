@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	db "keylab/database"
+	"keylab/database/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogs(t *testing.T) {
+	testDB := db.SetupTestDB(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	h := &Handlers{DB: testDB.DB}
+	e := echo.New()
+
+	admin := models.User{Forename: "Admin", Surname: "User", Email: "admin@example.com", Password: "Password123"}
+	assert.NoError(t, testDB.DB.Create(&admin).Error)
+
+	category := models.ProductCategory{Name: "Keycaps", Slug: "keycaps", Description: "Keycap sets"}
+	assert.NoError(t, testDB.DB.Create(&category).Error)
+
+	t.Run("Update Category Is Recorded", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{"name": "Keycap Sets", "slug": "keycaps", "description": "Keycap sets"})
+		req := httptest.NewRequest(http.MethodPut, "/categories/keycaps", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("slug")
+		c.SetParamValues("keycaps")
+		c.Set("user", admin)
+
+		assert.NoError(t, h.UpdateCategory(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var entry models.AuditLog
+		assert.NoError(t, testDB.DB.Where("entity_type = ? AND entity_id = ?", models.AuditEntityCategory, category.ID).First(&entry).Error)
+		assert.Equal(t, "category.update", entry.Action)
+		assert.Equal(t, admin.ID, *entry.ActorID)
+		assert.JSONEq(t, `{"name":"Keycaps"}`, string(entry.OldValues))
+		assert.Contains(t, string(entry.NewValues), `"name":"Keycap Sets"`)
+	})
+
+	t.Run("Query By Actor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/admin/audit?actor_id=%d&entity_type=category&from=2000-01-01", admin.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.NoError(t, h.GetAuditLogs(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data struct {
+				AuditLogs []models.AuditLog `json:"audit_logs"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response.Data.AuditLogs, 1)
+	})
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/audit?from=yesterday", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		assert.NoError(t, h.GetAuditLogs(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
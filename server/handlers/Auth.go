@@ -1,17 +1,22 @@
 package handlers
 
 import (
-	"fmt"
 	"keylab/database/models"
+	"keylab/logging"
+	"keylab/metrics"
 	"keylab/repositories"
 	"keylab/utils"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
 
-const SessionName = "keylab"
+const (
+	SessionName    = "keylab"
+	CSRFSessionKey = "csrf_token"
+	CSRFHeader     = "X-CSRF-Token"
+)
 
 // Login Handler [POST /auth/login]
 // 1. Parsing user input from the request body, and validating it.
@@ -29,25 +34,26 @@ func (h *Handlers) Login(c echo.Context) error {
 	}
 
 	if err := user.Validate("Email", "Password"); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
 	// Checks if user exists, returns status 401 if not.
-	validUser, err := repositories.FindUserByEmail(user.Email, h.DB)
+	validUser, err := repositories.FindUserByEmail(user.Email, h.db(c))
 	if err != nil {
-		log.Printf("Error in database while finding user by email: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error in database while finding user by email", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error checking existing user")
 	}
 
 	// Compares the password hash against the user's password hash.
 	if validUser == nil || utils.ComparePasswordHash(user.Password, validUser.Password) != nil {
+		metrics.FailedLogins.Inc()
 		return jsonResponse(c, http.StatusUnauthorized, "Invalid email or password")
 	}
 
 	// Creates or gets session for the user.
 	session, err := h.SessionStore.Get(c.Request(), SessionName)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error creating session", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating session")
 	}
 
@@ -57,7 +63,7 @@ func (h *Handlers) Login(c echo.Context) error {
 		return jsonResponse(c, http.StatusInternalServerError, "Error saving session")
 	}
 
-	fmt.Println(session)
+	slog.InfoContext(c.Request().Context(), "User logged in", "user_id", validUser.ID, "session", logging.RedactValues(session.Values))
 
 	return jsonResponse(c, http.StatusOK, "Logged in successfully!")
 
@@ -79,14 +85,14 @@ func (h *Handlers) Register(c echo.Context) error {
 	}
 
 	if err := user.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
 	// Checks if user exists, returns status 401 if not.
-	validUser, err := repositories.FindUserByEmail(user.Email, h.DB)
+	validUser, err := repositories.FindUserByEmail(user.Email, h.db(c))
 	if err != nil {
 		// If error occurs while checking for existing user, return 500 and output error message to terminal.
-		log.Printf("Error checking existing user: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error checking existing user", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error checking existing user")
 	}
 
@@ -112,13 +118,13 @@ func (h *Handlers) Register(c echo.Context) error {
 	user.Password = hashedPassword
 
 	// Creates the user in the database.
-	if err := h.DB.Create(&user).Error; err != nil {
+	if err := h.db(c).Create(&user).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating user")
 	}
 
 	session, err := h.SessionStore.Get(c.Request(), SessionName)
 	if err != nil {
-		log.Printf("Error creating session: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error creating session", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating session")
 	}
 	initiateSession(session, user.ID)
@@ -146,7 +152,7 @@ func (h *Handlers) Logout(c echo.Context) error {
 	// Sets the session's MaxAge to -1 essentially deleting the session.
 	session.Options.MaxAge = -1
 	if err := session.Save(c.Request(), c.Response()); err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(c.Request().Context(), "Error saving session", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error saving session")
 	}
 
@@ -171,7 +177,7 @@ func (h *Handlers) ValidateSession(c echo.Context) error {
 		return jsonResponse(c, http.StatusUnauthorized, "Invalid session data")
 	}
 
-	user, err := repositories.FindUserByID(userID, h.DB)
+	user, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusUnauthorized, "Unauthorized")
 	}
@@ -180,12 +186,45 @@ func (h *Handlers) ValidateSession(c echo.Context) error {
 	return jsonResponse(c, http.StatusOK, "Valid session", user)
 }
 
+// GetCSRFToken Handler [GET /auth/csrf]
+// 1. Gets or creates the session for the client.
+// 2. Generates a CSRF token if the session does not have one yet.
+// 3. Saves the session.
+// 4. Returns status 200 with the token, which must be sent back in the X-CSRF-Token header.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetCSRFToken(c echo.Context) error {
+	session, err := h.SessionStore.Get(c.Request(), SessionName)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error retrieving session", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error retrieving session")
+	}
+
+	token, ok := session.Values[CSRFSessionKey].(string)
+	if !ok || token == "" {
+		token, err = utils.GenerateCSRFToken()
+		if err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error generating CSRF token", "error", err)
+			return jsonResponse(c, http.StatusInternalServerError, "Error generating CSRF token")
+		}
+
+		session.Values[CSRFSessionKey] = token
+		if err := session.Save(c.Request(), c.Response()); err != nil {
+			return jsonResponse(c, http.StatusInternalServerError, "Error saving session")
+		}
+	}
+
+	return jsonResponse(c, http.StatusOK, "CSRF token fetched successfully", map[string]interface{}{
+		"csrf_token": token,
+	})
+}
+
 // REMOVE LATER
 
 func (h *Handlers) TestPermission(c echo.Context) error {
 	user := c.Get("user").(models.User)
 
-	fmt.Println(user)
+	slog.DebugContext(c.Request().Context(), "Test permission", "user_id", user.ID)
 
 	return jsonResponse(c, http.StatusOK, "Test Permission")
 }
@@ -206,14 +245,14 @@ func (h *Handlers) GetAllUsers(c echo.Context) error {
 	page, perPage, offset := getPaginationParams(c)
 	order := getSortOrder(c)
 
-	if err := h.DB.Order(order).Limit(perPage).Offset(offset).Find(&users).Error; err != nil {
-		log.Printf("Error fetching users: %v", err)
+	if err := h.db(c).Order(order).Limit(perPage).Offset(offset).Find(&users).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching users", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching users")
 	}
 
 	var total int64
-	if err := h.DB.Preload("Role").Model(&models.User{}).Count(&total).Error; err != nil {
-		log.Printf("Error counting users: %v", err)
+	if err := h.db(c).Preload("Role").Model(&models.User{}).Count(&total).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error counting users", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error counting users")
 	}
 
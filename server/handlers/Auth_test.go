@@ -20,7 +20,7 @@ import (
 func TestLogin(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
@@ -157,7 +157,7 @@ func TestLogin(t *testing.T) {
 func TestRegister(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
@@ -282,7 +282,7 @@ func TestRegister(t *testing.T) {
 func TestLogout(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"errors"
+	"keylab/apperr"
+	"keylab/config"
 	"keylab/database/models"
+	"keylab/metrics"
 	"keylab/repositories"
-	"log"
+	"keylab/tax"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -25,9 +29,9 @@ func (h *Handlers) ListCartItems(c echo.Context) error {
 
 	user := c.Get("user").(models.User)
 
-	cartItems, err := repositories.GetCartItemsByUserID(user.ID, h.DB)
+	cartItems, err := repositories.GetCartItemsByUserID(user.ID, h.db(c))
 	if err != nil {
-		log.Printf("Error fetching cart items: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching cart items", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching cart items")
 	}
 
@@ -35,6 +39,16 @@ func (h *Handlers) ListCartItems(c echo.Context) error {
 		return jsonResponse(c, http.StatusNotFound, "No cart items found for the user")
 	}
 
+	pricing, appErr := h.requestPricing(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := repositories.PriceCartItems(cartItems, pricing, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error pricing cart items", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching prices")
+	}
+
 	return jsonResponse(c, http.StatusOK, "Cart items fetched successfully", cartItems)
 }
 
@@ -54,10 +68,10 @@ func (h *Handlers) AddCartItem(c echo.Context) error {
 	}
 
 	if err := cartItem.Validate("ProductID", "Quantity"); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
-	product, err := repositories.GetProductByID(cartItem.ProductID, h.DB)
+	product, err := repositories.GetProductByID(cartItem.ProductID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
@@ -70,18 +84,18 @@ func (h *Handlers) AddCartItem(c echo.Context) error {
 	cartItem.UserID = user.ID
 
 	var existingCartItem models.CartItems
-	if err := h.DB.Where("user_id = ? AND product_id = ?", cartItem.UserID, cartItem.ProductID).First(&existingCartItem).Error; err == nil {
+	if err := h.db(c).Where("user_id = ? AND product_id = ?", cartItem.UserID, cartItem.ProductID).First(&existingCartItem).Error; err == nil {
 		existingCartItem.Quantity += cartItem.Quantity
 		if existingCartItem.Quantity > product.Stock {
 			return jsonResponse(c, http.StatusBadRequest, "Insufficient stock for the updated quantity")
 		}
 
-		if err := h.DB.Save(&existingCartItem).Error; err != nil {
-			log.Printf("Error updating cart item: %v", err)
+		if err := h.db(c).Save(&existingCartItem).Error; err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error updating cart item", "error", err)
 			return jsonResponse(c, http.StatusInternalServerError, "Error updating cart item")
 		}
 
-		existingCartItem, err = repositories.GetCartItemByID(existingCartItem.ID, h.DB)
+		existingCartItem, err = repositories.GetCartItemByID(existingCartItem.ID, h.db(c))
 		if err != nil {
 			return jsonResponse(c, http.StatusInternalServerError, "Error fetching updated cart item")
 		}
@@ -89,12 +103,22 @@ func (h *Handlers) AddCartItem(c echo.Context) error {
 		return jsonResponse(c, http.StatusOK, "Cart item updated successfully", existingCartItem)
 	}
 
-	if err := h.DB.Preload("Product").Create(&cartItem).Error; err != nil {
-		log.Printf("Error adding cart item: %v", err)
+	var otherItems int64
+	if err := h.db(c).Model(&models.CartItems{}).Where("user_id = ?", cartItem.UserID).Limit(1).Count(&otherItems).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error counting cart items", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error adding cart item")
+	}
+
+	if err := h.db(c).Preload("Product").Create(&cartItem).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error adding cart item", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error adding cart item")
 	}
 
-	cartItem, err = repositories.GetCartItemByID(cartItem.ID, h.DB)
+	if otherItems == 0 {
+		metrics.CartsCreated.Inc()
+	}
+
+	cartItem, err = repositories.GetCartItemByID(cartItem.ID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching cart item")
 	}
@@ -118,7 +142,7 @@ func (h *Handlers) DeleteCartItem(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid cart item ID")
 	}
 
-	cartItem, err = repositories.GetCartItemByID(idParam, h.DB)
+	cartItem, err = repositories.GetCartItemByID(idParam, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Cart item not found")
 	}
@@ -127,8 +151,8 @@ func (h *Handlers) DeleteCartItem(c echo.Context) error {
 		return jsonResponse(c, http.StatusForbidden, "You are not authorized to delete this cart item")
 	}
 
-	if err := h.DB.Delete(&cartItem).Error; err != nil {
-		log.Printf("Error deleting cart item: %v", err)
+	if err := h.db(c).Delete(&cartItem).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting cart item", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error deleting cart item")
 	}
 
@@ -153,7 +177,7 @@ func (h *Handlers) UpdateCartItemQuantity(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid cart item ID")
 	}
 
-	cartItem, err = repositories.GetCartItemByID(idParam, h.DB)
+	cartItem, err = repositories.GetCartItemByID(idParam, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Cart item not found")
 	}
@@ -166,7 +190,7 @@ func (h *Handlers) UpdateCartItemQuantity(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid input for updating cart item")
 	}
 
-	product, err := repositories.GetProductByID(cartItem.ProductID, h.DB)
+	product, err := repositories.GetProductByID(cartItem.ProductID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
@@ -176,11 +200,11 @@ func (h *Handlers) UpdateCartItemQuantity(c echo.Context) error {
 	}
 
 	if err := cartItem.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
-	if err := h.DB.Save(&cartItem).Error; err != nil {
-		log.Printf("Error updating cart item: %v", err)
+	if err := h.db(c).Save(&cartItem).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error updating cart item", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating cart item")
 	}
 
@@ -192,13 +216,15 @@ type CheckoutRequest struct {
 	ShippingAddressID  int64           `json:"shipping_address_id"`
 	NewBillingAddress  *models.Address `json:"new_billing_address"`
 	NewShippingAddress *models.Address `json:"new_shipping_address"`
+	ShippingMethodID   int64           `json:"shipping_method_id"`
 }
 
 // Checkout [POST /cart/checkout]
 // 1. Retrieves the addresses from the request and handles it as expected.
-// 2. Fetches Cart Items by the User and Calculates Total
+// 2. Fetches Cart Items by the User and Calculates Total, with tax and the chosen shipping method
 // 3. Creates a DB Transaction and handles creating order
-// 4. Returns order
+// 4. Alerts staff about the products the order takes to their low stock threshold
+// 5. Returns order
 
 func (h *Handlers) CheckoutCart(c echo.Context) error {
 	user := c.Get("user").(models.User)
@@ -208,17 +234,22 @@ func (h *Handlers) CheckoutCart(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid request data")
 	}
 
-	billingAddress, err := repositories.HandleAddress(user.ID, req.BillingAddressID, req.NewBillingAddress, models.Billing, h.DB)
+	pricing, appErr := h.requestPricing(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	billingAddress, err := repositories.HandleAddress(user.ID, req.BillingAddressID, req.NewBillingAddress, models.Billing, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to handle billing address")
 	}
 
-	shippingAddress, err := repositories.HandleAddress(user.ID, req.ShippingAddressID, req.NewShippingAddress, models.Shipping, h.DB)
+	shippingAddress, err := repositories.HandleAddress(user.ID, req.ShippingAddressID, req.NewShippingAddress, models.Shipping, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to handle shipping address")
 	}
 
-	cartItems, err := repositories.GetCartItemsByUserID(user.ID, h.DB)
+	cartItems, err := repositories.GetCartItemsByUserID(user.ID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching cart items")
 	}
@@ -226,8 +257,49 @@ func (h *Handlers) CheckoutCart(c echo.Context) error {
 		return jsonResponse(c, http.StatusNotFound, "No cart items found for the user")
 	}
 
-	total := repositories.CalculateTotal(cartItems, h.DB)
-	transaction := h.DB.Begin()
+	if err := repositories.PriceCartItems(cartItems, pricing, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error pricing cart items", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching prices")
+	}
+
+	taxes, err := h.taxCalculator().Calculate(c.Request().Context(), tax.Request{
+		Address: tax.Address{Country: shippingAddress.Country, County: shippingAddress.County},
+		Mode:    config.Get().TAX_MODE,
+		Lines:   repositories.TaxLines(cartItems),
+	})
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error calculating tax", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error calculating tax")
+	}
+
+	shippingOptions, err := repositories.GetShippingOptions(*shippingAddress, repositories.ShippingCart(cartItems), pricing, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching shipping options", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching shipping options")
+	}
+
+	zonesConfigured := true
+	if len(shippingOptions) == 0 {
+		if zonesConfigured, err = repositories.HasShippingZones(h.db(c)); err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error fetching shipping zones", "error", err)
+			return jsonResponse(c, http.StatusInternalServerError, "Error fetching shipping options")
+		}
+	}
+
+	shippingOption, appErr := chooseShipping(shippingOptions, zonesConfigured, req.ShippingMethodID)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	total := repositories.CalculateTotal(cartItems, h.db(c))
+	if taxes.Mode == tax.Exclusive {
+		total = total.Add(taxes.Total)
+	}
+	if shippingOption != nil {
+		total = total.Add(shippingOption.Cost)
+	}
+
+	transaction := h.db(c).Begin()
 	if transaction.Error != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to initiate transaction")
 	}
@@ -242,27 +314,72 @@ func (h *Handlers) CheckoutCart(c echo.Context) error {
 		UserID:            user.ID,
 		Status:            models.Pending,
 		Total:             total,
+		TaxTotal:          taxes.Total,
+		TaxMode:           taxes.Mode,
+		Currency:          pricing.Currency,
+		ExchangeRate:      pricing.Rate,
 		ShippingAddressID: shippingAddress.ID,
 		BillingAddressID:  billingAddress.ID,
 		OrderDate:         time.Now(),
 	}
+	if shippingOption != nil {
+		order.ShippingMethodID = &shippingOption.MethodID
+		order.ShippingMethod = shippingOption.Name
+		order.ShippingCost = shippingOption.Cost
+	}
 
 	if err := transaction.Create(&order).Error; err != nil {
 		transaction.Rollback()
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating order")
 	}
 
-	for _, item := range cartItems {
-		if err := transaction.Model(&models.Product{}).Where("id = ?", item.ProductID).UpdateColumn("stock", gorm.Expr("stock - ?", item.Quantity)).Error; err != nil {
+	for _, summary := range taxes.Taxes {
+		orderTax := models.OrderTax{
+			OrderID: order.ID,
+			Name:    summary.Name,
+			Rate:    summary.Rate,
+			Taxable: summary.Taxable,
+			Amount:  summary.Amount,
+		}
+		if err := transaction.Create(&orderTax).Error; err != nil {
+			transaction.Rollback()
+			return jsonResponse(c, http.StatusInternalServerError, "Error creating order")
+		}
+	}
+
+	lowStockThreshold := config.Get().LOW_STOCK_THRESHOLD
+	productIDs := make([]int64, len(cartItems))
+	for i, item := range cartItems {
+		productIDs[i] = item.ProductID
+	}
+
+	if err := repositories.ResetLowStockAlerts(productIDs, lowStockThreshold, transaction); err != nil {
+		transaction.Rollback()
+		return jsonResponse(c, http.StatusInternalServerError, "Error updating product stock")
+	}
+
+	stockOuts := 0
+	for i, item := range cartItems {
+		stock, err := repositories.TakeStock(item.ProductID, item.Quantity, transaction)
+		if err != nil {
 			transaction.Rollback()
 			return jsonResponse(c, http.StatusInternalServerError, "Error updating product stock")
 		}
 
+		if stock <= 0 && stock+item.Quantity > 0 {
+			stockOuts++
+		}
+
 		orderItem := models.OrderedItem{
-			OrderID:   order.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Product.Price,
+			OrderID:      order.ID,
+			ProductID:    item.ProductID,
+			Quantity:     item.Quantity,
+			Price:        item.Product.Price,
+			TaxName:      taxes.Lines[i].Name,
+			TaxRate:      taxes.Lines[i].Rate,
+			TaxAmount:    taxes.Lines[i].Amount,
+			Currency:     pricing.Currency,
+			ExchangeRate: pricing.Rate,
 		}
 		if err := transaction.Create(&orderItem).Error; err != nil {
 			transaction.Rollback()
@@ -270,6 +387,12 @@ func (h *Handlers) CheckoutCart(c echo.Context) error {
 		}
 	}
 
+	lowStock, err := repositories.ClaimLowStockAlerts(productIDs, lowStockThreshold, transaction)
+	if err != nil {
+		transaction.Rollback()
+		return jsonResponse(c, http.StatusInternalServerError, "Error updating product stock")
+	}
+
 	if err := transaction.Where("user_id = ?", user.ID).Delete(&models.CartItems{}).Error; err != nil {
 		transaction.Rollback()
 		return jsonResponse(c, http.StatusInternalServerError, "Error clearing cart")
@@ -279,6 +402,13 @@ func (h *Handlers) CheckoutCart(c echo.Context) error {
 		return jsonResponse(c, http.StatusInternalServerError, "Error processing checkout")
 	}
 
+	metrics.Checkouts.Inc()
+	metrics.StockOuts.Add(float64(stockOuts))
+
+	if len(lowStock) > 0 {
+		h.notifyLowStock(c.Request().Context(), lowStock)
+	}
+
 	return jsonResponse(c, http.StatusOK, "Order confirmed")
 }
 
@@ -288,7 +418,7 @@ func (h *Handlers) GetUserOrderDetails(c echo.Context) error {
 	orderID := c.Param("id")
 
 	var order models.Order
-	if err := h.DB.Preload("ShippingAddress").Preload("BillingAddress").Where("id = ? AND user_id = ?", orderID, user.ID).First(&order).Error; err != nil {
+	if err := h.db(c).Preload("ShippingAddress").Preload("BillingAddress").Preload("Taxes").Preload("Invoice").Where("id = ? AND user_id = ?", orderID, user.ID).First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return jsonResponse(c, http.StatusNotFound, "Order not found!")
 		}
@@ -296,7 +426,7 @@ func (h *Handlers) GetUserOrderDetails(c echo.Context) error {
 	}
 
 	var orderedItems []models.OrderedItem
-	if err := h.DB.Where("order_id = ?", orderID).Find(&orderedItems).Error; err != nil {
+	if err := h.db(c).Where("order_id = ?", orderID).Find(&orderedItems).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to fetch ordered items")
 	}
 
@@ -333,19 +463,22 @@ func (h *Handlers) UpdateOrderStatus(c echo.Context) error {
 	}
 
 	var order models.Order
-	if err := h.DB.Preload("ShippingAddress").Preload("BillingAddress").Where("id = ?", orderID).First(&order).Error; err != nil {
+	if err := h.db(c).Preload("ShippingAddress").Preload("BillingAddress").Where("id = ?", orderID).First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return jsonResponse(c, http.StatusNotFound, "Order not found")
 		}
 		return jsonResponse(c, http.StatusInternalServerError, "Internal server error")
 	}
 
+	before := order
 	order.Status = models.OrderStatus(status)
 
-	if err := h.DB.Save(&order).Error; err != nil {
+	if err := h.db(c).Save(&order).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating order status")
 	}
 
+	h.recordAudit(c, "order.status_update", models.AuditEntityOrder, order.ID, before, order)
+
 	return jsonResponse(c, http.StatusOK, "Order status updated successfully", order)
 }
 
@@ -361,33 +494,33 @@ func (h *Handlers) GetAllOrders(c echo.Context) error {
 	status := c.QueryParam("status")
 
 	var orders []models.Order
-	query := h.DB.Preload("User").Preload("ShippingAddress").Preload("BillingAddress").Order(order)
+	query := h.db(c).Preload("User").Preload("ShippingAddress").Preload("BillingAddress").Order(order)
 
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
 	if err := query.Limit(perPage).Offset(offset).Find(&orders).Error; err != nil {
-		log.Printf("Error fetching orders: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching orders", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching orders")
 	}
 
 	var total int64
-	countQuery := h.DB.Model(&models.Order{})
+	countQuery := h.db(c).Model(&models.Order{})
 	if status != "" {
 		countQuery = countQuery.Where("status = ?", status)
 	}
 
 	if err := countQuery.Count(&total).Error; err != nil {
-		log.Printf("Error counting orders: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error counting orders", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error counting orders")
 	}
 
 	var processedOrders []map[string]interface{}
 	for _, order := range orders {
 		var orderedItems []models.OrderedItem
-		if err := h.DB.Preload("Product").Where("order_id = ?", order.ID).Find(&orderedItems).Error; err != nil {
-			log.Printf("Error fetching ordered items for order %d: %v", order.ID, err)
+		if err := h.db(c).Preload("Product").Where("order_id = ?", order.ID).Find(&orderedItems).Error; err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error fetching ordered items", "order_id", order.ID, "error", err)
 			continue
 		}
 
@@ -416,17 +549,17 @@ func (h *Handlers) GetOrderDetails(c echo.Context) error {
 	}
 
 	var order models.Order
-	if err := h.DB.Preload("User").Preload("ShippingAddress").Preload("BillingAddress").Where("id = ?", orderID).First(&order).Error; err != nil {
+	if err := h.db(c).Preload("User").Preload("ShippingAddress").Preload("BillingAddress").Preload("Taxes").Preload("Invoice").Where("id = ?", orderID).First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return jsonResponse(c, http.StatusNotFound, "Order not found")
 		}
-		log.Printf("Error fetching order details: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching order details", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching order details")
 	}
 
 	var orderedItems []models.OrderedItem
-	if err := h.DB.Preload("Product").Where("order_id = ?", orderID).Find(&orderedItems).Error; err != nil {
-		log.Printf("Error fetching ordered items: %v", err)
+	if err := h.db(c).Preload("Product").Where("order_id = ?", orderID).Find(&orderedItems).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching ordered items", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to fetch ordered items")
 	}
 
@@ -450,7 +583,7 @@ func (h *Handlers) GetUserOrders(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
 
-	user, err := repositories.FindUserByID(userID, h.DB)
+	user, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "User not found")
 	}
@@ -461,33 +594,33 @@ func (h *Handlers) GetUserOrders(c echo.Context) error {
 	status := c.QueryParam("status")
 
 	var orders []models.Order
-	query := h.DB.Preload("User").Preload("ShippingAddress").Preload("BillingAddress").Where("user_id = ?", user.ID).Order(order)
+	query := h.db(c).Preload("User").Preload("ShippingAddress").Preload("BillingAddress").Where("user_id = ?", user.ID).Order(order)
 
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
 	if err := query.Limit(perPage).Offset(offset).Find(&orders).Error; err != nil {
-		log.Printf("Error fetching user orders: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching user orders", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching user orders")
 	}
 
 	var total int64
-	countQuery := h.DB.Model(&models.Order{}).Where("user_id = ?", user.ID)
+	countQuery := h.db(c).Model(&models.Order{}).Where("user_id = ?", user.ID)
 	if status != "" {
 		countQuery = countQuery.Where("status = ?", status)
 	}
 
 	if err := countQuery.Count(&total).Error; err != nil {
-		log.Printf("Error counting user orders: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error counting user orders", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error counting user orders")
 	}
 
 	var processedOrders []map[string]interface{}
 	for _, order := range orders {
 		var orderedItems []models.OrderedItem
-		if err := h.DB.Preload("Product").Where("order_id = ?", order.ID).Find(&orderedItems).Error; err != nil {
-			log.Printf("Error fetching ordered items for order %d: %v", order.ID, err)
+		if err := h.db(c).Preload("Product").Where("order_id = ?", order.ID).Find(&orderedItems).Error; err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error fetching ordered items", "order_id", order.ID, "error", err)
 			continue
 		}
 
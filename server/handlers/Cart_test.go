@@ -6,6 +6,7 @@ import (
 	"fmt"
 	db "keylab/database"
 	"keylab/database/models"
+	"keylab/money"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -33,7 +34,7 @@ func setupCartTest(t *testing.T) (*Handlers, *db.TestDB, models.User, models.Pro
 		Name:        "Mousepad",
 		Slug:        "mousepad",
 		Description: "Gaming mousepad",
-		Price:       25.0,
+		Price:       money.MustParse("25.00"),
 		Stock:       10,
 		CategoryID:  category.ID,
 	}
@@ -207,7 +208,7 @@ func TestUpdateOrderStatus(t *testing.T) {
 	order := models.Order{
 		UserID:            user.ID,
 		Status:            models.Pending,
-		Total:             50,
+		Total:             money.MustParse("50.00"),
 		ShippingAddressID: shipping.ID,
 		BillingAddressID:  billing.ID,
 	}
@@ -236,7 +237,7 @@ func TestGetUserOrderDetails(t *testing.T) {
 	assert.NoError(t, testDB.DB.Create(&shipping).Error)
 	assert.NoError(t, testDB.DB.Create(&billing).Error)
 
-	order := models.Order{UserID: user.ID, ShippingAddressID: shipping.ID, BillingAddressID: billing.ID, Status: models.Pending, Total: 100.0}
+	order := models.Order{UserID: user.ID, ShippingAddressID: shipping.ID, BillingAddressID: billing.ID, Status: models.Pending, Total: money.MustParse("100.00")}
 	assert.NoError(t, testDB.DB.Create(&order).Error)
 
 	e := echo.New()
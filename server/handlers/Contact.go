@@ -14,10 +14,10 @@ func (h *Handlers) ContactUs(c echo.Context) error {
 	}
 
 	if err := ContactUsRequest.Validate(); err != nil {
-		return jsonResponse(c, 400, err.Error())
+		return validationError(c, err)
 	}
 
-	if err := h.DB.Create(&ContactUsRequest).Error; err != nil {
+	if err := h.db(c).Create(&ContactUsRequest).Error; err != nil {
 		return jsonResponse(c, 500, "Failed to save contact us request")
 	}
 
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/database/models"
+	"keylab/money"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// currencyHeader selects the currency prices are shown in when there is no currency query
+// parameter.
+const currencyHeader = "X-Currency"
+
+type exchangeRateRequest struct {
+	Rate money.Rate `json:"rate" form:"rate"`
+}
+
+type productPriceRequest struct {
+	Price money.Money `json:"price" form:"price"`
+}
+
+// requestPricing reads the currency from the currency query parameter or the X-Currency header,
+// the store's base currency when neither is set.
+func (h *Handlers) requestPricing(c echo.Context) (repositories.Pricing, *apperr.Error) {
+	c.Response().Header().Add(echo.HeaderVary, currencyHeader)
+
+	base := config.Get().BASE_CURRENCY
+
+	code := c.QueryParam("currency")
+	if code == "" {
+		code = c.Request().Header.Get(currencyHeader)
+	}
+	if code == "" {
+		code = string(base)
+	}
+
+	currency, err := money.ParseCurrency(code)
+	if err != nil {
+		return repositories.Pricing{}, apperr.BadRequest("Unsupported currency")
+	}
+
+	pricing, err := repositories.GetPricing(base, currency, h.db(c))
+	if errors.Is(err, repositories.ErrCurrencyUnavailable) {
+		return repositories.Pricing{}, apperr.BadRequest("Currency not available")
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching exchange rate", "error", err)
+		return repositories.Pricing{}, apperr.Internal("Error fetching exchange rate", err)
+	}
+
+	return pricing, nil
+}
+
+// priceProducts shows products in the request's currency.
+func (h *Handlers) priceProducts(c echo.Context, products []models.Product) *apperr.Error {
+	pricing, appErr := h.requestPricing(c)
+	if appErr != nil {
+		return appErr
+	}
+
+	if err := repositories.PriceProducts(products, pricing, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error pricing products", "error", err)
+		return apperr.Internal("Error fetching prices", err)
+	}
+
+	return nil
+}
+
+// priceCurrency reads the :currency param. The base currency has no exchange rate or price
+// overrides, product prices are entered in it.
+func priceCurrency(c echo.Context) (money.Currency, bool) {
+	currency, err := money.ParseCurrency(c.Param("currency"))
+	return currency, err == nil && currency != config.Get().BASE_CURRENCY
+}
+
+// Get Currencies Handler [GET /currencies]
+// 1. Fetches every exchange rate.
+// 2. Returns status 200 with the base currency and every currency prices can be shown in.
+// 3. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetCurrencies(c echo.Context) error {
+	var exchangeRates []models.ExchangeRate
+	if err := h.db(c).Order("currency").Find(&exchangeRates).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching exchange rates", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching exchange rates")
+	}
+
+	base := config.Get().BASE_CURRENCY
+	currencies := []money.Currency{base}
+	for _, exchangeRate := range exchangeRates {
+		currencies = append(currencies, exchangeRate.Currency)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Currencies fetched successfully", map[string]interface{}{
+		"base":       base,
+		"currencies": currencies,
+	})
+}
+
+// Get Exchange Rates Handler [GET /admin/exchange-rates]
+// 1. Fetches every exchange rate.
+// 2. Returns status 200 with the exchange rates.
+// 3. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetExchangeRates(c echo.Context) error {
+	var exchangeRates []models.ExchangeRate
+	if err := h.db(c).Order("currency").Find(&exchangeRates).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching exchange rates", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching exchange rates")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Exchange rates fetched successfully", exchangeRates)
+}
+
+// Save Exchange Rate Handler [PUT /admin/exchange-rates/:currency]
+// 1. Validates the currency, which cannot be the base currency.
+// 2. Creates the exchange rate, or replaces the existing one for the currency.
+// 3. Returns status 200 with the exchange rate if successful.
+// 4. Returns status 400 if the currency or rate is invalid.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) SaveExchangeRate(c echo.Context) error {
+	currency, ok := priceCurrency(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid currency")
+	}
+
+	var request exchangeRateRequest
+	if err := c.Bind(&request); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for exchange rate")
+	}
+
+	var exchangeRate models.ExchangeRate
+	err := h.db(c).Where("currency = ?", currency).First(&exchangeRate).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(c.Request().Context(), "Error fetching exchange rate", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching exchange rates")
+	}
+
+	var before interface{}
+	if exchangeRate.ID != 0 {
+		before = exchangeRate
+	}
+
+	exchangeRate.Currency = currency
+	exchangeRate.Rate = request.Rate
+
+	if err := exchangeRate.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Save(&exchangeRate).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving exchange rate", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error saving exchange rate")
+	}
+
+	h.recordAudit(c, "exchange_rate.save", models.AuditEntityExchangeRate, exchangeRate.ID, before, exchangeRate)
+
+	return jsonResponse(c, http.StatusOK, "Exchange rate saved", exchangeRate)
+}
+
+// Delete Exchange Rate Handler [DELETE /admin/exchange-rates/:currency]
+// 1. Fetches the exchange rate for the currency.
+// 2. Deletes it, so prices can no longer be shown in that currency.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if there is no such exchange rate.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteExchangeRate(c echo.Context) error {
+	currency, ok := priceCurrency(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid currency")
+	}
+
+	var exchangeRate models.ExchangeRate
+	if err := h.db(c).Where("currency = ?", currency).First(&exchangeRate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Exchange rate not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching exchange rate", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching exchange rates")
+	}
+
+	if err := h.db(c).Delete(&exchangeRate).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting exchange rate", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting exchange rate")
+	}
+
+	h.recordAudit(c, "exchange_rate.delete", models.AuditEntityExchangeRate, exchangeRate.ID, exchangeRate, nil)
+
+	return jsonResponse(c, http.StatusOK, "Exchange rate deleted", exchangeRate)
+}
+
+// Get Product Prices Handler [GET /products/:id/prices]
+// 1. Fetches the product by ID.
+// 2. Returns status 200 with every price override of the product.
+// 3. Returns status 404 if the product is not found.
+// 4. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetProductPrices(c echo.Context) error {
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
+	}
+
+	product, err := repositories.GetProductByID(id, h.db(c))
+	if err != nil {
+		return jsonResponse(c, http.StatusNotFound, "Product not found")
+	}
+
+	var prices []models.ProductPrice
+	if err := h.db(c).Where("product_id = ?", product.ID).Order("currency").Find(&prices).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching product prices", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching prices")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Prices fetched successfully", prices)
+}
+
+// Save Product Price Handler [PUT /products/:id/prices/:currency]
+// 1. Validates the currency and fetches the product by ID.
+// 2. Creates the price override, or replaces the existing one for the currency.
+// 3. Returns status 200 with the price if successful.
+// 4. Returns status 400 if the currency or price is invalid.
+// 5. Returns status 404 if the product is not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) SaveProductPrice(c echo.Context) error {
+	currency, ok := priceCurrency(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid currency")
+	}
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
+	}
+
+	product, err := repositories.GetProductByID(id, h.db(c))
+	if err != nil {
+		return jsonResponse(c, http.StatusNotFound, "Product not found")
+	}
+
+	var request productPriceRequest
+	if err := c.Bind(&request); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for price")
+	}
+
+	var price models.ProductPrice
+	err = h.db(c).Where("product_id = ? AND currency = ?", product.ID, currency).First(&price).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(c.Request().Context(), "Error fetching product price", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching prices")
+	}
+
+	var before interface{}
+	if price.ID != 0 {
+		before = price
+	}
+
+	price.ProductID = product.ID
+	price.Currency = currency
+	price.Price = request.Price
+
+	if err := price.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Save(&price).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving product price", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error saving price")
+	}
+
+	h.recordAudit(c, "product_price.save", models.AuditEntityProductPrice, price.ID, before, price)
+
+	return jsonResponse(c, http.StatusOK, "Price saved", price)
+}
+
+// Delete Product Price Handler [DELETE /products/:id/prices/:currency]
+// 1. Fetches the product's price override for the currency.
+// 2. Deletes it, so the product's price is converted at the exchange rate again.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if there is no such price.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteProductPrice(c echo.Context) error {
+	currency, ok := priceCurrency(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid currency")
+	}
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var price models.ProductPrice
+	if err := h.db(c).Where("product_id = ? AND currency = ?", id, currency).First(&price).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Price not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching product price", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching prices")
+	}
+
+	if err := h.db(c).Delete(&price).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting product price", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting price")
+	}
+
+	h.recordAudit(c, "product_price.delete", models.AuditEntityProductPrice, price.ID, price, nil)
+
+	return jsonResponse(c, http.StatusOK, "Price deleted", price)
+}
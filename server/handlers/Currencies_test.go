@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/money"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiCurrency(t *testing.T) {
+	h, testDB, user, product := setupCartTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	saveRate := func(currency string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/admin/exchange-rates/"+currency, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("currency")
+		c.SetParamValues(currency)
+
+		assert.NoError(t, h.SaveExchangeRate(c))
+		return rec
+	}
+
+	getProduct := func(target string, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if header != "" {
+			req.Header.Set(currencyHeader, header)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("slug")
+		c.SetParamValues(product.Slug)
+
+		assert.NoError(t, h.GetProductBySlug(c))
+		return rec
+	}
+
+	price := func(rec *httptest.ResponseRecorder) (string, string) {
+		var response struct {
+			Data models.Product `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Data.Price.String(), string(response.Data.Currency)
+	}
+
+	t.Run("Unknown Or Unavailable Currency", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, getProduct("/products/mousepad?currency=XYZ", "").Code)
+		assert.Equal(t, http.StatusBadRequest, getProduct("/products/mousepad", "EUR").Code)
+	})
+
+	t.Run("Exchange Rates", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, saveRate("GBP", `{"rate": 1}`).Code)
+		assert.Equal(t, http.StatusBadRequest, saveRate("EUR", `{"rate": 0}`).Code)
+		assert.Equal(t, http.StatusOK, saveRate("EUR", `{"rate": 1.2}`).Code)
+		assert.Equal(t, http.StatusOK, saveRate("EUR", `{"rate": 1.16}`).Code)
+
+		var count int64
+		testDB.DB.Model(&models.ExchangeRate{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Converted Price", func(t *testing.T) {
+		rec := getProduct("/products/mousepad", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		amount, currency := price(rec)
+		assert.Equal(t, "25.00", amount)
+		assert.Equal(t, "GBP", currency)
+
+		rec = getProduct("/products/mousepad", "eur")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		amount, currency = price(rec)
+		assert.Equal(t, "29.00", amount)
+		assert.Equal(t, "EUR", currency)
+		assert.Contains(t, rec.Header().Values(echo.HeaderVary), currencyHeader)
+	})
+
+	t.Run("Price Override", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"price": 27.50}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "currency")
+		c.SetParamValues(fmt.Sprint(product.ID), "EUR")
+
+		assert.NoError(t, h.SaveProductPrice(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		amount, _ := price(getProduct("/products/mousepad?currency=EUR", "USD"))
+		assert.Equal(t, "27.50", amount)
+	})
+
+	t.Run("Checkout Locks Currency And Rate", func(t *testing.T) {
+		billing := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "X", Type: models.Billing}
+		shipping := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "X", Type: models.Shipping}
+		assert.NoError(t, testDB.DB.Create(&billing).Error)
+		assert.NoError(t, testDB.DB.Create(&shipping).Error)
+		assert.NoError(t, testDB.DB.Create(&models.CartItems{UserID: user.ID, ProductID: product.ID, Quantity: 2}).Error)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"billing_address_id":  billing.ID,
+			"shipping_address_id": shipping.ID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/cart/checkout?currency=EUR", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", user)
+
+		assert.NoError(t, h.CheckoutCart(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		// Later rate changes do not touch placed orders.
+		assert.Equal(t, http.StatusOK, saveRate("EUR", `{"rate": 1.5}`).Code)
+
+		var order models.Order
+		assert.NoError(t, testDB.DB.Preload("OrderItems").Where("user_id = ?", user.ID).First(&order).Error)
+		assert.Equal(t, money.EUR, order.Currency)
+		assert.Equal(t, "1.16", order.ExchangeRate.String())
+		assert.Equal(t, "55.00", order.Total.String())
+
+		assert.Len(t, order.OrderItems, 1)
+		assert.Equal(t, "27.50", order.OrderItems[0].Price.String())
+		assert.Equal(t, money.EUR, order.OrderItems[0].Currency)
+		assert.Equal(t, "1.16", order.OrderItems[0].ExchangeRate.String())
+	})
+}
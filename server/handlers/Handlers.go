@@ -1,11 +1,45 @@
 package handlers
 
 import (
+	"keylab/background"
+	"keylab/config"
+	"keylab/notify"
+	"keylab/repositories"
+	"keylab/tax"
+
 	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 type Handlers struct {
 	DB           *gorm.DB
 	SessionStore *sessions.CookieStore
+	Workers      *background.Group
+	Tax          tax.Calculator
+	Notifier     notify.Notifier
+}
+
+// db returns the database handle bound to the request's context, so repository and GORM query
+// logs carry the request ID.
+func (h *Handlers) db(c echo.Context) *gorm.DB {
+	return h.DB.WithContext(c.Request().Context())
+}
+
+// taxCalculator returns the configured tax calculator, the shop's own rate table by default.
+func (h *Handlers) taxCalculator() tax.Calculator {
+	if h.Tax != nil {
+		return h.Tax
+	}
+
+	return repositories.NewTaxRateTable(h.DB)
+}
+
+// notifier returns the configured staff notifier, the channels set in the config by default.
+func (h *Handlers) notifier() notify.Notifier {
+	if h.Notifier != nil {
+		return h.Notifier
+	}
+
+	return notify.FromConfig(config.Get())
 }
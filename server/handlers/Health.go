@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+	"keylab/apperr"
+	db "keylab/database"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// Healthz [GET /healthz] Liveness probe. Always 200 while the process can serve requests.
+func (h *Handlers) Healthz(c echo.Context) error {
+	return jsonResponse(c, http.StatusOK, "OK")
+}
+
+// Readyz [GET /readyz] Readiness probe. Checks the database connection and that all migrations
+// have been applied. The reason for a failure is logged, not sent.
+func (h *Handlers) Readyz(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	if err := db.CheckReady(ctx, h.DB); err != nil {
+		slog.WarnContext(c.Request().Context(), "Readiness check failed", "error", err)
+		return apperr.Render(c, apperr.FromStatus(http.StatusServiceUnavailable, "Not ready").WithCause(err))
+	}
+
+	return jsonResponse(c, http.StatusOK, "Ready")
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthz(t *testing.T) {
+	e := echo.New()
+	h := &Handlers{}
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+
+	if assert.NoError(t, h.Healthz(e.NewContext(req, rec))) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
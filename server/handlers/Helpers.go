@@ -3,9 +3,13 @@ package handlers
 import (
 	"fmt"
 	"io"
+	"keylab/apperr"
 	"keylab/config"
+	"keylab/i18n"
 	"keylab/utils"
-	"log"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,9 +19,17 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// jsonResponse writes the {"message", "data"} envelope with message translated into the request's
+// locale. Error statuses are written in the apperr error envelope instead, with the generic code
+// for the status and without data. Handlers that need a specific code can return an *apperr.Error, which the central
+// error handler renders.
 func jsonResponse(c echo.Context, httpCode int, message string, data ...interface{}) error {
+	if httpCode >= http.StatusBadRequest {
+		return apperr.Render(c, apperr.FromStatus(httpCode, message))
+	}
+
 	response := echo.Map{
-		"message": message,
+		"message": i18n.T(requestLocale(c), message),
 	}
 
 	if len(data) > 0 && data[0] != nil {
@@ -29,6 +41,16 @@ func jsonResponse(c echo.Context, httpCode int, message string, data ...interfac
 	return c.JSON(httpCode, response)
 }
 
+// requestLocale is the locale chosen for the request by the locale middleware.
+func requestLocale(c echo.Context) string {
+	return i18n.FromContext(c.Request().Context())
+}
+
+// validationError responds with every invalid field from a model's Validate method.
+func validationError(c echo.Context, err error) error {
+	return apperr.Render(c, apperr.Validation(err))
+}
+
 func initiateSession(session *sessions.Session, userID int64) {
 	session.Options = &sessions.Options{
 		Path:     "/",
@@ -87,23 +109,21 @@ func getSortOrder(c echo.Context) string {
 	return fmt.Sprintf("%s %s", sortField, sortDirection)
 }
 
-func uploadImages(c echo.Context, formField string, destination string, allowedExtensions []string) ([]map[string]interface{}, error) {
+// uploadImages saves the files uploaded in formField to destination. Every file is checked before
+// any is written, and when saving one fails the ones already saved are removed again.
+func uploadImages(c echo.Context, formField string, destination string, allowedExtensions []string) ([]map[string]interface{}, *apperr.Error) {
 	var uploadedFiles []map[string]interface{}
 
-	config := config.Initialize()
+	config := config.Get()
 
 	files, err := c.MultipartForm()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse multipart form: %v", err)
+		return nil, apperr.BadRequest("Invalid multipart form")
 	}
 
 	fileHeaders := files.File[formField]
 	if len(fileHeaders) == 0 {
-		return nil, fmt.Errorf("No files uploaded")
-	}
-
-	if err := os.MkdirAll(destination, os.ModePerm); err != nil {
-		return nil, fmt.Errorf("Failed to create destination directory: %v", err)
+		return nil, apperr.BadRequest("No files uploaded")
 	}
 
 	allowedExtensionsMap := make(map[string]bool)
@@ -112,28 +132,32 @@ func uploadImages(c echo.Context, formField string, destination string, allowedE
 	}
 
 	for _, fileHeader := range fileHeaders {
-		extension := strings.ToLower(filepath.Ext(fileHeader.Filename))
-		if !allowedExtensionsMap[extension] {
-			return nil, fmt.Errorf("File type not allowed: %s", extension)
+		if !allowedExtensionsMap[strings.ToLower(filepath.Ext(fileHeader.Filename))] {
+			return nil, apperr.BadRequest("File type not allowed")
 		}
 
-		filename := utils.GenerateUniqueFilename(extension)
-		destinationPath := filepath.Join(destination, filename)
-
-		src, err := fileHeader.Open()
-		if err != nil {
-			return nil, fmt.Errorf("Failed to open source file: %v", err)
+		if fileHeader.Size > int64(config.MAX_UPLOAD_SIZE) {
+			return nil, apperr.BadRequest("File too large")
 		}
-		defer src.Close()
+	}
 
-		dst, err := os.Create(destinationPath)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to create destination file: %v", err)
-		}
-		defer dst.Close()
+	if err := os.MkdirAll(destination, os.ModePerm); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error creating upload directory", "error", err)
+		return nil, apperr.Internal("Error saving uploaded files", err)
+	}
+
+	var written []string
+	for _, fileHeader := range fileHeaders {
+		filename := utils.GenerateUniqueFilename(strings.ToLower(filepath.Ext(fileHeader.Filename)))
+		destinationPath := filepath.Join(destination, filename)
 
-		if _, err := io.Copy(dst, src); err != nil {
-			return nil, fmt.Errorf("Failed to copy content to destination: %v", err)
+		written = append(written, destinationPath)
+		if err := saveUploadedFile(fileHeader, destinationPath); err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error saving uploaded file", "error", err)
+			for _, path := range written {
+				deleteImage(path)
+			}
+			return nil, apperr.Internal("Error saving uploaded files", err)
 		}
 
 		fileURL := fmt.Sprintf("%s/uploads/%s", config.SERVER_URL, filename)
@@ -147,10 +171,29 @@ func uploadImages(c echo.Context, formField string, destination string, allowedE
 	return uploadedFiles, nil
 }
 
+func saveUploadedFile(fileHeader *multipart.FileHeader, destinationPath string) error {
+	src, err := fileHeader.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.Create(destinationPath)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to copy content to destination: %w", err)
+	}
+
+	return dst.Close()
+}
+
 func deleteImage(imagePath string) error {
-	fmt.Println("imagePath", imagePath)
 	if err := os.Remove(imagePath); err != nil && !os.IsNotExist(err) {
-		log.Printf("Failed to delete image file: %s, error: %v", imagePath, err)
+		slog.Warn("Failed to delete image file", "path", imagePath, "error", err)
 	}
 
 	return nil
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/notify"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// notifyLowStock alerts staff about products that have run low, in the background so a slow mail
+// server or webhook does not hold up checkout. Failed alerts are logged and not retried.
+func (h *Handlers) notifyLowStock(ctx context.Context, products []notify.LowStock) {
+	send := func(ctx context.Context) {
+		if err := h.notifier().LowStock(ctx, products); err != nil {
+			slog.ErrorContext(ctx, "Error sending low stock alert", "error", err)
+		}
+	}
+
+	if h.Workers == nil || !h.Workers.Go("low stock alert", send) {
+		send(context.WithoutCancel(ctx))
+	}
+}
+
+// Get Low Stock Products Handler [GET /admin/inventory/low-stock]
+// 1. Lists the products at or below their low stock threshold, or LOW_STOCK_THRESHOLD when they
+// have none, the emptiest first.
+// 2. Each product includes when staff were last alerted about it, null if they have not been.
+func (h *Handlers) GetLowStockProducts(c echo.Context) error {
+	page, perPage, offset := getPaginationParams(c)
+
+	products, total, err := repositories.GetLowStockProducts(config.Get().LOW_STOCK_THRESHOLD, perPage, offset, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching low stock products", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching low stock products", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, "Low stock products fetched successfully", map[string]interface{}{
+		"products": products,
+		"metadata": generatePaginationResponse(page, perPage, int(total)),
+	})
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/money"
+	"keylab/notify"
+	"keylab/repositories"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts [][]notify.LowStock
+}
+
+func (n *recordingNotifier) LowStock(ctx context.Context, products []notify.LowStock) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, products)
+	return nil
+}
+
+func (n *recordingNotifier) take() [][]notify.LowStock {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	alerts := n.alerts
+	n.alerts = nil
+	return alerts
+}
+
+func TestLowStockAlerts(t *testing.T) {
+	h, testDB, user, mousepad := setupCartTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	notifier := &recordingNotifier{}
+	h.Notifier = notifier
+	e := echo.New()
+
+	threshold := 7
+	assert.NoError(t, testDB.DB.Model(&mousepad).Update("low_stock_threshold", threshold).Error)
+
+	// Without a threshold of its own, the cable uses LOW_STOCK_THRESHOLD, 5 by default.
+	cable := models.Product{Name: "Coiled cable", Slug: "coiled-cable", Description: "USB-C", Price: money.MustParse("30.00"), Stock: 6, CategoryID: mousepad.CategoryID}
+	assert.NoError(t, testDB.DB.Create(&cable).Error)
+
+	address := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "X", Type: models.Shipping}
+	assert.NoError(t, testDB.DB.Create(&address).Error)
+
+	checkout := func(items map[int64]int) {
+		for productID, quantity := range items {
+			assert.NoError(t, testDB.DB.Create(&models.CartItems{UserID: user.ID, ProductID: productID, Quantity: quantity}).Error)
+		}
+
+		body, _ := json.Marshal(map[string]interface{}{"billing_address_id": address.ID, "shipping_address_id": address.ID})
+		req := httptest.NewRequest(http.MethodPost, "/cart/checkout", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", user)
+
+		assert.NoError(t, h.CheckoutCart(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	t.Run("Alerts Once Below The Threshold", func(t *testing.T) {
+		checkout(map[int64]int{mousepad.ID: 2})
+		assert.Empty(t, notifier.take(), "8 left is above the threshold of 7")
+
+		checkout(map[int64]int{mousepad.ID: 2, cable.ID: 1})
+		alerts := notifier.take()
+		if assert.Len(t, alerts, 1) {
+			assert.Equal(t, []notify.LowStock{
+				{ProductID: mousepad.ID, Name: "Mousepad", Slug: "mousepad", Stock: 6, Threshold: 7},
+				{ProductID: cable.ID, Name: "Coiled cable", Slug: "coiled-cable", Stock: 5, Threshold: 5},
+			}, alerts[0])
+		}
+
+		checkout(map[int64]int{mousepad.ID: 1, cable.ID: 1})
+		assert.Empty(t, notifier.take(), "already alerted")
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/inventory/low-stock", nil)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, h.GetLowStockProducts(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data struct {
+				Products []repositories.LowStockProduct `json:"products"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		products := response.Data.Products
+		if assert.Len(t, products, 2) {
+			assert.Equal(t, cable.ID, products[0].ID, "the emptiest first")
+			assert.Equal(t, 4, products[0].Stock)
+			assert.Equal(t, 7, products[1].Threshold)
+			assert.NotNil(t, products[1].AlertedAt)
+		}
+	})
+
+	t.Run("Alerts Again After A Restock", func(t *testing.T) {
+		assert.NoError(t, testDB.DB.Model(&mousepad).Update("stock", 20).Error)
+
+		checkout(map[int64]int{mousepad.ID: 15})
+		alerts := notifier.take()
+		if assert.Len(t, alerts, 1) && assert.Len(t, alerts[0], 1) {
+			assert.Equal(t, 5, alerts[0][0].Stock)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/database/models"
+	"keylab/documents"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const mimeApplicationPDF = "application/pdf"
+
+// Get User Order Invoice Handler [GET /user/orders/:id/invoice]
+// 1. Fetches the order by ID, which must belong to the user.
+// 2. Issues the order's invoice number the first time its invoice is requested.
+// 3. Returns status 200 with the invoice as a PDF.
+// 4. Returns status 404 if the order is not found or is someone else's.
+// 5. Returns status 409 if the order was cancelled before it had an invoice.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetUserOrderInvoice(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	order, appErr := h.documentOrder(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+	if order.UserID != user.ID {
+		return jsonResponse(c, http.StatusNotFound, "Order not found")
+	}
+
+	return h.invoiceResponse(c, order)
+}
+
+// Get Order Invoice Handler [GET /admin/orders/:id/invoice]
+// 1. Fetches the order by ID.
+// 2. Issues the order's invoice number the first time its invoice is requested.
+// 3. Returns status 200 with the invoice as a PDF.
+// 4. Returns status 404 if the order is not found.
+// 5. Returns status 409 if the order was cancelled before it had an invoice.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetOrderInvoice(c echo.Context) error {
+	order, appErr := h.documentOrder(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	return h.invoiceResponse(c, order)
+}
+
+// Get Order Packing Slip Handler [GET /admin/orders/:id/packing-slip]
+// 1. Fetches the order by ID.
+// 2. Returns status 200 with the packing slip as a PDF.
+// 3. Returns status 404 if the order is not found.
+// 4. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetOrderPackingSlip(c echo.Context) error {
+	order, appErr := h.documentOrder(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	var pdf bytes.Buffer
+	if err := documents.PackingSlip(&pdf, seller(), order); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error rendering packing slip", "order_id", order.ID, "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error generating packing slip")
+	}
+
+	return pdfResponse(c, fmt.Sprintf("packing-slip-%d.pdf", order.ID), pdf.Bytes())
+}
+
+// documentOrder fetches the order in the :id param with everything its documents show.
+func (h *Handlers) documentOrder(c echo.Context) (models.Order, *apperr.Error) {
+	orderID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return models.Order{}, apperr.BadRequest("Invalid order ID")
+	}
+
+	order, err := repositories.GetOrderForDocuments(orderID, h.db(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Order{}, apperr.NotFound("Order not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching order details", "error", err)
+		return models.Order{}, apperr.Internal("Error fetching order details", err)
+	}
+
+	return order, nil
+}
+
+// invoiceResponse issues the order's invoice if it has none yet and responds with the PDF.
+func (h *Handlers) invoiceResponse(c echo.Context, order models.Order) error {
+	if order.Invoice == nil && order.Status == models.Cancelled {
+		return jsonResponse(c, http.StatusConflict, "Cancelled orders are not invoiced")
+	}
+
+	invoice, err := repositories.IssueInvoice(order.ID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error issuing invoice", "order_id", order.ID, "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error generating invoice")
+	}
+
+	var pdf bytes.Buffer
+	if err := documents.Invoice(&pdf, seller(), invoice, order); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error rendering invoice", "order_id", order.ID, "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error generating invoice")
+	}
+
+	return pdfResponse(c, "invoice-"+invoice.Reference()+".pdf", pdf.Bytes())
+}
+
+func seller() documents.Seller {
+	return documents.ParseSeller(config.Get().STORE_NAME, config.Get().STORE_ADDRESS)
+}
+
+// pdfResponse sends the PDF as a download named filename.
+func pdfResponse(c echo.Context, filename string, pdf []byte) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, mimeApplicationPDF, pdf)
+}
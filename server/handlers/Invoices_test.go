@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/money"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoices(t *testing.T) {
+	h, testDB, user, product := setupCartTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	address := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "United Kingdom", Type: models.Billing}
+	assert.NoError(t, testDB.DB.Create(&address).Error)
+
+	createOrder := func(status models.OrderStatus) models.Order {
+		order := models.Order{
+			UserID:            user.ID,
+			Status:            status,
+			Total:             money.MustParse("50.00"),
+			Currency:          money.GBP,
+			ShippingAddressID: address.ID,
+			BillingAddressID:  address.ID,
+			OrderDate:         time.Now(),
+		}
+		assert.NoError(t, testDB.DB.Create(&order).Error)
+		assert.NoError(t, testDB.DB.Create(&models.OrderedItem{OrderID: order.ID, ProductID: product.ID, Quantity: 2, Price: money.MustParse("25.00"), Currency: money.GBP}).Error)
+		return order
+	}
+
+	request := func(handler echo.HandlerFunc, orderID int64, as models.User) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(orderID))
+		c.Set("user", as)
+
+		assert.NoError(t, handler(c))
+		return rec
+	}
+
+	first := createOrder(models.Pending)
+	second := createOrder(models.Delivered)
+
+	t.Run("Owner Downloads Invoice", func(t *testing.T) {
+		rec := request(h.GetUserOrderInvoice, first.ID, user)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, mimeApplicationPDF, rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "invoice-INV-000001.pdf")
+		assert.Contains(t, rec.Body.String(), "%PDF-")
+		assert.Contains(t, rec.Body.String(), "(Mousepad)")
+
+		// The number is kept on later downloads.
+		rec = request(h.GetUserOrderInvoice, first.ID, user)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "invoice-INV-000001.pdf")
+	})
+
+	t.Run("Other Users Cannot Download Invoice", func(t *testing.T) {
+		other := models.User{ID: user.ID + 1000}
+		assert.Equal(t, http.StatusNotFound, request(h.GetUserOrderInvoice, first.ID, other).Code)
+	})
+
+	t.Run("Admin Downloads Invoice And Packing Slip", func(t *testing.T) {
+		rec := request(h.GetOrderInvoice, second.ID, user)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "invoice-INV-000002.pdf")
+
+		rec = request(h.GetOrderPackingSlip, second.ID, user)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "(PACKING SLIP)")
+
+		assert.Equal(t, http.StatusNotFound, request(h.GetOrderInvoice, 999999, user).Code)
+	})
+
+	t.Run("Cancelled Orders Are Not Invoiced", func(t *testing.T) {
+		cancelled := createOrder(models.Cancelled)
+		assert.Equal(t, http.StatusConflict, request(h.GetOrderInvoice, cancelled.ID, user).Code)
+
+		var count int64
+		testDB.DB.Model(&models.Invoice{}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+}
@@ -3,7 +3,7 @@ package handlers
 import (
 	"errors"
 	"keylab/database/models"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -20,15 +20,21 @@ import (
 func (h *Handlers) GetCategories(c echo.Context) error {
 	var categories []models.ProductCategory
 
-	if err := h.DB.Preload("Parent").Find(&categories).Error; err != nil {
-		log.Printf("Error fetching categories: %v", err)
+	if err := h.db(c).Preload("Parent").Find(&categories).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching categories", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching categories")
 	}
 
 	if len(categories) == 0 {
-		return jsonResponse(c, http.StatusNotFound, "No categories found", categories)
+		return jsonResponse(c, http.StatusNotFound, "No categories found")
 	}
 
+	localized := make([]*models.ProductCategory, len(categories))
+	for i := range categories {
+		localized[i] = &categories[i]
+	}
+	h.localizeCategories(c, localized...)
+
 	return jsonResponse(c, http.StatusOK, "Categories found", categories)
 }
 
@@ -47,15 +53,17 @@ func (h *Handlers) GetCategoryBySlug(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid category slug")
 	}
 
-	if err := h.DB.Where("slug = ?", slug).Preload("Parent").First(&category).Error; err != nil {
+	if err := h.db(c).Where("slug = ?", slug).Preload("Parent").First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return jsonResponse(c, http.StatusNotFound, "Category not found")
 		}
 
-		log.Printf("Error fetching category by slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching category by slug", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching category by slug")
 	}
 
+	h.localizeCategories(c, &category)
+
 	return jsonResponse(c, http.StatusOK, "Category found", category)
 }
 
@@ -76,18 +84,26 @@ func (h *Handlers) CreateCategory(c echo.Context) error {
 	}
 
 	if err := category.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
-	if err := h.DB.Where("slug = ?", category.Slug).First(&category).Error; err == nil {
+	if category.TaxClassID != nil {
+		if err := h.db(c).First(&models.TaxClass{}, *category.TaxClassID).Error; err != nil {
+			return jsonResponse(c, http.StatusBadRequest, "Tax class not found")
+		}
+	}
+
+	if err := h.db(c).Where("slug = ?", category.Slug).First(&category).Error; err == nil {
 		return jsonResponse(c, http.StatusBadRequest, "Category already exists with the same slug")
 	}
 
-	if err := h.DB.Create(&category).Error; err != nil {
-		log.Printf("Error creating product category: %v", err)
+	if err := h.db(c).Create(&category).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error creating product category", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating product category")
 	}
 
+	h.recordAudit(c, "category.create", models.AuditEntityCategory, category.ID, nil, category)
+
 	return jsonResponse(c, http.StatusCreated, "Product category created", category)
 }
 
@@ -106,20 +122,22 @@ func (h *Handlers) DeleteCategory(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid category slug")
 	}
 
-	if err := h.DB.Where("slug = ?", slug).Preload("Parent").First(&category).Error; err != nil {
+	if err := h.db(c).Where("slug = ?", slug).Preload("Parent").First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return jsonResponse(c, http.StatusNotFound, "Category not found")
 		}
 
-		log.Printf("Error fetching category by slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching category by slug", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching category by slug")
 	}
 
-	if err := h.DB.Delete(&category).Error; err != nil {
-		log.Printf("Error deleting product category: %v", err)
+	if err := h.db(c).Delete(&category).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting product category", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error deleting product category")
 	}
 
+	h.recordAudit(c, "category.delete", models.AuditEntityCategory, category.ID, category, nil)
+
 	return jsonResponse(c, http.StatusOK, "Product category deleted", category)
 }
 
@@ -141,27 +159,37 @@ func (h *Handlers) UpdateCategory(c echo.Context) error {
 	}
 
 	// Fetch the existing category
-	if err := h.DB.Where("slug = ?", slug).First(&category).Error; err != nil {
+	if err := h.db(c).Where("slug = ?", slug).First(&category).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return jsonResponse(c, http.StatusNotFound, "Category not found")
 		}
 
-		log.Printf("Error fetching category by slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching category by slug", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching category by slug")
 	}
 
+	before := category
+
 	if err := c.Bind(&category); err != nil {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid input updating product category")
 	}
 
 	if err := category.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
+	}
+
+	if category.TaxClassID != nil {
+		if err := h.db(c).First(&models.TaxClass{}, *category.TaxClassID).Error; err != nil {
+			return jsonResponse(c, http.StatusBadRequest, "Tax class not found")
+		}
 	}
 
-	if err := h.DB.Save(&category).Error; err != nil {
-		log.Printf("Error updating product category: %v", err)
+	if err := h.db(c).Save(&category).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error updating product category", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating product category")
 	}
 
+	h.recordAudit(c, "category.update", models.AuditEntityCategory, category.ID, before, category)
+
 	return jsonResponse(c, http.StatusOK, "Product category updated", category)
 }
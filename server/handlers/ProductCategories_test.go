@@ -18,7 +18,7 @@ import (
 func TestGetCategories(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
@@ -100,7 +100,7 @@ func TestGetCategories(t *testing.T) {
 func TestGetCategoryBySlug(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
@@ -191,7 +191,7 @@ func TestGetCategoryBySlug(t *testing.T) {
 func TestCreateCategory(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
@@ -315,7 +315,7 @@ func TestCreateCategory(t *testing.T) {
 func TestUpdateCategory(t *testing.T) {
 	e := echo.New()
 
-	config := config.Initialize()
+	config := config.Get()
 
 	sessionStore := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
 	sessionStore.Options.HttpOnly = true
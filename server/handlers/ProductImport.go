@@ -0,0 +1,566 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/database/models"
+	"keylab/productcsv"
+	"keylab/repositories"
+	"keylab/utils"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	productImageDirectory = "public/images/product_images"
+	seedImagePrefix       = "public/seed/"
+
+	// inlineImportRows is the most rows imported before responding. Larger files, and files with
+	// image URLs to download unless it is a dry run, are imported in the background.
+	inlineImportRows = 100
+	// importProgressRows is how often a running import saves its progress.
+	importProgressRows = 25
+)
+
+var productImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var productImageExtensions = []string{".jpg", ".jpeg", ".png", ".webp"}
+
+// imageClient downloads the images of imported products. It only connects to public addresses,
+// checked after DNS resolution for every connection including redirects, so an import cannot make
+// the server fetch from itself or its private network.
+var imageClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: 10 * time.Second, Control: dialPublicOnly}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+	CheckRedirect: func(request *http.Request, via []*http.Request) error {
+		if len(via) >= maxImageRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxImageRedirects)
+		}
+		if request.URL.Scheme != "http" && request.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to %q not allowed", request.URL.Scheme)
+		}
+		if ip := net.ParseIP(request.URL.Hostname()); ip != nil && !publicIP(ip) {
+			return fmt.Errorf("redirect to %s not allowed", ip)
+		}
+		return nil
+	},
+}
+
+const maxImageRedirects = 5
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice but not in net.IP.IsPrivate.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified() && !sharedAddressSpace.Contains(ip)
+}
+
+// dialPublicOnly is a net.Dialer Control function refusing connections to non-public addresses.
+func dialPublicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("connecting to %s is not allowed", host)
+	}
+
+	return nil
+}
+
+// Export Products Handler [GET /admin/products/export.csv]
+// 1. Writes every product as a CSV row, with its category slug and image filenames.
+// 2. Prices are in the base currency, so the file can be imported again.
+func (h *Handlers) ExportProducts(c echo.Context) error {
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	response.Header().Set(echo.HeaderContentDisposition, `attachment; filename="products.csv"`)
+	response.WriteHeader(http.StatusOK)
+
+	writer := productcsv.NewWriter(response)
+
+	var products []models.Product
+	err := h.db(c).Preload("Category").Preload("ProductImages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("primary_image DESC, id")
+	}).Order("id").FindInBatches(&products, 200, func(tx *gorm.DB, batch int) error {
+		for _, product := range products {
+			if err := writer.Write(exportRecord(product)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	// The status has been sent, so a failure can only cut the file short.
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error exporting products", "error", err)
+	}
+
+	return nil
+}
+
+func exportRecord(product models.Product) productcsv.Record {
+	record := productcsv.Record{
+		Slug:        product.Slug,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Stock:       product.Stock,
+		Weight:      product.Weight,
+	}
+	if product.Category != nil {
+		record.Category = product.Category.Slug
+	}
+	for _, image := range product.ProductImages {
+		record.Images = append(record.Images, image.Image)
+	}
+
+	return record
+}
+
+// Import Products Handler [POST /admin/products/import]
+// 1. Reads the CSV file in the "file" form field; dry_run=true only checks it.
+// 2. Creates or updates a product per row, skipping rows with errors.
+// 3. Returns status 200 with the finished import for small files.
+// 4. Returns status 202 with the pending import for large files, to be polled at
+// GET /admin/products/import/:id.
+// 5. Returns status 400 if the file is not a usable CSV file.
+func (h *Handlers) ImportProducts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return apperr.Render(c, apperr.BadRequest("No file uploaded"))
+	}
+	if maxSize := config.Get().MAX_UPLOAD_SIZE; fileHeader.Size > int64(maxSize) {
+		return apperr.Render(c, apperr.New(http.StatusRequestEntityTooLarge, apperr.CodePayloadTooLarge, "File too large"))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		slog.ErrorContext(ctx, "Error opening import file", "error", err)
+		return apperr.Render(c, apperr.Internal("Error reading file", err))
+	}
+	defer file.Close()
+
+	records, rowErrors, err := productcsv.Read(file)
+	if err != nil {
+		appErr := apperr.BadRequest("Invalid CSV file")
+		appErr.Fields = []apperr.FieldError{{Field: "file", Tag: "csv", Message: err.Error()}}
+		return apperr.Render(c, appErr)
+	}
+
+	job := models.ProductImport{
+		Filename:      filepath.Base(fileHeader.Filename),
+		DryRun:        c.FormValue("dry_run") == "true" || c.QueryParam("dry_run") == "true",
+		Status:        models.ImportPending,
+		TotalRows:     len(records) + len(rowErrors),
+		Failed:        len(rowErrors),
+		ProcessedRows: len(rowErrors),
+		Errors:        rowErrors,
+	}
+	if user, ok := c.Get("user").(models.User); ok {
+		job.UserID = &user.ID
+	}
+
+	if err := h.db(c).Create(&job).Error; err != nil {
+		slog.ErrorContext(ctx, "Error creating product import", "error", err)
+		return apperr.Render(c, apperr.Internal("Error importing products", err))
+	}
+
+	importer := &productImporter{db: h.DB, audit: h.auditor(c), job: &job, records: records}
+
+	if len(records) <= inlineImportRows && (job.DryRun || !hasImageURLs(records)) {
+		// Once started, the import finishes even if the client goes away.
+		importer.run(context.WithoutCancel(ctx))
+		return jsonResponse(c, http.StatusOK, "Products imported", job)
+	}
+
+	// The worker updates job from here on.
+	pending := job
+	if h.Workers == nil || !h.Workers.Go("product import", importer.run) {
+		importer.fail(ctx, "The server is shutting down")
+		return apperr.Render(c, apperr.FromStatus(http.StatusServiceUnavailable, "Shutting down"))
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/admin/products/import/%d", pending.ID))
+	return jsonResponse(c, http.StatusAccepted, "Product import started", pending)
+}
+
+// Get Product Import Handler [GET /admin/products/import/:id]
+// 1. Returns the import's status, counts and row errors so far.
+// 2. Returns status 404 if the import is not found.
+func (h *Handlers) GetProductImport(c echo.Context) error {
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid import ID"))
+	}
+
+	var job models.ProductImport
+	if err := h.db(c).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperr.Render(c, apperr.NotFound("Import not found"))
+		}
+		slog.ErrorContext(c.Request().Context(), "Error fetching product import", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching import", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, "Import fetched successfully", job)
+}
+
+func hasImageURLs(records []productcsv.Record) bool {
+	for _, record := range records {
+		for _, image := range record.Images {
+			if isImageURL(image) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isImageURL(image string) bool {
+	lower := strings.ToLower(image)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// productImporter imports the records of a file, saving its progress to job as it goes.
+type productImporter struct {
+	db      *gorm.DB
+	audit   auditor
+	job     *models.ProductImport
+	records []productcsv.Record
+}
+
+func (p *productImporter) run(ctx context.Context) {
+	p.job.Status = models.ImportRunning
+	p.save(ctx)
+
+	for i, record := range p.records {
+		if ctx.Err() != nil {
+			p.fail(ctx, "Stopped by a server shutdown")
+			return
+		}
+
+		created, err := p.importRecord(ctx, record)
+		switch {
+		case err != nil:
+			p.job.Failed++
+			p.job.Errors = append(p.job.Errors, productcsv.RowError{Line: record.Line, Slug: record.Slug, Message: err.Error()})
+		case created:
+			p.job.Created++
+		default:
+			p.job.Updated++
+		}
+		p.job.ProcessedRows++
+
+		if (i+1)%importProgressRows == 0 {
+			p.save(ctx)
+		}
+	}
+
+	sort.SliceStable(p.job.Errors, func(i, j int) bool {
+		return p.job.Errors[i].Line < p.job.Errors[j].Line
+	})
+
+	now := time.Now()
+	p.job.Status = models.ImportCompleted
+	p.job.FinishedAt = &now
+	p.save(ctx)
+}
+
+func (p *productImporter) fail(ctx context.Context, message string) {
+	now := time.Now()
+	p.job.Status = models.ImportFailed
+	p.job.Message = message
+	p.job.FinishedAt = &now
+	p.save(ctx)
+}
+
+func (p *productImporter) save(ctx context.Context) {
+	// The job's own context may be cancelled, the outcome is still worth saving.
+	if err := p.db.WithContext(context.WithoutCancel(ctx)).Save(p.job).Error; err != nil {
+		slog.ErrorContext(ctx, "Error saving product import", "import_id", p.job.ID, "error", err)
+	}
+}
+
+// rowError is a problem with a record, reported to the user as it is.
+type rowError struct {
+	message string
+}
+
+func (e rowError) Error() string {
+	return e.message
+}
+
+func rowErrorf(format string, args ...interface{}) error {
+	return rowError{message: fmt.Sprintf(format, args...)}
+}
+
+// importRecord creates or updates the record's product and adds the images it does not have yet.
+// Other errors than row errors are logged and reported as a failure to save the row.
+func (p *productImporter) importRecord(ctx context.Context, record productcsv.Record) (bool, error) {
+	created, err := p.upsert(ctx, record)
+
+	var rowErr rowError
+	if err != nil && !errors.As(err, &rowErr) {
+		slog.ErrorContext(ctx, "Error importing product", "import_id", p.job.ID, "line", record.Line, "slug", record.Slug, "error", err)
+		return false, errors.New("the product could not be saved")
+	}
+
+	return created, err
+}
+
+func (p *productImporter) upsert(ctx context.Context, record productcsv.Record) (bool, error) {
+	db := p.db.WithContext(ctx)
+
+	var category models.ProductCategory
+	if err := db.Where("slug = ?", record.Category).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, rowErrorf("category %q not found", record.Category)
+		}
+		return false, err
+	}
+
+	product, err := repositories.GetProductBySlug(record.Slug, db)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	created := product.ID == 0
+	before := product
+
+	product.Slug = record.Slug
+	product.Name = record.Name
+	product.Description = record.Description
+	product.Price = record.Price
+	product.Stock = record.Stock
+	product.Weight = record.Weight
+	product.CategoryID = category.ID
+	product.Category = &category
+
+	if err := validateImportedProduct(product); err != nil {
+		return false, err
+	}
+
+	attached := map[string]bool{}
+	for _, image := range product.ProductImages {
+		attached[image.Image] = true
+	}
+
+	var newImages []string
+	for _, image := range record.Images {
+		if attached[image] {
+			continue
+		}
+		if err := checkImportImage(image); err != nil {
+			return false, err
+		}
+		newImages = append(newImages, image)
+	}
+
+	if p.job.DryRun {
+		return created, nil
+	}
+
+	// Downloads come before the transaction so it is not held open for them, and are removed again
+	// if the row is not saved.
+	var downloaded []string
+	saved := false
+	defer func() {
+		if !saved {
+			for _, filename := range downloaded {
+				deleteImage(filepath.Join(productImageDirectory, filename))
+			}
+		}
+	}()
+	for i, image := range newImages {
+		if !isImageURL(image) {
+			continue
+		}
+		filename, err := downloadProductImage(ctx, image)
+		if err != nil {
+			return false, err
+		}
+		downloaded = append(downloaded, filename)
+		newImages[i] = filename
+	}
+
+	hasPrimary := false
+	for _, image := range product.ProductImages {
+		hasPrimary = hasPrimary || image.PrimaryImage
+	}
+
+	var images []models.ProductImage
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "ProductImages").Save(&product).Error; err != nil {
+			return err
+		}
+
+		for i, image := range newImages {
+			productImage := models.ProductImage{ProductID: product.ID, Image: image, PrimaryImage: !hasPrimary && i == 0}
+			if err := tx.Create(&productImage).Error; err != nil {
+				return err
+			}
+			images = append(images, productImage)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	saved = true
+
+	if created {
+		p.audit.record(ctx, "product.create", models.AuditEntityProduct, product.ID, nil, product)
+	} else {
+		p.audit.record(ctx, "product.update", models.AuditEntityProduct, product.ID, before, product)
+	}
+	for _, image := range images {
+		p.audit.record(ctx, "product_image.create", models.AuditEntityProductImage, image.ID, nil, image)
+	}
+
+	return created, nil
+}
+
+// validateImportedProduct validates the product like CreateProduct does, except that a stock of 0
+// is allowed, so sold out products can be exported and imported again.
+func validateImportedProduct(product models.Product) error {
+	if product.Stock < 0 {
+		return rowErrorf("stock must be at least 0")
+	}
+
+	err := product.Validate("Name", "Description", "Price", "Weight", "CategoryID")
+	if err == nil {
+		return nil
+	}
+
+	var messages []string
+	for _, field := range apperr.Validation(err).Fields {
+		messages = append(messages, field.Message)
+	}
+	if len(messages) == 0 {
+		return err
+	}
+
+	return rowErrorf("%s", strings.Join(messages, "; "))
+}
+
+// checkImportImage checks that an image filename exists in the product images, or among the seed
+// images, and that an image URL is an http(s) URL. URLs are only checked further when downloaded.
+func checkImportImage(image string) error {
+	if isImageURL(image) {
+		if parsed, err := url.Parse(image); err != nil || parsed.Host == "" {
+			return rowErrorf("image URL %q is not valid", image)
+		}
+		return nil
+	}
+
+	if !allowedImageExtension(filepath.Ext(image)) {
+		return rowErrorf("image %q is not a .jpg, .jpeg, .png or .webp file", image)
+	}
+
+	imagePath := filepath.Join(productImageDirectory, image)
+	if strings.HasPrefix(image, seedImagePrefix) {
+		imagePath = image
+		if path.Clean(image) != image || path.Dir(image)+"/" != seedImagePrefix {
+			return rowErrorf("image %q not found", image)
+		}
+	} else if filepath.Base(image) != image {
+		return rowErrorf("image %q not found", image)
+	}
+
+	if _, err := os.Stat(imagePath); err != nil {
+		return rowErrorf("image %q not found", image)
+	}
+
+	return nil
+}
+
+func allowedImageExtension(extension string) bool {
+	extension = strings.ToLower(extension)
+	for _, allowed := range productImageExtensions {
+		if extension == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// downloadProductImage saves the image at imageURL with the product images and returns its
+// filename. Images are held to the same types and size as uploads.
+func downloadProductImage(ctx context.Context, imageURL string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", rowErrorf("image URL %q is not valid", imageURL)
+	}
+
+	response, err := imageClient.Do(request)
+	if err != nil {
+		return "", rowErrorf("image %q could not be downloaded", imageURL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", rowErrorf("image %q could not be downloaded: %s", imageURL, response.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get(echo.HeaderContentType))
+	extension, ok := productImageTypes[mediaType]
+	if !ok {
+		if extension = strings.ToLower(path.Ext(request.URL.Path)); !allowedImageExtension(extension) {
+			return "", rowErrorf("image %q is not a JPEG, PNG or WebP image", imageURL)
+		}
+	}
+
+	maxSize := int64(config.Get().MAX_UPLOAD_SIZE)
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxSize+1))
+	if err != nil {
+		return "", rowErrorf("image %q could not be downloaded", imageURL)
+	}
+	if int64(len(body)) > maxSize {
+		return "", rowErrorf("image %q exceeds %s", imageURL, config.Get().MAX_UPLOAD_SIZE)
+	}
+
+	if err := os.MkdirAll(productImageDirectory, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	filename := utils.GenerateUniqueFilename(extension)
+	if err := os.WriteFile(filepath.Join(productImageDirectory, filename), body, 0o644); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"keylab/background"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/repositories"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductImport(t *testing.T) {
+	h, testDB, product := setupProductTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	category := models.ProductCategory{Name: "Keyboards", Slug: "keyboards"}
+	assert.NoError(t, testDB.DB.Create(&category).Error)
+
+	upload := func(file string, dryRun bool) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "products.csv")
+		_, _ = part.Write([]byte(file))
+		if dryRun {
+			_ = writer.WriteField("dry_run", "true")
+		}
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/products/import", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, h.ImportProducts(e.NewContext(req, rec)))
+		return rec
+	}
+
+	importOf := func(rec *httptest.ResponseRecorder) models.ProductImport {
+		var response struct {
+			Data models.ProductImport `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	file := strings.Join([]string{
+		"slug,name,description,price,stock,category,images",
+		fmt.Sprintf("%s,Renamed Product,Updated,59.99,0,keyboards,", product.Slug),
+		",Split Keyboard,Two halves,199.00,5,keyboards,",
+		"mousepad,Mousepad,Desk mat,19.99,10,mousepads,",
+		"keycaps,Keycaps,PBT,39.99,10,keyboards,missing.png",
+		"switches,Switches,Linear,not a price,10,keyboards,",
+	}, "\n")
+
+	t.Run("Dry Run Changes Nothing", func(t *testing.T) {
+		rec := upload(file, true)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		job := importOf(rec)
+		assert.Equal(t, models.ImportCompleted, job.Status)
+		assert.True(t, job.DryRun)
+		assert.Equal(t, []int{5, 5, 1, 1, 3}, []int{job.TotalRows, job.ProcessedRows, job.Created, job.Updated, job.Failed})
+
+		var count int64
+		testDB.DB.Model(&models.Product{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Import Upserts By Slug And Reports Row Errors", func(t *testing.T) {
+		rec := upload(file, false)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		job := importOf(rec)
+		assert.Equal(t, []int{1, 1, 3}, []int{job.Created, job.Updated, job.Failed})
+		assert.Equal(t, 4, job.Errors[0].Line)
+		assert.Equal(t, `category "mousepads" not found`, job.Errors[0].Message)
+		assert.Equal(t, `image "missing.png" not found`, job.Errors[1].Message)
+		assert.Equal(t, 6, job.Errors[2].Line)
+
+		var updated models.Product
+		assert.NoError(t, testDB.DB.First(&updated, product.ID).Error)
+		assert.Equal(t, "Renamed Product", updated.Name)
+		assert.Equal(t, "59.99", updated.Price.String())
+		assert.Equal(t, 0, updated.Stock)
+		assert.Equal(t, category.ID, updated.CategoryID)
+
+		var created models.Product
+		assert.NoError(t, testDB.DB.First(&created, "slug = ?", "split-keyboard").Error)
+		assert.Equal(t, "199.00", created.Price.String())
+	})
+
+	t.Run("Invalid File", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, upload("name,colour\n", false).Code)
+	})
+
+	t.Run("Large Files Import In The Background", func(t *testing.T) {
+		h.Workers = background.NewGroup()
+		defer func() { h.Workers = nil }()
+
+		image := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(echo.HeaderContentType, "image/png")
+			_, _ = w.Write([]byte("fake image content"))
+		}))
+		defer image.Close()
+
+		// The test server listens on loopback, which the import's own client refuses
+		publicOnly := imageClient
+		imageClient = image.Client()
+		defer func() { imageClient = publicOnly }()
+
+		rows := []string{"name,description,price,stock,category,images"}
+		for i := 0; i < inlineImportRows; i++ {
+			rows = append(rows, fmt.Sprintf("Bulk %d,Bulk product,1.00,1,keyboards,", i))
+		}
+		rows = append(rows, "Pictured,With a picture,1.00,1,keyboards,"+image.URL+"/keyboard")
+
+		rec := upload(strings.Join(rows, "\n"), false)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		job := importOf(rec)
+		assert.Equal(t, fmt.Sprintf("/admin/products/import/%d", job.ID), rec.Header().Get(echo.HeaderLocation))
+
+		assert.NoError(t, h.Workers.Shutdown(context.Background()))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec = httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(job.ID))
+		assert.NoError(t, h.GetProductImport(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		job = importOf(rec)
+		assert.Equal(t, models.ImportCompleted, job.Status)
+		assert.Equal(t, inlineImportRows+1, job.Created)
+		assert.Empty(t, job.Errors)
+
+		pictured, err := repositories.GetProductBySlug("pictured", testDB.DB)
+		assert.NoError(t, err)
+		if assert.Len(t, pictured.ProductImages, 1) {
+			assert.True(t, pictured.ProductImages[0].PrimaryImage)
+			assert.Equal(t, ".png", filepath.Ext(pictured.ProductImages[0].Image))
+			deleteImage(filepath.Join(productImageDirectory, pictured.ProductImages[0].Image))
+		}
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/products/export.csv", nil)
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, h.ExportProducts(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Equal(t, "slug,name,description,price,stock,weight,category,images", lines[0])
+		assert.Equal(t, fmt.Sprintf("%s,Renamed Product,Updated,59.99,0,0,keyboards,", product.Slug), lines[1])
+		assert.Len(t, lines, 1+2+inlineImportRows+1)
+	})
+}
+
+func TestImageDownloadsOnlyReachPublicAddresses(t *testing.T) {
+	for address, public := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1":     true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"0.0.0.0":          false,
+		"100.64.0.1":       false,
+		"::ffff:127.0.0.1": false,
+	} {
+		assert.Equal(t, public, publicIP(net.ParseIP(address)), address)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(echo.HeaderContentType, "image/png")
+		_, _ = w.Write([]byte("fake image content"))
+	}))
+	defer server.Close()
+
+	_, err := downloadProductImage(context.Background(), server.URL+"/keyboard.png")
+	assert.Error(t, err, "the server listens on loopback")
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/apperr"
+	"keylab/database/models"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// questionProduct loads the product named by the product_slug path parameter.
+func (h *Handlers) questionProduct(c echo.Context) (models.Product, *apperr.Error) {
+	product, err := repositories.GetProductBySlug(c.Param("product_slug"), h.db(c))
+	if err != nil {
+		return product, apperr.NotFound("Product not found")
+	}
+
+	return product, nil
+}
+
+// shownQuestion loads the question named by the id path parameter if it is published and about
+// the product named by the product_slug path parameter, with its published answers.
+func (h *Handlers) shownQuestion(c echo.Context) (models.ProductQuestion, *apperr.Error) {
+	questionID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return models.ProductQuestion{}, apperr.BadRequest("Invalid question ID")
+	}
+
+	product, appErr := h.questionProduct(c)
+	if appErr != nil {
+		return models.ProductQuestion{}, appErr
+	}
+
+	question, err := repositories.GetPublishedQuestion(questionID, product.ID, h.db(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return question, apperr.NotFound("Question not found")
+	}
+	if err != nil {
+		return question, apperr.Internal("Error fetching question", err)
+	}
+
+	return question, nil
+}
+
+// shownAnswer loads the published answer named by the answer_id path parameter of a shown
+// question.
+func (h *Handlers) shownAnswer(c echo.Context) (models.ProductAnswer, *apperr.Error) {
+	question, appErr := h.shownQuestion(c)
+	if appErr != nil {
+		return models.ProductAnswer{}, appErr
+	}
+
+	answerID, err := convertToInt64(c.Param("answer_id"))
+	if err != nil {
+		return models.ProductAnswer{}, apperr.BadRequest("Invalid answer ID")
+	}
+
+	for _, answer := range question.Answers {
+		if answer.ID == answerID {
+			return answer, nil
+		}
+	}
+
+	return models.ProductAnswer{}, apperr.NotFound("Answer not found")
+}
+
+// Get Product Questions Handler [GET /products/:product_slug/questions]
+// 1. Lists the published questions about the product, newest first, each with its published
+// answers, official ones first and then the most upvoted.
+// 2. Returns status 200 with the page of questions and the pagination metadata.
+func (h *Handlers) GetProductQuestions(c echo.Context) error {
+	product, appErr := h.questionProduct(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	page, perPage, offset := getPaginationParams(c)
+
+	questions, total, err := repositories.GetProductQuestions(product.ID, perPage, offset, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching product questions", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching questions", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, "Questions fetched successfully", map[string]interface{}{
+		"questions": questions,
+		"metadata":  generatePaginationResponse(page, perPage, int(total)),
+	})
+}
+
+// Get Product Question Handler [GET /products/:product_slug/questions/:id]
+// 1. Returns a published question about the product with its published answers.
+// 2. Returns status 404 if the question is not shown on the product.
+func (h *Handlers) GetProductQuestion(c echo.Context) error {
+	question, appErr := h.shownQuestion(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Question found", question)
+}
+
+// Ask Question Handler [POST /products/:product_slug/questions]
+// 1. Publishes the user's question about the product.
+// 2. Returns status 201 with the question.
+// 3. Returns status 400 for an invalid question and 404 if there is no such product.
+func (h *Handlers) AskQuestion(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	product, appErr := h.questionProduct(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	var question models.ProductQuestion
+	if err := c.Bind(&question); err != nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid request body"))
+	}
+	question = models.ProductQuestion{ProductID: product.ID, UserID: user.ID, Body: question.Body, Status: models.QuestionPublished}
+
+	if err := question.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Create(&question).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error creating question", "error", err)
+		return apperr.Render(c, apperr.Internal("Error creating question", err))
+	}
+	question.Answers = []models.ProductAnswer{}
+
+	return jsonResponse(c, http.StatusCreated, "Question created successfully", question)
+}
+
+// Answer Question Handler [POST /products/:product_slug/questions/:id/answers]
+// 1. Publishes the user's answer to a published question about the product. Only staff, users
+// whose role has the admin:dashboard permission, and users who had the product delivered can
+// answer, and their answers are flagged as such.
+// 2. Returns status 201 with the answer.
+// 3. Returns status 400 for an invalid answer, 403 if the user cannot answer and 404 if the
+// question is not shown on the product.
+func (h *Handlers) AnswerQuestion(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	question, appErr := h.shownQuestion(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	var answer models.ProductAnswer
+	if err := c.Bind(&answer); err != nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid request body"))
+	}
+	answer = models.ProductAnswer{QuestionID: question.ID, UserID: user.ID, Body: answer.Body, Status: models.QuestionPublished}
+
+	if err := answer.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	staff, err := repositories.CheckRolePermissions(user.RoleID, []string{"admin:dashboard"}, h.db(c))
+	if err != nil {
+		return apperr.Render(c, apperr.Internal("Error creating answer", err))
+	}
+	received, err := repositories.HasReceivedProduct(user.ID, question.ProductID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error checking the user's orders", "error", err)
+		return apperr.Render(c, apperr.Internal("Error creating answer", err))
+	}
+	if !staff && !received {
+		return apperr.Render(c, apperr.Forbidden("Only staff and customers who received this product can answer questions about it"))
+	}
+	answer.Staff = staff
+	answer.Verified = received
+
+	if err := h.db(c).Create(&answer).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error creating answer", "error", err)
+		return apperr.Render(c, apperr.Internal("Error creating answer", err))
+	}
+
+	return jsonResponse(c, http.StatusCreated, "Answer created successfully", answer)
+}
+
+// Upvote Answer Handler [PUT /products/:product_slug/questions/:id/answers/:answer_id/vote]
+// 1. Records the user's upvote of a published answer to a question shown on the product. Upvoting
+// an answer again changes nothing.
+// 2. Returns status 200 with the answer and its updated upvote count.
+// 3. Returns status 400 if the answer is the user's own and 404 if it is not shown on the product.
+func (h *Handlers) UpvoteAnswer(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	answer, appErr := h.shownAnswer(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if answer.UserID == user.ID {
+		return apperr.Render(c, apperr.BadRequest("You cannot upvote your own answer"))
+	}
+
+	vote := models.AnswerVote{AnswerID: answer.ID, UserID: user.ID}
+	if _, err := repositories.SaveAnswerVote(&vote, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving answer vote", "error", err)
+		return apperr.Render(c, apperr.Internal("Error saving vote", err))
+	}
+
+	return h.votedAnswer(c, answer.ID, "Vote saved")
+}
+
+// Delete Answer Vote Handler [DELETE /products/:product_slug/questions/:id/answers/:answer_id/vote]
+// 1. Withdraws the user's upvote of a published answer to a question shown on the product.
+// 2. Returns status 200 with the answer and its updated upvote count.
+// 3. Returns status 404 if the answer is not shown on the product or the user has not upvoted it.
+func (h *Handlers) DeleteAnswerVote(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	answer, appErr := h.shownAnswer(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	deleted, err := repositories.DeleteAnswerVote(answer.ID, user.ID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting answer vote", "error", err)
+		return apperr.Render(c, apperr.Internal("Error deleting vote", err))
+	}
+	if !deleted {
+		return apperr.Render(c, apperr.NotFound("You have not upvoted this answer"))
+	}
+
+	return h.votedAnswer(c, answer.ID, "Vote deleted")
+}
+
+// votedAnswer responds with the answer as recounted after a vote.
+func (h *Handlers) votedAnswer(c echo.Context, answerID int64, message string) error {
+	answer, err := repositories.GetAnswerByID(answerID, h.db(c))
+	if err != nil {
+		return apperr.Render(c, apperr.Internal("Error fetching answer", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, message, answer)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	db "keylab/database"
+	"keylab/database/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductQuestions(t *testing.T) {
+	h, testDB, asker, product := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	newUser := func(email string, phone string, roleID int64) models.User {
+		u := models.User{Forename: "Jane", Surname: "Doe", Email: email, Password: "hashedpassword", PhoneNumber: phone, RoleID: roleID}
+		assert.NoError(t, testDB.DB.Create(&u).Error)
+		return u
+	}
+
+	role := models.Role{Name: "Support"}
+	permission := models.Permission{Name: "admin:dashboard"}
+	assert.NoError(t, testDB.DB.Create(&role).Error)
+	assert.NoError(t, testDB.DB.Where(permission).FirstOrCreate(&permission).Error)
+	assert.NoError(t, testDB.DB.Create(&models.RolePermission{RoleID: role.ID, PermissionID: permission.ID}).Error)
+
+	staff := newUser("staff@example.com", "+1234567891", role.ID)
+	buyer := newUser("buyer@example.com", "+1234567892", 0)
+	browser := newUser("browser@example.com", "+1234567893", 0)
+
+	address := models.Address{UserID: buyer.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "X", Type: models.Shipping}
+	assert.NoError(t, testDB.DB.Create(&address).Error)
+	order := models.Order{UserID: buyer.ID, Status: models.Delivered, Total: product.Price, ShippingAddressID: address.ID, BillingAddressID: address.ID}
+	assert.NoError(t, testDB.DB.Create(&order).Error)
+	assert.NoError(t, testDB.DB.Create(&models.OrderedItem{OrderID: order.ID, ProductID: product.ID, Quantity: 1, Price: product.Price}).Error)
+
+	call := func(handler echo.HandlerFunc, method string, query string, body interface{}, as *models.User, params ...string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, "/?"+query, bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if as != nil {
+			c.Set("user", *as)
+		}
+		c.SetParamNames(append([]string{"product_slug"}, params[0:len(params)/2]...)...)
+		c.SetParamValues(append([]string{product.Slug}, params[len(params)/2:]...)...)
+
+		assert.NoError(t, handler(c))
+		return rec
+	}
+	id := func(id int64) string { return strconv.FormatInt(id, 10) }
+	decode := func(rec *httptest.ResponseRecorder, data interface{}) {
+		response := struct {
+			Data interface{} `json:"data"`
+		}{Data: data}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	}
+	listed := func() []models.ProductQuestion {
+		rec := call(h.GetProductQuestions, http.MethodGet, "", nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var data struct {
+			Questions []models.ProductQuestion `json:"questions"`
+		}
+		decode(rec, &data)
+		return data.Questions
+	}
+
+	var question models.ProductQuestion
+	t.Run("Ask", func(t *testing.T) {
+		rec := call(h.AskQuestion, http.MethodPost, "", map[string]interface{}{"body": "Does it support hot-swap?", "status": "hidden"}, &asker)
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		decode(rec, &question)
+		assert.Equal(t, models.QuestionPublished, question.Status)
+
+		assert.Equal(t, http.StatusBadRequest, call(h.AskQuestion, http.MethodPost, "", map[string]interface{}{"body": ""}, &asker).Code)
+	})
+
+	answers := map[string]models.ProductAnswer{}
+	t.Run("Answer", func(t *testing.T) {
+		answer := func(as models.User, body string) *httptest.ResponseRecorder {
+			return call(h.AnswerQuestion, http.MethodPost, "", map[string]interface{}{"body": body, "official": true}, &as, "id", id(question.ID))
+		}
+
+		assert.Equal(t, http.StatusForbidden, answer(browser, "I think so").Code)
+
+		for name, author := range map[string]models.User{"staff": staff, "buyer": buyer} {
+			rec := answer(author, "Yes, it does")
+			assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+			var created models.ProductAnswer
+			decode(rec, &created)
+			assert.False(t, created.Official, "the flag cannot be set by the client")
+			answers[name] = created
+		}
+		assert.True(t, answers["staff"].Staff)
+		assert.False(t, answers["staff"].Verified)
+		assert.True(t, answers["buyer"].Verified)
+		assert.False(t, answers["buyer"].Staff)
+	})
+
+	t.Run("Upvote", func(t *testing.T) {
+		upvote := func(as models.User, answer models.ProductAnswer) *httptest.ResponseRecorder {
+			return call(h.UpvoteAnswer, http.MethodPut, "", nil, &as, "id", "answer_id", id(question.ID), id(answer.ID))
+		}
+
+		assert.Equal(t, http.StatusOK, upvote(asker, answers["buyer"]).Code)
+		assert.Equal(t, http.StatusOK, upvote(browser, answers["buyer"]).Code)
+		rec := upvote(browser, answers["buyer"])
+		assert.Equal(t, http.StatusOK, rec.Code, "upvoting again changes nothing")
+
+		var upvoted models.ProductAnswer
+		decode(rec, &upvoted)
+		assert.Equal(t, 2, upvoted.UpvoteCount)
+
+		assert.Equal(t, http.StatusBadRequest, upvote(buyer, answers["buyer"]).Code, "own answer")
+
+		assert.Equal(t, http.StatusOK, call(h.DeleteAnswerVote, http.MethodDelete, "", nil, &asker, "id", "answer_id", id(question.ID), id(answers["buyer"].ID)).Code)
+		assert.Equal(t, http.StatusNotFound, call(h.DeleteAnswerVote, http.MethodDelete, "", nil, &asker, "id", "answer_id", id(question.ID), id(answers["buyer"].ID)).Code)
+
+		questions := listed()
+		if assert.Len(t, questions, 1) && assert.Len(t, questions[0].Answers, 2) {
+			assert.Equal(t, answers["buyer"].ID, questions[0].Answers[0].ID, "the most upvoted first")
+			assert.Equal(t, 1, questions[0].Answers[0].UpvoteCount)
+		}
+	})
+
+	t.Run("Moderate", func(t *testing.T) {
+		official := call(h.MarkAnswerOfficial, http.MethodPut, "", map[string]interface{}{"official": true}, &staff, "id", id(answers["staff"].ID))
+		assert.Equal(t, http.StatusOK, official.Code)
+		assert.Equal(t, http.StatusBadRequest, call(h.MarkAnswerOfficial, http.MethodPut, "", map[string]interface{}{}, &staff, "id", id(answers["staff"].ID)).Code)
+
+		questions := listed()
+		if assert.Len(t, questions, 1) && assert.Len(t, questions[0].Answers, 2) {
+			assert.Equal(t, answers["staff"].ID, questions[0].Answers[0].ID, "official answers first")
+		}
+
+		assert.Equal(t, http.StatusOK, call(h.ModerateAnswer, http.MethodPut, "", map[string]interface{}{"status": "hidden"}, &staff, "id", id(answers["buyer"].ID)).Code)
+		assert.Len(t, listed()[0].Answers, 1)
+
+		assert.Equal(t, http.StatusBadRequest, call(h.ModerateQuestion, http.MethodPut, "", map[string]interface{}{"status": "rejected"}, &staff, "id", id(question.ID)).Code)
+		assert.Equal(t, http.StatusOK, call(h.ModerateQuestion, http.MethodPut, "", map[string]interface{}{"status": "hidden"}, &staff, "id", id(question.ID)).Code)
+		assert.Empty(t, listed())
+		assert.Equal(t, http.StatusNotFound, call(h.GetProductQuestion, http.MethodGet, "", nil, nil, "id", id(question.ID)).Code)
+
+		var queue struct {
+			Questions []models.ProductQuestion `json:"questions"`
+		}
+		rec := call(h.GetModerationQuestions, http.MethodGet, "status=hidden", nil, &staff)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		decode(rec, &queue)
+		if assert.Len(t, queue.Questions, 1) {
+			assert.Len(t, queue.Questions[0].Answers, 2, "moderators see hidden answers")
+		}
+
+		assert.Equal(t, http.StatusOK, call(h.DeleteQuestion, http.MethodDelete, "", nil, &staff, "id", id(question.ID)).Code)
+		var remaining int64
+		assert.NoError(t, testDB.DB.Model(&models.ProductAnswer{}).Count(&remaining).Error)
+		assert.Zero(t, remaining)
+	})
+}
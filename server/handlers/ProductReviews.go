@@ -1,117 +1,182 @@
 package handlers
 
 import (
+	"keylab/apperr"
+	"keylab/config"
 	"keylab/database/models"
+	"keylab/ratings"
 	"keylab/repositories"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// verifiedFilter reads the verified query parameter of a review listing, nil when it is not set.
+func verifiedFilter(c echo.Context) (*bool, *apperr.Error) {
+	value := c.QueryParam("verified")
+	if value == "" {
+		return nil, nil
+	}
+
+	verified, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, apperr.BadRequest("Invalid verified filter, use true or false")
+	}
+
+	return &verified, nil
+}
+
+// reviewsDB returns the database handle for a public review listing, limited to verified
+// purchases with verified=true or to the other reviews with verified=false.
+func (h *Handlers) reviewsDB(c echo.Context) (*gorm.DB, *apperr.Error) {
+	verified, appErr := verifiedFilter(c)
+	if appErr != nil || verified == nil {
+		return h.db(c), appErr
+	}
+
+	return h.db(c).Scopes(repositories.VerifiedReviews(*verified)), nil
+}
+
+// reviewPage reads the sort (most helpful by default), page and per_page query parameters of a
+// review listing.
+func reviewPage(c echo.Context) (repositories.ReviewPage, *apperr.Error) {
+	_, perPage, offset := getPaginationParams(c)
+	page := repositories.ReviewPage{Sort: ratings.MostHelpful, Limit: perPage, Offset: offset}
+
+	if value := c.QueryParam("sort"); value != "" {
+		sort, ok := ratings.ParseSort(value)
+		if !ok {
+			return page, apperr.BadRequest("Invalid sort, use helpful, newest, highest or lowest")
+		}
+		page.Sort = sort
+	}
+
+	return page, nil
+}
+
+// reviewList fetches a page of a public review listing with list, answering 404 with notFound
+// when nothing matches.
+func (h *Handlers) reviewList(c echo.Context, notFound string, list func(repositories.ReviewPage, *gorm.DB) ([]models.ProductReviews, int64, error)) error {
+	reviewsDB, appErr := h.reviewsDB(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	page, appErr := reviewPage(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	reviews, total, err := list(page, reviewsDB)
+	if err != nil {
+		return apperr.Render(c, apperr.Internal("Error fetching reviews", err))
+	}
+	if total == 0 {
+		return jsonResponse(c, http.StatusNotFound, notFound)
+	}
+	repositories.SetReviewPhotoURLs(reviews, config.Get().SERVER_URL)
+
+	return jsonResponse(c, http.StatusOK, "Reviews found", map[string]interface{}{
+		"reviews":  reviews,
+		"metadata": generatePaginationResponse(page.Offset/page.Limit+1, page.Limit, int(total)),
+	})
+}
+
 // Get Reviews By User [GET /products/:product_slug/reviews/users/:user_id/reviews]
 // 1. Fetches user id from params and converts it to int64.
 // 2. Checks if user exists in the database.
-// 3. Fetches reviews by user from the database.
-// 4. Returns status 200 with the reviews if successful.
+// 3. Fetches a page of the user's reviews, sorted by sort (helpful, newest, highest or lowest).
+// 4. Returns status 200 with the reviews and the pagination metadata if successful.
 // 5. Returns status 404 if no reviews are found.
 // 6. Returns status 500 if an error occurs.
 
 func (h *Handlers) GetReviewByUser(c echo.Context) error {
-	var reviews []models.ProductReviews
 	var user models.User
 
 	userID, err := convertToInt64(c.Param("user_id"))
 	if err != nil {
-		log.Printf("Error converting user ID to int64: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error converting user ID to int64", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
 
-	user, err = repositories.FindUserByID(userID, h.DB)
+	user, err = repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Cannot find user with that ID")
 	}
 
-	reviews, err = repositories.GetReviewByUserID(user.ID, h.DB)
-	if err != nil || len(reviews) == 0 {
-		return jsonResponse(c, http.StatusNotFound, "No reviews found for this user")
-	}
-
-	return jsonResponse(c, http.StatusOK, "Reviews found", reviews)
+	return h.reviewList(c, "No reviews found for this user", func(page repositories.ReviewPage, db *gorm.DB) ([]models.ProductReviews, int64, error) {
+		return repositories.GetReviewByUserID(user.ID, page, db)
+	})
 }
 
 // Get Reviews For Product [GET /products/:product_slug/reviews]
 // 1. Gets product slug from params and validates it
 // 2. Fetches product by slug from the database
-// 3. Fetches reviews by product from the database
-// 4. Returns status 200 with the reviews if successful
+// 3. Fetches a page of the product's reviews, sorted by sort (helpful, newest, highest or lowest)
+// 4. Returns status 200 with the reviews and the pagination metadata if successful
 // 5. Returns status 404 if no reviews are found
 // 6. Returns status 500 if an error occurs
 
 func (h *Handlers) GetReviewsByProduct(c echo.Context) error {
-	var reviews []models.ProductReviews
 	var product models.Product
 
-	product, err := repositories.GetProductBySlug(c.Param("product_slug"), h.DB)
+	product, err := repositories.GetProductBySlug(c.Param("product_slug"), h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Cannot find product with that slug")
 	}
 
-	reviews, err = repositories.GetReviewsByProductID(product.ID, h.DB)
-	if err != nil || len(reviews) == 0 {
-		return jsonResponse(c, http.StatusNotFound, "No reviews found for this product")
-	}
-
-	return jsonResponse(c, http.StatusOK, "Reviews found", reviews)
+	return h.reviewList(c, "No reviews found for this product", func(page repositories.ReviewPage, db *gorm.DB) ([]models.ProductReviews, int64, error) {
+		return repositories.GetReviewsByProductID(product.ID, page, db)
+	})
 }
 
 // Get Reviews By User [GET /products/:product_slug/reviews/users/:user_id/reviews]
 // 1. Fetches user id from params and converts it to int64.
 // 2. Checks if user exists in the database.
-// 3. Fetches reviews by user from the database.
-// 4. Returns status 200 with the reviews if successful.
+// 3. Fetches a page of the user's reviews, sorted by sort (helpful, newest, highest or lowest).
+// 4. Returns status 200 with the reviews and the pagination metadata if successful.
 // 5. Returns status 404 if no reviews are found.
 // 6. Returns status 500 if an error occurs.
 
 func (h *Handlers) GetReviewsByUser(c echo.Context) error {
-	var reviews []models.ProductReviews
 	var user models.User
 
 	userID, err := convertToInt64(c.Param("user_id"))
 	if err != nil {
-		log.Printf("Error converting user ID to int64: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error converting user ID to int64", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
 
-	user, err = repositories.FindUserByID(userID, h.DB)
+	user, err = repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Cannot find user with that ID")
 	}
 
-	reviews, err = repositories.GetReviewByUserID(user.ID, h.DB)
-	if err != nil || len(reviews) == 0 {
-		return jsonResponse(c, http.StatusNotFound, "No reviews found for this user")
-	}
-
-	return jsonResponse(c, http.StatusOK, "Reviews found", reviews)
+	return h.reviewList(c, "No reviews found for this user", func(page repositories.ReviewPage, db *gorm.DB) ([]models.ProductReviews, int64, error) {
+		return repositories.GetReviewByUserID(user.ID, page, db)
+	})
 }
 
 // Get Review [GET /products/:product_slug/reviews/:id]
 // 1. Fetches review by ID and validates it.
 // 2. Fetches reviews by ID from the database.
 // 3. Returns status 200 with the review if successful.
-// 4. Returns status 404 if the review is not found.
+// 4. Returns status 404 if the review is not found or not approved.
 
 func (h *Handlers) GetReview(c echo.Context) error {
 	var review models.ProductReviews
 
 	reviewID, err := convertToInt64(c.Param("id"))
 	if err != nil {
-		log.Printf("Error converting review ID to int64: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error converting review ID to int64", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid review ID")
 	}
 
-	product, err := repositories.GetProductBySlug(c.Param("product_slug"), h.DB)
+	product, err := repositories.GetProductBySlug(c.Param("product_slug"), h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
@@ -120,24 +185,23 @@ func (h *Handlers) GetReview(c echo.Context) error {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
-	review, err = repositories.GetReviewByID(reviewID, h.DB)
-	if err != nil {
+	review, err = repositories.GetReviewByID(reviewID, h.db(c))
+	if err != nil || review.Status != models.ReviewApproved {
 		return jsonResponse(c, http.StatusNotFound, "Review not found")
 	}
 
-	return jsonResponse(c, http.StatusOK, "Review found", review)
+	return jsonResponse(c, http.StatusOK, "Review found", reviewWithPhotoURLs(review))
 }
 
 // Get Review Statistics [GET /products/:product_slug/reviews/statistics]
 // 1. Fetches product by slug and validates it.
-// 2. Fetches reviews by product from the database.
-// 3. Calculates the total number of reviews and average rating.
+// 2. Counts the product's approved reviews per rating in the database.
+// 3. Returns the total number of reviews, the average rating and the distribution of 1 to 5 stars.
 // 4. Returns status 200 with the review statistics if successful.
 // 5. Returns status 404 if no reviews are found.
 // 6. Returns status 500 if an error occurs.
 
 func (h *Handlers) GetReviewStatistics(c echo.Context) error {
-	var reviews []models.ProductReviews
 	var product models.Product
 
 	slug := c.Param("product_slug")
@@ -145,55 +209,47 @@ func (h *Handlers) GetReviewStatistics(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid product slug")
 	}
 
-	product, err := repositories.GetProductBySlug(slug, h.DB)
+	product, err := repositories.GetProductBySlug(slug, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
-	reviews, err = repositories.GetReviewsByProductID(product.ID, h.DB)
-	if err != nil || len(reviews) == 0 {
-		return jsonResponse(c, http.StatusNotFound, "No reviews found for this product")
-	}
-
-	var (
-		totalReviews  = len(reviews)
-		averageRating float64
-	)
-
-	for _, review := range reviews {
-		averageRating += float64(review.Rating)
+	summary, err := repositories.GetReviewSummary(product.ID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching review statistics", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching review statistics", err))
 	}
-	averageRating = averageRating / float64(totalReviews)
-
-	reviewStatistics := map[string]interface{}{
-		"total_reviews":  totalReviews,
-		"average_rating": averageRating,
+	if summary.TotalReviews == 0 {
+		return jsonResponse(c, http.StatusNotFound, "No reviews found for this product")
 	}
 
-	return jsonResponse(c, http.StatusOK, "Review statistics", reviewStatistics)
+	return jsonResponse(c, http.StatusOK, "Review statistics", summary)
 }
 
 // Create Review [POST /products/:product_slug/reviews]
-// 1. Binds the request body to the review struct.
+// 1. Binds the request body, JSON or a multipart form with up to 5 photos, to the review struct.
 // 2. Validates the review input.
 // 3. Validates the product slug.
 // 4. Validates that the user has not already reviewed the product.
-// 5. Creates the review in the database.
-// 6. Returns status 200 if the review is created successfully.
-// 7. Returns status 404 if the product is not found.
-// 8. Returns status 400 if invalid input is provided.
-// 9. Returns status 500 if an error occurs.
+// 5. Flags the review as a verified purchase when the user had the product delivered.
+// 6. Saves the photos and creates the review in the database, pending moderation when
+// REVIEW_PREMODERATION is on, and updates the product's rating.
+// 7. Returns status 200 if the review is created successfully.
+// 8. Returns status 403 if the user has not received the product and REVIEW_PURCHASE_POLICY is require.
+// 9. Returns status 404 if the product is not found.
+// 10. Returns status 400 if invalid input is provided.
+// 11. Returns status 500 if an error occurs.
 
 func (h *Handlers) CreateReview(c echo.Context) error {
 	var review models.ProductReviews
 	var product models.Product
 
 	if err := c.Bind(&review); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, "Invalid input creating review", err.Error())
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input creating review")
 	}
 
 	if err := review.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
 	productSlug := c.Param("product_slug")
@@ -201,7 +257,7 @@ func (h *Handlers) CreateReview(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid product slug")
 	}
 
-	product, err := repositories.GetProductBySlug(productSlug, h.DB)
+	product, err := repositories.GetProductBySlug(productSlug, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
@@ -211,28 +267,66 @@ func (h *Handlers) CreateReview(c echo.Context) error {
 	user := c.Get("user").(models.User)
 	review.UserID = user.ID
 
+	review.Status = models.ReviewApproved
+	if config.Get().REVIEW_PREMODERATION {
+		review.Status = models.ReviewPending
+	}
+	review.ReportCount = 0
+	review.ModeratedAt = nil
+	review.HelpfulCount, review.NotHelpfulCount = 0, 0
+
 	var existingReview models.ProductReviews
-	if err := h.DB.Where("product_id = ? AND user_id = ?", review.ProductID, review.UserID).First(&existingReview).Error; err == nil {
+	if err := h.db(c).Where("product_id = ? AND user_id = ?", review.ProductID, review.UserID).First(&existingReview).Error; err == nil {
 		return jsonResponse(c, http.StatusBadRequest, "User has already reviewed this product")
 	}
 
-	if err := h.DB.Create(&review).Error; err != nil {
-		log.Printf("Error creating review: %v", err)
+	received, err := repositories.HasReceivedProduct(user.ID, product.ID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error checking the user's orders", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error creating review")
+	}
+	if !received && config.Get().REVIEW_PURCHASE_POLICY == config.ReviewsRequirePurchase {
+		return jsonResponse(c, http.StatusForbidden, "Only customers who received this product can review it")
+	}
+	review.Verified = received
+
+	photos, appErr := uploadReviewPhotos(c, 0)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+	review.Photos = photos
+
+	err = h.db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		return repositories.RefreshProductRating(review.ProductID, tx)
+	})
+	if err != nil {
+		deleteReviewPhotoFiles(photos)
+		slog.ErrorContext(c.Request().Context(), "Error creating review", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating review")
 	}
 
-	if err := h.DB.Preload("Product").Preload("Product.Category").First(&review, review.ID).Error; err != nil {
-		log.Printf("Error fetching created review with related data: %v", err)
+	if err := h.db(c).Preload("Product").Preload("Product.Category").First(&review, review.ID).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching created review with related data", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching related data for created review")
 	}
 
+	review = reviewWithPhotoURLs(review)
+	if review.Status == models.ReviewPending {
+		return jsonResponse(c, http.StatusOK, "Review submitted for moderation", review)
+	}
+
 	return jsonResponse(c, http.StatusOK, "Review created successfully!", review)
 }
 
 // Update Review Handler [PUT /reviews/:id]
 // 1. Fetches review by ID and validates it.
 // 2. Validates that the logged-in user is the one who created the review.
-// 3. Updates review fields based on input.
+// 3. Updates review fields based on input, JSON or a multipart form, adding the uploaded photos up to
+// 5 in all, and updates the product's rating. Under REVIEW_PREMODERATION the review goes back to
+// pending.
 // 4. Returns status 200 if the update is successful.
 // 5. Returns status 404 if the review is not found.
 // 6. Returns status 400 if invalid input is provided.
@@ -246,7 +340,7 @@ func (h *Handlers) UpdateReview(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid review ID")
 	}
 
-	review, err = repositories.GetReviewByID(reviewID, h.DB)
+	review, err = repositories.GetReviewByID(reviewID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Review not found")
 	}
@@ -256,26 +350,67 @@ func (h *Handlers) UpdateReview(c echo.Context) error {
 		return jsonResponse(c, http.StatusForbidden, "You are not authorized to update this review")
 	}
 
+	stored := review
 	if err := c.Bind(&review); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, "Invalid input updating review", err.Error())
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input updating review")
+	}
+
+	keepReviewState(&review, stored)
+	if config.Get().REVIEW_PREMODERATION {
+		review.Status = models.ReviewPending
+	}
+
+	if err := review.Validate("Rating", "Title", "Comment"); err != nil {
+		return validationError(c, err)
 	}
 
-	if err := review.Validate("Rating", "Comment"); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+	photos, appErr := uploadReviewPhotos(c, len(review.Photos))
+	if appErr != nil {
+		return apperr.Render(c, appErr)
 	}
 
-	if err := h.DB.Save(&review).Error; err != nil {
-		log.Printf("Error updating review: %v", err)
+	err = h.db(c).Transaction(func(tx *gorm.DB) error {
+		// The vote counts are left to the votes, which may change them concurrently.
+		if err := tx.Omit(clause.Associations, "helpful_count", "not_helpful_count").Save(&review).Error; err != nil {
+			return err
+		}
+
+		for i := range photos {
+			photos[i].ReviewID = review.ID
+		}
+		if len(photos) > 0 {
+			if err := tx.Create(&photos).Error; err != nil {
+				return err
+			}
+		}
+
+		return repositories.RefreshProductRating(review.ProductID, tx)
+	})
+	if err != nil {
+		deleteReviewPhotoFiles(photos)
+		slog.ErrorContext(c.Request().Context(), "Error updating review", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating review")
 	}
+	review.Photos = append(review.Photos, photos...)
 
-	return jsonResponse(c, http.StatusOK, "Review updated successfully", review)
+	return jsonResponse(c, http.StatusOK, "Review updated successfully", reviewWithPhotoURLs(review))
+}
+
+// keepReviewState restores the fields of a review that its author cannot change, after their
+// update has been bound over it.
+func keepReviewState(review *models.ProductReviews, stored models.ProductReviews) {
+	review.ID, review.ProductID, review.UserID = stored.ID, stored.ProductID, stored.UserID
+	review.Status, review.ReportCount, review.ModeratedAt = stored.Status, stored.ReportCount, stored.ModeratedAt
+	review.Verified = stored.Verified
+	review.HelpfulCount, review.NotHelpfulCount = stored.HelpfulCount, stored.NotHelpfulCount
+	review.Photos = stored.Photos
 }
 
 // Delete Review Handler [DELETE /reviews/:id]
 // 1. Fetches review by ID and validates it.
 // 2. Validates that the logged-in user is the one who created the review.
-// 3. Deletes the review from the database.
+// 3. Deletes the review from the database and its photos from the server, and updates the
+// product's rating.
 // 4. Returns status 200 if the deletion is successful.
 // 5. Returns status 404 if the review is not found.
 // 6. Returns status 500 if an error occurs.
@@ -288,7 +423,7 @@ func (h *Handlers) DeleteReview(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid review ID")
 	}
 
-	review, err = repositories.GetReviewByID(reviewID, h.DB)
+	review, err = repositories.GetReviewByID(reviewID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Review not found")
 	}
@@ -298,10 +433,17 @@ func (h *Handlers) DeleteReview(c echo.Context) error {
 		return jsonResponse(c, http.StatusForbidden, "You are not authorized to delete this review")
 	}
 
-	if err := h.DB.Delete(&review).Error; err != nil {
-		log.Printf("Error deleting review: %v", err)
+	err = h.db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&review).Error; err != nil {
+			return err
+		}
+		return repositories.RefreshProductRating(review.ProductID, tx)
+	})
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting review", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error deleting review")
 	}
+	deleteReviewPhotoFiles(review.Photos)
 
 	return jsonResponse(c, http.StatusOK, "Review deleted successfully", review)
 }
@@ -316,22 +458,23 @@ func (h *Handlers) GetUserReview(c echo.Context) error {
 	var review []models.ProductReviews
 
 	productSlug := c.Param("product_slug")
-	product, err := repositories.GetProductBySlug(productSlug, h.DB)
+	product, err := repositories.GetProductBySlug(productSlug, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
 	user := c.Get("user").(models.User)
-	if err := h.DB.Where("product_id = ? AND user_id = ?", product.ID, user.ID).First(&review).Error; err != nil {
+	if err := h.db(c).Preload("Photos").Where("product_id = ? AND user_id = ?", product.ID, user.ID).First(&review).Error; err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Review not found")
 	}
+	repositories.SetReviewPhotoURLs(review, config.Get().SERVER_URL)
 
 	return jsonResponse(c, http.StatusOK, "Review found", review)
 }
 
 // FetchRecentViews [GET /reviews/recent]
 // 1. Gets the limit parameter from the query string
-// 2. Fetches the most recent reviews from the database
+// 2. Fetches the most recent approved reviews from the database
 // 3. Returns status 200 with the reviews if successful
 // 4. Returns status 400 if invalid parameters are provided
 // 5. Returns status 500 if an error occurs
@@ -353,9 +496,14 @@ func (h *Handlers) FetchRecentViews(c echo.Context) error {
 		limit = int(parsedLimit)
 	}
 
-	var reviews []models.ProductReviews
-	if err := h.DB.Preload("User").Preload("Product").Order("created_at DESC").Limit(limit).Find(&reviews).Error; err != nil {
-		log.Printf("Error fetching recent reviews: %v", err)
+	reviewsDB, appErr := h.reviewsDB(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	reviews, err := repositories.GetRecentReviews(limit, reviewsDB)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching recent reviews", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching recent reviews")
 	}
 
@@ -363,5 +511,6 @@ func (h *Handlers) FetchRecentViews(c echo.Context) error {
 		return jsonResponse(c, http.StatusOK, "No reviews found", []models.ProductReviews{})
 	}
 
+	repositories.SetReviewPhotoURLs(reviews, config.Get().SERVER_URL)
 	return jsonResponse(c, http.StatusOK, "Recent reviews found", reviews)
 }
@@ -3,8 +3,12 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"keylab/config"
 	db "keylab/database"
 	"keylab/database/models"
+	"keylab/money"
+	"keylab/ratings"
+	"keylab/repositories"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -41,7 +45,7 @@ func setupReviewHandler(t *testing.T) (*Handlers, *db.TestDB, models.User, model
 		Name:        "Test Product",
 		Slug:        "test-product",
 		Description: "Test Description",
-		Price:       10.0,
+		Price:       money.MustParse("10.00"),
 		Stock:       0,
 		CategoryID:  category.ID,
 		CreatedAt:   time.Now(),
@@ -315,3 +319,171 @@ func TestDeleteReview(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 }
+
+func TestVerifiedPurchaseReviews(t *testing.T) {
+	h, testDB, user, product := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	newUser := func(email string, phone string) models.User {
+		u := models.User{Forename: "Jane", Surname: "Doe", Email: email, Password: "hashedpassword", PhoneNumber: phone}
+		assert.NoError(t, testDB.DB.Create(&u).Error)
+		return u
+	}
+	order := func(buyer models.User, status models.OrderStatus) {
+		address := models.Address{UserID: buyer.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "X", Type: models.Shipping}
+		assert.NoError(t, testDB.DB.Create(&address).Error)
+		o := models.Order{UserID: buyer.ID, Status: status, Total: product.Price, ShippingAddressID: address.ID, BillingAddressID: address.ID}
+		assert.NoError(t, testDB.DB.Create(&o).Error)
+		assert.NoError(t, testDB.DB.Create(&models.OrderedItem{OrderID: o.ID, ProductID: product.ID, Quantity: 1, Price: product.Price}).Error)
+	}
+	create := func(author models.User) int {
+		body, _ := json.Marshal(map[string]interface{}{"rating": 4, "comment": "Nice", "verified_purchase": true})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", author)
+		c.SetParamNames("product_slug")
+		c.SetParamValues(product.Slug)
+
+		assert.NoError(t, h.CreateReview(c))
+		return rec.Code
+	}
+	verified := func(author models.User) bool {
+		var review models.ProductReviews
+		assert.NoError(t, testDB.DB.Where("user_id = ?", author.ID).First(&review).Error)
+		return review.Verified
+	}
+
+	buyer := newUser("buyer@example.com", "+1234567891")
+	order(buyer, models.Delivered)
+
+	t.Run("Flag", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, create(user))
+		assert.False(t, verified(user), "the flag cannot be set by the client")
+
+		assert.Equal(t, http.StatusOK, create(buyer))
+		assert.True(t, verified(buyer))
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?verified=true", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("product_slug")
+		c.SetParamValues(product.Slug)
+
+		assert.NoError(t, h.GetReviewsByProduct(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data struct {
+				Reviews []models.ProductReviews `json:"reviews"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response.Data.Reviews, 1) {
+			assert.Equal(t, buyer.ID, response.Data.Reviews[0].UserID)
+		}
+	})
+
+	t.Run("Require", func(t *testing.T) {
+		cfg := config.Get()
+		cfg.REVIEW_PURCHASE_POLICY = config.ReviewsRequirePurchase
+		defer func() { cfg.REVIEW_PURCHASE_POLICY = config.ReviewsFlagUnverified }()
+
+		waiting := newUser("waiting@example.com", "+1234567892")
+		order(waiting, models.Shipped)
+		assert.Equal(t, http.StatusForbidden, create(waiting), "the order has not been delivered yet")
+
+		customer := newUser("customer@example.com", "+1234567893")
+		order(customer, models.Delivered)
+		assert.Equal(t, http.StatusOK, create(customer))
+	})
+}
+
+func TestProductRating(t *testing.T) {
+	h, testDB, user, product := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	other := models.User{Forename: "Jane", Surname: "Doe", Email: "jane.doe@example.com", Password: "hashedpassword", PhoneNumber: "+1234567891"}
+	assert.NoError(t, testDB.DB.Create(&other).Error)
+
+	call := func(handler echo.HandlerFunc, method string, body interface{}, as models.User, params ...string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, "/", bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", as)
+		c.SetParamNames(append([]string{"product_slug"}, params[0:len(params)/2]...)...)
+		c.SetParamValues(append([]string{product.Slug}, params[len(params)/2:]...)...)
+
+		assert.NoError(t, handler(c))
+		return rec
+	}
+	rating := func() (float64, int) {
+		var stored models.Product
+		assert.NoError(t, testDB.DB.First(&stored, product.ID).Error)
+		return stored.AverageRating, stored.ReviewCount
+	}
+
+	assert.Equal(t, http.StatusOK, call(h.CreateReview, http.MethodPost, map[string]interface{}{"rating": 5, "comment": "Great", "average_rating": 1}, user).Code)
+	assert.Equal(t, http.StatusOK, call(h.CreateReview, http.MethodPost, map[string]interface{}{"rating": 2, "comment": "Meh"}, other).Code)
+	average, count := rating()
+	assert.Equal(t, 3.5, average)
+	assert.Equal(t, 2, count)
+
+	t.Run("Statistics", func(t *testing.T) {
+		rec := call(h.GetReviewStatistics, http.MethodGet, nil, user)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data ratings.Summary `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, int64(2), response.Data.TotalReviews)
+		assert.Equal(t, 3.5, response.Data.AverageRating)
+		assert.Equal(t, map[int]int64{1: 0, 2: 1, 3: 0, 4: 0, 5: 1}, response.Data.Distribution)
+	})
+
+	var review models.ProductReviews
+	assert.NoError(t, testDB.DB.Where("user_id = ?", other.ID).First(&review).Error)
+	id := strconv.FormatInt(review.ID, 10)
+
+	t.Run("Update", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(h.UpdateReview, http.MethodPut, map[string]interface{}{"rating": 4, "comment": "Better"}, other, "id", id).Code)
+		average, _ := rating()
+		assert.Equal(t, 4.5, average)
+	})
+
+	t.Run("Moderation", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(h.ModerateReview, http.MethodPut, map[string]interface{}{"status": "hidden"}, user, "id", id).Code)
+		average, count := rating()
+		assert.Equal(t, 5.0, average)
+		assert.Equal(t, 1, count, "hidden reviews are not counted")
+
+		assert.Equal(t, http.StatusOK, call(h.ModerateReview, http.MethodPut, map[string]interface{}{"status": "approved"}, user, "id", id).Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(h.DeleteReview, http.MethodDelete, nil, other, "id", id).Code)
+		average, count := rating()
+		assert.Equal(t, 5.0, average)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Sort Products By Rating", func(t *testing.T) {
+		unrated := models.Product{Name: "Unrated", Slug: "unrated", Description: "No reviews", Price: money.MustParse("5.00"), Stock: 1, CategoryID: product.CategoryID}
+		assert.NoError(t, testDB.DB.Create(&unrated).Error)
+
+		products, err := repositories.GetProducts("average_rating desc, review_count desc", 10, 0, testDB.DB)
+		assert.NoError(t, err)
+		if assert.Len(t, products, 2) {
+			assert.Equal(t, product.ID, products[0].ID)
+			assert.Equal(t, 5.0, products[0].AverageRating)
+		}
+	})
+}
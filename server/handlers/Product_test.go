@@ -14,6 +14,7 @@ import (
 
 	db "keylab/database"
 	"keylab/database/models"
+	"keylab/money"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -31,7 +32,7 @@ func setupProductTest(t *testing.T) (*Handlers, *db.TestDB, models.Product) {
 		Name:        "Sample Product",
 		Slug:        "sample-product",
 		Description: "This is a sample product",
-		Price:       49.99,
+		Price:       money.MustParse("49.99"),
 		CategoryID:  category.ID,
 		Stock:       10,
 	}
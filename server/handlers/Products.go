@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"fmt"
+	"keylab/apperr"
 	"keylab/config"
 	"keylab/database/models"
 	"keylab/repositories"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -24,6 +25,21 @@ func wrapData(products []models.Product) []map[string]interface{} {
 	return result
 }
 
+// productSortOrder is getSortOrder for product listings, where sort=rating orders by the average
+// rating and then by the number of reviews.
+func productSortOrder(c echo.Context) string {
+	if c.QueryParam("sort") != "rating" {
+		return getSortOrder(c)
+	}
+
+	direction := "desc"
+	if c.QueryParam("direction") == "asc" {
+		direction = "asc"
+	}
+
+	return fmt.Sprintf("average_rating %[1]s, review_count %[1]s", direction)
+}
+
 // List Products [GET /products] or [GET /products?page=1&per_page=10]
 // 1. Fetches all products from the database.
 // 2. Returns status 200 with the products if successful.
@@ -32,22 +48,26 @@ func wrapData(products []models.Product) []map[string]interface{} {
 func (h *Handlers) ListProducts(c echo.Context) error {
 	var products []models.Product
 
-	config := config.Initialize()
+	config := config.Get()
 
 	page, perPage, offset := getPaginationParams(c)
-	order := getSortOrder(c)
+	order := productSortOrder(c)
 
-	products, err := repositories.GetProducts(order, perPage, offset, h.DB)
+	products, err := repositories.GetProducts(order, perPage, offset, h.db(c))
 	if err != nil {
-		log.Printf("Error fetching products: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching products", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching products")
 	}
 
 	repositories.SetProductImageURLs(products, config.SERVER_URL)
+	h.localizeProducts(c, products)
+	if err := h.priceProducts(c, products); err != nil {
+		return apperr.Render(c, err)
+	}
 
 	var total int64
-	if err := h.DB.Model(&models.Product{}).Count(&total).Error; err != nil {
-		log.Printf("Error counting products: %v", err)
+	if err := h.db(c).Model(&models.Product{}).Count(&total).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error counting products", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error counting products")
 	}
 
@@ -67,23 +87,28 @@ func (h *Handlers) ListProducts(c echo.Context) error {
 func (h *Handlers) GetProductBySlug(c echo.Context) error {
 	var product models.Product
 
-	config := config.Initialize()
+	config := config.Get()
 
 	slug := c.Param("slug")
 	if err := product.Validate(slug); err != nil {
-		log.Printf("Error validating product slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error validating product slug", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid product slug")
 	}
 
-	product, err := repositories.GetProductBySlug(slug, h.DB)
+	product, err := repositories.GetProductBySlug(slug, h.db(c))
 	if err != nil {
-		log.Printf("Error fetching product by slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching product by slug", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
-	repositories.SetProductImageURLs([]models.Product{product}, config.SERVER_URL)
+	products := []models.Product{product}
+	repositories.SetProductImageURLs(products, config.SERVER_URL)
+	h.localizeProducts(c, products)
+	if err := h.priceProducts(c, products); err != nil {
+		return apperr.Render(c, err)
+	}
 
-	return jsonResponse(c, http.StatusOK, "Product found", product)
+	return jsonResponse(c, http.StatusOK, "Product found", products[0])
 }
 
 // Get Products By Category [GET /products/category/:id]
@@ -96,23 +121,27 @@ func (h *Handlers) GetProductBySlug(c echo.Context) error {
 func (h *Handlers) GetProductsByCategory(c echo.Context) error {
 	var products []models.Product
 
-	config := config.Initialize()
+	config := config.Get()
 
 	category, err := convertToInt64(c.Param("id"))
 	if err != nil {
-		log.Printf("Error converting category ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error converting category ID", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid category ID")
 	}
 
 	page, perPage, offset := getPaginationParams(c)
-	order := getSortOrder(c)
+	order := productSortOrder(c)
 
-	if err := h.DB.Preload("Category").Preload("Category.Parent").Preload("ProductImages").Order(order).Where("category_id = ?", category).Limit(perPage).Offset(offset).Find(&products).Error; err != nil {
-		log.Printf("Error fetching products by category: %v", err)
+	if err := h.db(c).Preload("Category").Preload("Category.Parent").Preload("ProductImages").Order(order).Where("category_id = ?", category).Limit(perPage).Offset(offset).Find(&products).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching products by category", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching products by category")
 	}
 
 	repositories.SetProductImageURLs(products, config.SERVER_URL)
+	h.localizeProducts(c, products)
+	if err := h.priceProducts(c, products); err != nil {
+		return apperr.Render(c, err)
+	}
 
 	return jsonResponse(c, http.StatusOK, "Products fetched successfully", map[string]interface{}{
 		"products": wrapData(products),
@@ -133,16 +162,16 @@ func (h *Handlers) CreateProduct(c echo.Context) error {
 	var product models.Product
 
 	if err := c.Bind(&product); err != nil {
-		log.Printf("Error binding product data: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error binding product data", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid input for creating product")
 	}
 
 	if err := product.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
-	if err := h.DB.First(&product.Category, "id = ?", product.CategoryID).Error; err != nil {
-		log.Printf("Error fetching category: %v", err)
+	if err := h.db(c).First(&product.Category, "id = ?", product.CategoryID).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching category", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Category not found")
 	}
 
@@ -150,9 +179,9 @@ func (h *Handlers) CreateProduct(c echo.Context) error {
 	destination := "public/images/product_images"
 	allowedExtensions := []string{".jpg", ".jpeg", ".png", ".webp"}
 
-	transaction := h.DB.Begin()
+	transaction := h.db(c).Begin()
 	if transaction.Error != nil {
-		log.Printf("Error starting transaction: %v", transaction.Error)
+		slog.ErrorContext(c.Request().Context(), "Error starting transaction", "error", transaction.Error)
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to initiate transaction")
 	}
 
@@ -164,7 +193,7 @@ func (h *Handlers) CreateProduct(c echo.Context) error {
 
 	if err := transaction.Create(&product).Error; err != nil {
 		transaction.Rollback()
-		log.Printf("Error creating product: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error creating product", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating product")
 	}
 
@@ -172,20 +201,20 @@ func (h *Handlers) CreateProduct(c echo.Context) error {
 	product.Slug = baseSlug
 
 	var existingProduct models.Product
-	if _, err := repositories.GetProductBySlug(product.Slug, h.DB); err == nil && existingProduct.ID != product.ID {
+	if _, err := repositories.GetProductBySlug(product.Slug, h.db(c)); err == nil && existingProduct.ID != product.ID {
 		product.Slug = fmt.Sprintf("%s-%d", baseSlug, product.ID)
 	}
 
 	if err := transaction.Model(&product).Update("slug", product.Slug).Error; err != nil {
 		transaction.Rollback()
-		log.Printf("Error updating product slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error updating product slug", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to update product slug")
 	}
 
-	uploadedImages, err := uploadImages(c, formField, destination, allowedExtensions)
-	if err != nil {
+	uploadedImages, appErr := uploadImages(c, formField, destination, allowedExtensions)
+	if appErr != nil {
 		transaction.Rollback()
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return apperr.Render(c, appErr)
 	}
 
 	for _, img := range uploadedImages {
@@ -204,6 +233,8 @@ func (h *Handlers) CreateProduct(c echo.Context) error {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to commit transaction")
 	}
 
+	h.recordAudit(c, "product.create", models.AuditEntityProduct, product.ID, nil, product)
+
 	return jsonResponse(c, http.StatusCreated, "Product created successfully", map[string]interface{}{
 		"product":        product,
 		"product_images": uploadedImages,
@@ -225,20 +256,20 @@ func (h *Handlers) DeleteProduct(c echo.Context) error {
 
 	id, err := convertToInt64(c.Param("id"))
 	if err != nil {
-		log.Printf("Error converting product ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error converting product ID", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
 	}
 
-	product, err := repositories.GetProductByID(id, h.DB)
+	product, err := repositories.GetProductByID(id, h.db(c))
 	if err != nil {
-		log.Printf("Error fetching product by ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching product by ID", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
-	transaction := h.DB.Begin()
+	transaction := h.db(c).Begin()
 
 	if err := transaction.Where("product_id = ?", product.ID).Find(&productReviews).Error; err != nil {
-		log.Printf("Error fetching product reviews: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching product reviews", "error", err)
 		transaction.Rollback()
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching product reviews")
 	}
@@ -246,7 +277,7 @@ func (h *Handlers) DeleteProduct(c echo.Context) error {
 	// Delete each review
 	for _, review := range productReviews {
 		if err := transaction.Delete(&review).Error; err != nil {
-			log.Printf("Error deleting product review: %v", err)
+			slog.ErrorContext(c.Request().Context(), "Error deleting product review", "error", err)
 			transaction.Rollback()
 			return jsonResponse(c, http.StatusInternalServerError, "Error deleting product reviews")
 		}
@@ -259,7 +290,6 @@ func (h *Handlers) DeleteProduct(c echo.Context) error {
 	}
 
 	for _, productImage := range productImages {
-		fmt.Println("productImage", productImage)
 		if err := deleteImage("public/images/product_images/" + productImage.Image); err != nil {
 			transaction.Rollback()
 			return jsonResponse(c, http.StatusInternalServerError, "Error deleting product images")
@@ -277,6 +307,8 @@ func (h *Handlers) DeleteProduct(c echo.Context) error {
 		return jsonResponse(c, http.StatusInternalServerError, "Error finalizing product deletion")
 	}
 
+	h.recordAudit(c, "product.delete", models.AuditEntityProduct, product.ID, product, nil)
+
 	return jsonResponse(c, http.StatusOK, "Product deleted successfully", product)
 }
 
@@ -292,36 +324,40 @@ func (h *Handlers) UpdateProduct(c echo.Context) error {
 
 	id, err := convertToInt64(c.Param("id"))
 	if err != nil {
-		log.Printf("Error converting product ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error converting product ID", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
 	}
 
-	product, err = repositories.GetProductByID(id, h.DB)
+	product, err = repositories.GetProductByID(id, h.db(c))
 	if err != nil {
-		log.Printf("Error fetching product by ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching product by ID", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
+	before := product
+
 	if err := c.Bind(&product); err != nil {
-		log.Printf("Error binding product data: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error binding product data", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid input for updating product")
 	}
 
 	var existingProduct models.Product
-	existingProduct, err = repositories.GetProductBySlug(product.Slug, h.DB)
+	existingProduct, err = repositories.GetProductBySlug(product.Slug, h.db(c))
 	if err == nil && existingProduct.ID != product.ID {
 		return jsonResponse(c, http.StatusBadRequest, "Product already exists with the same slug")
 	}
 
 	if err := product.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
-	if err := h.DB.Save(&product).Error; err != nil {
-		log.Printf("Error updating product: %v", err)
+	if err := h.db(c).Save(&product).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error updating product", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating product")
 	}
 
+	h.recordAudit(c, "product.update", models.AuditEntityProduct, product.ID, before, product)
+
 	return jsonResponse(c, http.StatusOK, "Product updated successfully", product)
 }
 
@@ -333,24 +369,28 @@ func (h *Handlers) UpdateProduct(c echo.Context) error {
 func (h *Handlers) SearchProducts(c echo.Context) error {
 	query := c.Param("query")
 
-	config := config.Initialize()
+	config := config.Get()
 
 	page, perPage, offset := getPaginationParams(c)
-	order := getSortOrder(c)
+	order := productSortOrder(c)
 
 	var products []models.Product
-	if err := h.DB.Preload("Category").Preload("Category.Parent").Preload("ProductImages").Order(order).Where("name LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%").Limit(perPage).Offset(offset).Find(&products).Error; err != nil {
-		log.Printf("Error searching for products: %v", err)
+	if err := h.db(c).Preload("Category").Preload("Category.Parent").Preload("ProductImages").Order(order).Where("name LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%").Limit(perPage).Offset(offset).Find(&products).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error searching for products", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error searching for products")
 	}
 
 	var total int64
-	if err := h.DB.Model(&models.Product{}).Where("name LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%").Count(&total).Error; err != nil {
-		log.Printf("Error counting products: %v", err)
+	if err := h.db(c).Model(&models.Product{}).Where("name LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%").Count(&total).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error counting products", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error counting products")
 	}
 
 	repositories.SetProductImageURLs(products, config.SERVER_URL)
+	h.localizeProducts(c, products)
+	if err := h.priceProducts(c, products); err != nil {
+		return apperr.Render(c, err)
+	}
 
 	return jsonResponse(c, http.StatusOK, "Products fetched successfully", map[string]interface{}{
 		"products": wrapData(products),
@@ -389,9 +429,9 @@ func (h *Handlers) GetProductImage(c echo.Context) error {
 
 func (h *Handlers) UploadProductImages(c echo.Context) error {
 	slug := c.Param("slug")
-	product, err := repositories.GetProductBySlug(slug, h.DB)
+	product, err := repositories.GetProductBySlug(slug, h.db(c))
 	if err != nil {
-		log.Printf("Error fetching product by slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching product by slug", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
@@ -399,10 +439,9 @@ func (h *Handlers) UploadProductImages(c echo.Context) error {
 	destination := "public/images/product_images"
 	allowedExtensions := []string{".jpg", ".jpeg", ".png", ".webp"}
 
-	uploadedImages, err := uploadImages(c, formField, destination, allowedExtensions)
-	if err != nil {
-		log.Printf("Error uploading images: %v", err)
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+	uploadedImages, appErr := uploadImages(c, formField, destination, allowedExtensions)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
 	}
 
 	for _, img := range uploadedImages {
@@ -411,10 +450,12 @@ func (h *Handlers) UploadProductImages(c echo.Context) error {
 			Image:     img["filename"].(string),
 		}
 
-		if err := h.DB.Create(&productImage).Error; err != nil {
-			log.Printf("Error saving image to database: %v", err)
+		if err := h.db(c).Create(&productImage).Error; err != nil {
+			slog.ErrorContext(c.Request().Context(), "Error saving image to database", "error", err)
 			return jsonResponse(c, http.StatusInternalServerError, "Failed to save image to database")
 		}
+
+		h.recordAudit(c, "product_image.create", models.AuditEntityProductImage, productImage.ID, nil, productImage)
 	}
 
 	return jsonResponse(c, http.StatusOK, "Images uploaded successfully", map[string]interface{}{
@@ -429,22 +470,29 @@ func (h *Handlers) UploadProductImages(c echo.Context) error {
 // 3. Returns status 404 if the image is not found.
 // 4. Returns status 500 if an error occurs.
 func (h *Handlers) DeleteProductImage(c echo.Context) error {
-	product, err := repositories.GetProductBySlug(c.Param("slug"), h.DB)
+	product, err := repositories.GetProductBySlug(c.Param("slug"), h.db(c))
 	if err != nil {
-		log.Printf("Error fetching product by slug: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error fetching product by slug", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "Product not found")
 	}
 
 	var productImage models.ProductImage
-	if err := h.DB.Where("product_id = ? AND id = ?", product.ID, c.Param("id")).First(&productImage).Error; err != nil {
-		log.Printf("Error fetching product image: %v", err)
+	if err := h.db(c).Where("product_id = ? AND id = ?", product.ID, c.Param("id")).First(&productImage).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching product image", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "Image not found for the product")
 	}
 
 	if err := deleteImage("public/images/product_images/" + productImage.Image); err != nil {
-		log.Printf("Error deleting image: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error deleting image", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Error deleting image")
 	}
 
+	if err := h.db(c).Delete(&productImage).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting image record", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting image")
+	}
+
+	h.recordAudit(c, "product_image.delete", models.AuditEntityProductImage, productImage.ID, productImage, nil)
+
 	return jsonResponse(c, http.StatusOK, "Image deleted successfully")
 }
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/apperr"
+	"keylab/database/models"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const invalidQuestionStatus = "Invalid status, use published or hidden"
+
+// questionFilter reads the question moderation queue's status, product (a slug), user_id and
+// unanswered query parameters.
+func (h *Handlers) questionFilter(c echo.Context) (repositories.QuestionFilter, *apperr.Error) {
+	var filter repositories.QuestionFilter
+
+	if value := c.QueryParam("status"); value != "" {
+		status, ok := models.ParseQuestionStatus(value)
+		if !ok {
+			return filter, apperr.BadRequest(invalidQuestionStatus)
+		}
+		filter.Status = status
+	}
+
+	if slug := c.QueryParam("product"); slug != "" {
+		product, err := repositories.GetProductBySlug(slug, h.db(c))
+		if err != nil {
+			return filter, apperr.NotFound("Product not found")
+		}
+		filter.ProductID = product.ID
+	}
+
+	if value := c.QueryParam("user_id"); value != "" {
+		userID, err := convertToInt64(value)
+		if err != nil {
+			return filter, apperr.BadRequest("Invalid user ID")
+		}
+		filter.UserID = userID
+	}
+
+	if value := c.QueryParam("unanswered"); value != "" {
+		unanswered, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, apperr.BadRequest("Invalid unanswered filter, use true or false")
+		}
+		filter.Unanswered = unanswered
+	}
+
+	return filter, nil
+}
+
+// Get Questions For Moderation Handler [GET /admin/questions]
+// 1. Lists questions in every status with all of their answers, newest first, filtered by status,
+// product slug, user_id and unanswered=true for questions without a published answer.
+// 2. Returns status 200 with the page of questions and the pagination metadata.
+func (h *Handlers) GetModerationQuestions(c echo.Context) error {
+	filter, appErr := h.questionFilter(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	page, perPage, offset := getPaginationParams(c)
+
+	questions, total, err := repositories.GetModerationQuestions(filter, perPage, offset, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching questions for moderation", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching questions", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, "Questions fetched successfully", map[string]interface{}{
+		"questions": questions,
+		"metadata":  generatePaginationResponse(page, perPage, int(total)),
+	})
+}
+
+// moderatedQuestion loads the question named by the id path parameter in any status.
+func (h *Handlers) moderatedQuestion(c echo.Context) (models.ProductQuestion, *apperr.Error) {
+	questionID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return models.ProductQuestion{}, apperr.BadRequest("Invalid question ID")
+	}
+
+	question, err := repositories.GetQuestionByID(questionID, h.db(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return question, apperr.NotFound("Question not found")
+	}
+	if err != nil {
+		return question, apperr.Internal("Error fetching question", err)
+	}
+
+	return question, nil
+}
+
+// moderatedAnswer loads the answer named by the id path parameter in any status.
+func (h *Handlers) moderatedAnswer(c echo.Context) (models.ProductAnswer, *apperr.Error) {
+	answerID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return models.ProductAnswer{}, apperr.BadRequest("Invalid answer ID")
+	}
+
+	answer, err := repositories.GetAnswerByID(answerID, h.db(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return answer, apperr.NotFound("Answer not found")
+	}
+	if err != nil {
+		return answer, apperr.Internal("Error fetching answer", err)
+	}
+
+	return answer, nil
+}
+
+// Get Question For Moderation Handler [GET /admin/questions/:id]
+// 1. Returns the question in any status with all of its answers.
+func (h *Handlers) GetModerationQuestion(c echo.Context) error {
+	question, appErr := h.moderatedQuestion(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Question found", question)
+}
+
+type moderateQuestionRequest struct {
+	Status models.QuestionStatus `json:"status"`
+}
+
+// bindQuestionStatus reads the status of a moderation request.
+func bindQuestionStatus(c echo.Context) (models.QuestionStatus, *apperr.Error) {
+	var req moderateQuestionRequest
+	if err := c.Bind(&req); err != nil {
+		return "", apperr.BadRequest("Invalid request body")
+	}
+
+	status, ok := models.ParseQuestionStatus(string(req.Status))
+	if !ok {
+		return "", apperr.BadRequest(invalidQuestionStatus)
+	}
+
+	return status, nil
+}
+
+// Moderate Question Handler [PUT /admin/questions/:id/status]
+// 1. Sets the question's status to published or hidden. Only published questions are shown to
+// shoppers, hiding a question hides its answers with it.
+// 2. Records the change in the audit log.
+func (h *Handlers) ModerateQuestion(c echo.Context) error {
+	question, appErr := h.moderatedQuestion(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	status, appErr := bindQuestionStatus(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	before := question
+	question.Status = status
+	if err := h.db(c).Model(&question).UpdateColumn("status", question.Status).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error moderating question", "error", err)
+		return apperr.Render(c, apperr.Internal("Error moderating question", err))
+	}
+
+	h.recordAudit(c, "question.moderate", models.AuditEntityQuestion, question.ID, before, question)
+
+	return jsonResponse(c, http.StatusOK, "Question moderated successfully", question)
+}
+
+// Delete Question Handler [DELETE /admin/questions/:id]
+// 1. Deletes the question with its answers and their votes.
+// 2. Records the deletion in the audit log.
+func (h *Handlers) DeleteQuestion(c echo.Context) error {
+	question, appErr := h.moderatedQuestion(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := h.db(c).Delete(&models.ProductQuestion{}, question.ID).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting question", "error", err)
+		return apperr.Render(c, apperr.Internal("Error deleting question", err))
+	}
+
+	h.recordAudit(c, "question.delete", models.AuditEntityQuestion, question.ID, question, nil)
+
+	return jsonResponse(c, http.StatusOK, "Question deleted successfully")
+}
+
+// Moderate Answer Handler [PUT /admin/answers/:id/status]
+// 1. Sets the answer's status to published or hidden. Only published answers are shown to
+// shoppers.
+// 2. Records the change in the audit log.
+func (h *Handlers) ModerateAnswer(c echo.Context) error {
+	answer, appErr := h.moderatedAnswer(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	status, appErr := bindQuestionStatus(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	before := answer
+	answer.Status = status
+	if err := h.db(c).Model(&answer).UpdateColumn("status", answer.Status).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error moderating answer", "error", err)
+		return apperr.Render(c, apperr.Internal("Error moderating answer", err))
+	}
+
+	h.recordAudit(c, "answer.moderate", models.AuditEntityAnswer, answer.ID, before, answer)
+
+	return jsonResponse(c, http.StatusOK, "Answer moderated successfully", answer)
+}
+
+type officialAnswerRequest struct {
+	Official *bool `json:"official"`
+}
+
+// Mark Answer Official Handler [PUT /admin/answers/:id/official]
+// 1. Marks the answer as the shop's official answer, or unmarks it. Official answers are listed
+// before the others.
+// 2. Records the change in the audit log.
+// 3. Returns status 400 if official is missing.
+func (h *Handlers) MarkAnswerOfficial(c echo.Context) error {
+	answer, appErr := h.moderatedAnswer(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	var req officialAnswerRequest
+	if err := c.Bind(&req); err != nil || req.Official == nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid request body, official must be true or false"))
+	}
+
+	before := answer
+	answer.Official = *req.Official
+	if err := h.db(c).Model(&answer).UpdateColumn("official", answer.Official).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error updating answer", "error", err)
+		return apperr.Render(c, apperr.Internal("Error updating answer", err))
+	}
+
+	h.recordAudit(c, "answer.official", models.AuditEntityAnswer, answer.ID, before, answer)
+
+	return jsonResponse(c, http.StatusOK, "Answer updated successfully", answer)
+}
+
+// Delete Answer Handler [DELETE /admin/answers/:id]
+// 1. Deletes the answer with its votes.
+// 2. Records the deletion in the audit log.
+func (h *Handlers) DeleteAnswer(c echo.Context) error {
+	answer, appErr := h.moderatedAnswer(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := h.db(c).Delete(&models.ProductAnswer{}, answer.ID).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting answer", "error", err)
+		return apperr.Render(c, apperr.Internal("Error deleting answer", err))
+	}
+
+	h.recordAudit(c, "answer.delete", models.AuditEntityAnswer, answer.ID, answer, nil)
+
+	return jsonResponse(c, http.StatusOK, "Answer deleted successfully")
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultRecommendationLimit = 8
+
+// Get Product Recommendations Handler [GET /products/:slug/recommendations]
+// 1. Returns up to limit (8 by default) products in stock that are often bought with the product,
+// the most often first, as last computed by the recommendations job.
+// 2. Tops them up with the best rated products of the same category when there are not enough.
+// 3. Returns status 400 for an invalid limit and 404 if there is no such product.
+func (h *Handlers) GetProductRecommendations(c echo.Context) error {
+	limit := defaultRecommendationLimit
+	if value := c.QueryParam("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > repositories.MaxRecommendations {
+			return apperr.Render(c, apperr.BadRequest("Invalid limit"))
+		}
+		limit = parsed
+	}
+
+	product, err := repositories.GetProductBySlug(c.Param("slug"), h.db(c))
+	if err != nil {
+		return apperr.Render(c, apperr.NotFound("Product not found"))
+	}
+
+	products, err := repositories.GetRecommendations(product, limit, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching recommendations", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching recommendations", err))
+	}
+
+	repositories.SetProductImageURLs(products, config.Get().SERVER_URL)
+	h.localizeProducts(c, products)
+	if err := h.priceProducts(c, products); err != nil {
+		return apperr.Render(c, err)
+	}
+
+	return jsonResponse(c, http.StatusOK, "Recommendations fetched successfully", products)
+}
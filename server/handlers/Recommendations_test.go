@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/money"
+	"keylab/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRecommendations(t *testing.T) {
+	h, testDB, user, keyboard := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	accessories := models.ProductCategory{Name: "Accessories", Slug: "accessories"}
+	assert.NoError(t, testDB.DB.Create(&accessories).Error)
+
+	newProduct := func(slug string, categoryID int64, stock int) models.Product {
+		p := models.Product{Name: slug, Slug: slug, Description: slug, Price: money.MustParse("5.00"), Stock: stock, CategoryID: categoryID}
+		assert.NoError(t, testDB.DB.Create(&p).Error)
+		return p
+	}
+	switches := newProduct("switches", accessories.ID, 10)
+	keycaps := newProduct("keycaps", accessories.ID, 10)
+	soldOut := newProduct("sold-out-cable", accessories.ID, 0)
+	newProduct("other-keyboard", keyboard.CategoryID, 3)
+
+	address := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "X", Type: models.Shipping}
+	assert.NoError(t, testDB.DB.Create(&address).Error)
+	order := func(status models.OrderStatus, products ...models.Product) {
+		o := models.Order{UserID: user.ID, Status: status, Total: keyboard.Price, ShippingAddressID: address.ID, BillingAddressID: address.ID}
+		assert.NoError(t, testDB.DB.Create(&o).Error)
+		for _, p := range products {
+			assert.NoError(t, testDB.DB.Create(&models.OrderedItem{OrderID: o.ID, ProductID: p.ID, Quantity: 1, Price: p.Price}).Error)
+		}
+	}
+	order(models.Delivered, keyboard, switches, keycaps, soldOut)
+	order(models.Shipped, keyboard, switches)
+	order(models.Cancelled, keyboard, keycaps)
+	order(models.Cancelled, keyboard, keycaps)
+
+	lastYear := time.Now().AddDate(-1, 0, 0)
+	assert.NoError(t, repositories.RefreshRecommendations(lastYear, testDB.DB))
+
+	recommended := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("slug")
+		c.SetParamValues(keyboard.Slug)
+
+		assert.NoError(t, h.GetProductRecommendations(c))
+		if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+			return nil
+		}
+
+		var response struct {
+			Data []models.Product `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		slugs := []string{}
+		for _, p := range response.Data {
+			slugs = append(slugs, p.Slug)
+		}
+		return slugs
+	}
+
+	t.Run("Bought Together", func(t *testing.T) {
+		assert.Equal(t, []string{"switches"}, recommended("limit=1"), "the most often bought together first, cancelled orders left out")
+	})
+
+	t.Run("Category Fallback", func(t *testing.T) {
+		assert.Equal(t, []string{"switches", "keycaps", "other-keyboard"}, recommended(""), "only products in stock, never the product itself")
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		assert.NoError(t, testDB.DB.Model(&switches).UpdateColumn("stock", 0).Error)
+		assert.NoError(t, repositories.RefreshRecommendations(lastYear, testDB.DB))
+
+		var rows []models.ProductRecommendation
+		assert.NoError(t, testDB.DB.Where("product_id = ?", keyboard.ID).Find(&rows).Error)
+		assert.Equal(t, []models.ProductRecommendation{{ProductID: keyboard.ID, RecommendedID: keycaps.ID, Score: 1}}, rows)
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		assert.NoError(t, repositories.RefreshRecommendations(time.Now().Add(time.Hour), testDB.DB))
+
+		var count int64
+		assert.NoError(t, testDB.DB.Model(&models.ProductRecommendation{}).Count(&count).Error)
+		assert.Zero(t, count, "every order is older than the window")
+	})
+
+	t.Run("Invalid Limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?limit=100", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("slug")
+		c.SetParamValues(keyboard.Slug)
+
+		assert.NoError(t, h.GetProductRecommendations(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
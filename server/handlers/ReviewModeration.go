@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/database/models"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// reviewFilter reads the moderation queue's status, product (a slug), user_id, reported and
+// verified query parameters.
+func (h *Handlers) reviewFilter(c echo.Context) (repositories.ReviewFilter, *apperr.Error) {
+	var filter repositories.ReviewFilter
+
+	if value := c.QueryParam("status"); value != "" {
+		status, ok := models.ParseReviewStatus(value)
+		if !ok {
+			return filter, apperr.BadRequest("Invalid status, use pending, approved, rejected or hidden")
+		}
+		filter.Status = status
+	}
+
+	if slug := c.QueryParam("product"); slug != "" {
+		product, err := repositories.GetProductBySlug(slug, h.db(c))
+		if err != nil {
+			return filter, apperr.NotFound("Product not found")
+		}
+		filter.ProductID = product.ID
+	}
+
+	if value := c.QueryParam("user_id"); value != "" {
+		userID, err := convertToInt64(value)
+		if err != nil {
+			return filter, apperr.BadRequest("Invalid user ID")
+		}
+		filter.UserID = userID
+	}
+
+	if value := c.QueryParam("reported"); value != "" {
+		reported, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, apperr.BadRequest("Invalid reported filter, use true or false")
+		}
+		filter.Reported = reported
+	}
+
+	verified, appErr := verifiedFilter(c)
+	if appErr != nil {
+		return filter, appErr
+	}
+	filter.Verified = verified
+
+	return filter, nil
+}
+
+// Get Reviews For Moderation Handler [GET /admin/reviews]
+// 1. Lists reviews in every status, newest first, filtered by status, product slug, user_id,
+// verified purchase and reported=true for reviews with open reports, which are listed the most
+// reported first.
+// 2. Returns status 200 with the page of reviews and the pagination metadata.
+func (h *Handlers) GetModerationReviews(c echo.Context) error {
+	filter, appErr := h.reviewFilter(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	page, perPage, offset := getPaginationParams(c)
+
+	reviews, total, err := repositories.GetModerationReviews(filter, perPage, offset, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching reviews for moderation", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching reviews", err))
+	}
+
+	repositories.SetReviewPhotoURLs(reviews, config.Get().SERVER_URL)
+	return jsonResponse(c, http.StatusOK, "Reviews fetched successfully", map[string]interface{}{
+		"reviews":  reviews,
+		"metadata": generatePaginationResponse(page, perPage, int(total)),
+	})
+}
+
+// moderatedReview loads the review named by the id path parameter in any status.
+func (h *Handlers) moderatedReview(c echo.Context) (models.ProductReviews, *apperr.Error) {
+	reviewID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return models.ProductReviews{}, apperr.BadRequest("Invalid review ID")
+	}
+
+	review, err := repositories.GetReviewByID(reviewID, h.db(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return review, apperr.NotFound("Review not found")
+	}
+	if err != nil {
+		return review, apperr.Internal("Error fetching review", err)
+	}
+
+	return review, nil
+}
+
+// Get Review For Moderation Handler [GET /admin/reviews/:id]
+// 1. Returns the review in any status with the reports made about it, newest first.
+func (h *Handlers) GetModerationReview(c echo.Context) error {
+	review, appErr := h.moderatedReview(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	reports, err := repositories.GetReviewReports(review.ID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching review reports", "error", err)
+		return apperr.Render(c, apperr.Internal("Error fetching review", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, "Review found", map[string]interface{}{
+		"review":  reviewWithPhotoURLs(review),
+		"reports": reports,
+	})
+}
+
+type moderateReviewRequest struct {
+	Status models.ReviewStatus `json:"status"`
+}
+
+// Moderate Review Handler [PUT /admin/reviews/:id/status]
+// 1. Sets the review's status to pending, approved, rejected or hidden. Only approved reviews are
+// shown to shoppers.
+// 2. Clears the review's open reports, which stay listed on the review, and updates the product's
+// rating.
+// 3. Records the change in the audit log.
+func (h *Handlers) ModerateReview(c echo.Context) error {
+	review, appErr := h.moderatedReview(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	var req moderateReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid request body"))
+	}
+
+	status, ok := models.ParseReviewStatus(string(req.Status))
+	if !ok {
+		return apperr.Render(c, apperr.BadRequest("Invalid status, use pending, approved, rejected or hidden"))
+	}
+
+	before := review
+	now := time.Now()
+	review.Status = status
+	review.ReportCount = 0
+	review.ModeratedAt = &now
+
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&review).UpdateColumns(map[string]interface{}{
+			"status":       review.Status,
+			"report_count": review.ReportCount,
+			"moderated_at": review.ModeratedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return repositories.RefreshProductRating(review.ProductID, tx)
+	})
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error moderating review", "error", err)
+		return apperr.Render(c, apperr.Internal("Error moderating review", err))
+	}
+
+	h.recordAudit(c, "review.moderate", models.AuditEntityReview, review.ID, before, review)
+
+	return jsonResponse(c, http.StatusOK, "Review moderated successfully", reviewWithPhotoURLs(review))
+}
+
+// Report Review Handler [POST /products/:product_slug/reviews/:id/report]
+// 1. Flags an approved review of the product for moderators with a reason (spam, abuse, off_topic
+// or other) and an optional comment.
+// 2. Returns status 201 when the report is saved.
+// 3. Returns status 400 for invalid input or the user's own review, 404 if the review is not shown
+// on the product, and 409 if the user has already reported it.
+func (h *Handlers) ReportReview(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	review, appErr := h.shownReview(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if review.UserID == user.ID {
+		return apperr.Render(c, apperr.BadRequest("You cannot report your own review"))
+	}
+
+	var report models.ReviewReport
+	if err := c.Bind(&report); err != nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid request body"))
+	}
+	report.ID = 0
+	report.ReviewID = review.ID
+	report.UserID = user.ID
+
+	if err := report.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	var existing int64
+	if err := h.db(c).Model(&models.ReviewReport{}).Where("review_id = ? AND user_id = ?", review.ID, user.ID).Count(&existing).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error checking review reports", "error", err)
+		return apperr.Render(c, apperr.Internal("Error reporting review", err))
+	}
+	if existing > 0 {
+		return apperr.Render(c, apperr.Conflict("You have already reported this review"))
+	}
+
+	if err := repositories.CreateReviewReport(&report, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error reporting review", "error", err)
+		return apperr.Render(c, apperr.Internal("Error reporting review", err))
+	}
+
+	return jsonResponse(c, http.StatusCreated, "Review reported", report)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"keylab/config"
+	db "keylab/database"
+	"keylab/database/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewModeration(t *testing.T) {
+	h, testDB, user, product := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	reporter := models.User{Forename: "Jane", Surname: "Roe", Email: "jane.roe@example.com", Password: "hashedpassword", PhoneNumber: "+1234567891"}
+	assert.NoError(t, testDB.DB.Create(&reporter).Error)
+
+	cfg := config.Get()
+	cfg.REVIEW_PREMODERATION = true
+	defer func() { cfg.REVIEW_PREMODERATION = false }()
+
+	call := func(handler echo.HandlerFunc, method, query string, body interface{}, as *models.User, params ...string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, "/?"+query, bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if as != nil {
+			c.Set("user", *as)
+		}
+		var names, values []string
+		for i := 0; i < len(params); i += 2 {
+			names, values = append(names, params[i]), append(values, params[i+1])
+		}
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+
+		assert.NoError(t, handler(c))
+		return rec
+	}
+
+	var review models.ProductReviews
+	reviewID := func() string { return strconv.FormatInt(review.ID, 10) }
+
+	t.Run("Pre-moderation Holds New Reviews", func(t *testing.T) {
+		rec := call(h.CreateReview, http.MethodPost, "", map[string]interface{}{"rating": 5, "comment": "Great", "status": "approved"}, &user, "product_slug", product.Slug)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		assert.NoError(t, testDB.DB.Where("user_id = ?", user.ID).First(&review).Error)
+		assert.Equal(t, models.ReviewPending, review.Status, "users cannot approve their own review")
+
+		assert.Equal(t, http.StatusNotFound, call(h.GetReviewsByProduct, http.MethodGet, "", nil, nil, "product_slug", product.Slug).Code)
+		assert.Equal(t, http.StatusNotFound, call(h.GetReviewStatistics, http.MethodGet, "", nil, nil, "product_slug", product.Slug).Code)
+		assert.Equal(t, http.StatusNotFound, call(h.GetReview, http.MethodGet, "", nil, nil, "id", reviewID(), "product_slug", product.Slug).Code)
+	})
+
+	t.Run("Queue", func(t *testing.T) {
+		rec := call(h.GetModerationReviews, http.MethodGet, "status=pending&product="+product.Slug, nil, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data struct {
+				Reviews []models.ProductReviews `json:"reviews"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response.Data.Reviews, 1)
+
+		assert.Equal(t, http.StatusBadRequest, call(h.GetModerationReviews, http.MethodGet, "status=spam", nil, nil).Code)
+	})
+
+	t.Run("Approve", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, call(h.ModerateReview, http.MethodPut, "", map[string]string{"status": "deleted"}, nil, "id", reviewID()).Code)
+		assert.Equal(t, http.StatusOK, call(h.ModerateReview, http.MethodPut, "", map[string]string{"status": "approved"}, nil, "id", reviewID()).Code)
+
+		assert.Equal(t, http.StatusOK, call(h.GetReviewsByProduct, http.MethodGet, "", nil, nil, "product_slug", product.Slug).Code)
+		assert.Equal(t, http.StatusOK, call(h.GetReviewStatistics, http.MethodGet, "", nil, nil, "product_slug", product.Slug).Code)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		report := func(as models.User, body interface{}) int {
+			return call(h.ReportReview, http.MethodPost, "", body, &as, "product_slug", product.Slug, "id", reviewID()).Code
+		}
+
+		assert.Equal(t, http.StatusBadRequest, report(reporter, map[string]string{"reason": "boring"}))
+		assert.Equal(t, http.StatusBadRequest, report(user, map[string]string{"reason": "spam"}), "own review")
+		assert.Equal(t, http.StatusCreated, report(reporter, map[string]string{"reason": "spam", "comment": "Link to another shop"}))
+		assert.Equal(t, http.StatusConflict, report(reporter, map[string]string{"reason": "abuse"}))
+
+		rec := call(h.GetModerationReview, http.MethodGet, "", nil, nil, "id", reviewID())
+		var response struct {
+			Data struct {
+				Review  models.ProductReviews `json:"review"`
+				Reports []models.ReviewReport `json:"reports"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Data.Review.ReportCount)
+		if assert.Len(t, response.Data.Reports, 1) {
+			assert.Equal(t, models.ReportSpam, response.Data.Reports[0].Reason)
+		}
+	})
+
+	t.Run("Hide", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(h.ModerateReview, http.MethodPut, "", map[string]string{"status": "hidden"}, nil, "id", reviewID()).Code)
+
+		assert.NoError(t, testDB.DB.First(&review, review.ID).Error)
+		assert.Equal(t, models.ReviewHidden, review.Status)
+		assert.Zero(t, review.ReportCount, "moderating clears the reports")
+		assert.NotNil(t, review.ModeratedAt)
+
+		assert.Equal(t, http.StatusNotFound, call(h.GetReview, http.MethodGet, "", nil, nil, "id", reviewID(), "product_slug", product.Slug).Code)
+		assert.Equal(t, http.StatusNotFound, call(h.ReportReview, http.MethodPost, "", map[string]string{"reason": "spam"}, &reporter, "product_slug", product.Slug, "id", reviewID()).Code)
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"keylab/apperr"
+	"keylab/config"
+	"keylab/database/models"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	reviewPhotoField = "photos"
+	reviewPhotoDir   = "public/images/review_photos"
+	maxReviewPhotos  = 5
+)
+
+// uploadReviewPhotos saves the photos uploaded in the photos field of a multipart request, as long
+// as the review ends up with no more than maxReviewPhotos besides the existing ones. Requests
+// without photos, including JSON ones, upload none.
+func uploadReviewPhotos(c echo.Context, existing int) ([]models.ReviewPhoto, *apperr.Error) {
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File[reviewPhotoField]) == 0 {
+		return nil, nil
+	}
+
+	if existing+len(form.File[reviewPhotoField]) > maxReviewPhotos {
+		return nil, apperr.BadRequest("A review can have at most 5 photos")
+	}
+
+	uploaded, appErr := uploadImages(c, reviewPhotoField, reviewPhotoDir, productImageExtensions)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	photos := make([]models.ReviewPhoto, len(uploaded))
+	for i, file := range uploaded {
+		photos[i] = models.ReviewPhoto{Image: file["filename"].(string)}
+	}
+
+	return photos, nil
+}
+
+// deleteReviewPhotoFiles removes the photos' files from the server.
+func deleteReviewPhotoFiles(photos []models.ReviewPhoto) {
+	for _, photo := range photos {
+		deleteImage(filepath.Join(reviewPhotoDir, photo.Image))
+	}
+}
+
+// reviewWithPhotoURLs returns the review with the URLs of its photos set.
+func reviewWithPhotoURLs(review models.ProductReviews) models.ProductReviews {
+	reviews := []models.ProductReviews{review}
+	repositories.SetReviewPhotoURLs(reviews, config.Get().SERVER_URL)
+
+	return reviews[0]
+}
+
+// Get Review Photo Handler [GET /reviews/photos/:filename]
+// 1. Serves the review photo with the filename.
+// 2. Returns status 404 if there is no such photo.
+func (h *Handlers) GetReviewPhoto(c echo.Context) error {
+	path := filepath.Join(reviewPhotoDir, filepath.Base(c.Param("filename")))
+	if _, err := os.Stat(path); err != nil {
+		return apperr.Render(c, apperr.NotFound("Image not found"))
+	}
+
+	return c.File(path)
+}
+
+// Delete Review Photo Handler [DELETE /products/:product_slug/reviews/:id/photos/:photo_id]
+// 1. Deletes a photo from the user's own review, from the database and the server.
+// 2. Returns status 200 with the review and its remaining photos.
+// 3. Returns status 403 if the review is not the user's and 404 if it has no such photo.
+func (h *Handlers) DeleteReviewPhoto(c echo.Context) error {
+	reviewID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid review ID"))
+	}
+
+	review, err := repositories.GetReviewByID(reviewID, h.db(c))
+	if err != nil {
+		return apperr.Render(c, apperr.NotFound("Review not found"))
+	}
+
+	user := c.Get("user").(models.User)
+	if review.UserID != user.ID {
+		return apperr.Render(c, apperr.Forbidden("You are not authorized to update this review"))
+	}
+
+	var photo models.ReviewPhoto
+	if err := h.db(c).Where("review_id = ? AND id = ?", review.ID, c.Param("photo_id")).First(&photo).Error; err != nil {
+		return apperr.Render(c, apperr.NotFound("Image not found"))
+	}
+
+	if err := h.db(c).Delete(&photo).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting review photo", "error", err)
+		return apperr.Render(c, apperr.Internal("Error deleting image", err))
+	}
+	deleteReviewPhotoFiles([]models.ReviewPhoto{photo})
+
+	photos := []models.ReviewPhoto{}
+	for _, kept := range review.Photos {
+		if kept.ID != photo.ID {
+			photos = append(photos, kept)
+		}
+	}
+	review.Photos = photos
+
+	return jsonResponse(c, http.StatusOK, "Image deleted successfully", reviewWithPhotoURLs(review))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	db "keylab/database"
+	"keylab/database/models"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewPhotos(t *testing.T) {
+	h, testDB, user, product := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	// Photos are saved relative to the working directory.
+	wd, _ := os.Getwd()
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	send := func(handler echo.HandlerFunc, method string, fields map[string]string, photos []string, params ...string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for name, value := range fields {
+			_ = writer.WriteField(name, value)
+		}
+		for _, name := range photos {
+			part, _ := writer.CreateFormFile("photos", name)
+			_, _ = part.Write([]byte("fake image content"))
+		}
+		writer.Close()
+
+		req := httptest.NewRequest(method, "/", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", user)
+		c.SetParamNames(append([]string{"product_slug"}, params[0:len(params)/2]...)...)
+		c.SetParamValues(append([]string{product.Slug}, params[len(params)/2:]...)...)
+
+		assert.NoError(t, handler(c))
+		return rec
+	}
+	stored := func() models.ProductReviews {
+		var review models.ProductReviews
+		assert.NoError(t, testDB.DB.Preload("Photos").Where("user_id = ?", user.ID).First(&review).Error)
+		return review
+	}
+	onDisk := func(photo models.ReviewPhoto) bool {
+		_, err := os.Stat(filepath.Join(reviewPhotoDir, photo.Image))
+		return err == nil
+	}
+
+	longComment := strings.Repeat("Lovely keyboard. ", 50)
+
+	t.Run("Create", func(t *testing.T) {
+		rec := send(h.CreateReview, http.MethodPost, map[string]string{"rating": "5", "title": "My build", "comment": longComment}, []string{"a.png", "b.jpg"})
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response struct {
+			Data models.ProductReviews `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "My build", response.Data.Title)
+		if assert.Len(t, response.Data.Photos, 2) {
+			assert.Contains(t, response.Data.Photos[0].URL, "/reviews/photos/"+response.Data.Photos[0].Image)
+		}
+
+		review := stored()
+		assert.Equal(t, longComment, review.Comment)
+		for _, photo := range review.Photos {
+			assert.True(t, onDisk(photo))
+		}
+	})
+
+	reviewID := func() string { return strconv.FormatInt(stored().ID, 10) }
+
+	t.Run("Update", func(t *testing.T) {
+		fields := map[string]string{"rating": "4", "comment": "Still good"}
+		rec := send(h.UpdateReview, http.MethodPut, fields, []string{"c.gif"}, "id", reviewID())
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"message":"File type not allowed"`)
+		assert.Equal(t, http.StatusBadRequest, send(h.UpdateReview, http.MethodPut, fields, []string{"c.png", "d.png", "e.png", "f.png"}, "id", reviewID()).Code, "more than 5 photos")
+
+		assert.Equal(t, http.StatusOK, send(h.UpdateReview, http.MethodPut, fields, []string{"c.png"}, "id", reviewID()).Code)
+		review := stored()
+		assert.Equal(t, "Still good", review.Comment)
+		assert.Len(t, review.Photos, 3)
+	})
+
+	t.Run("Delete Photo", func(t *testing.T) {
+		photo := stored().Photos[0]
+		assert.Equal(t, http.StatusOK, send(h.DeleteReviewPhoto, http.MethodDelete, nil, nil, "id", "photo_id", reviewID(), strconv.FormatInt(photo.ID, 10)).Code)
+		assert.Len(t, stored().Photos, 2)
+		assert.False(t, onDisk(photo))
+	})
+
+	t.Run("Delete Review", func(t *testing.T) {
+		photos := stored().Photos
+		assert.Equal(t, http.StatusOK, send(h.DeleteReview, http.MethodDelete, nil, nil, "id", reviewID()).Code)
+		for _, photo := range photos {
+			assert.False(t, onDisk(photo))
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"keylab/apperr"
+	"keylab/database/models"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// shownReview loads the review named by the id path parameter if it is approved and belongs to the
+// product named by the product_slug path parameter.
+func (h *Handlers) shownReview(c echo.Context) (models.ProductReviews, *apperr.Error) {
+	reviewID, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return models.ProductReviews{}, apperr.BadRequest("Invalid review ID")
+	}
+
+	product, err := repositories.GetProductBySlug(c.Param("product_slug"), h.db(c))
+	if err != nil {
+		return models.ProductReviews{}, apperr.NotFound("Product not found")
+	}
+
+	review, err := repositories.GetReviewByID(reviewID, h.db(c))
+	if err != nil || review.ProductID != product.ID || review.Status != models.ReviewApproved {
+		return models.ProductReviews{}, apperr.NotFound("Review not found")
+	}
+
+	return review, nil
+}
+
+type reviewVoteRequest struct {
+	Helpful *bool `json:"helpful"`
+}
+
+// Vote On Review Handler [PUT /products/:product_slug/reviews/:id/vote]
+// 1. Records whether the user found an approved review of the product helpful, replacing their
+// earlier vote on it.
+// 2. Returns status 200 with the review and its updated vote counts.
+// 3. Returns status 400 if helpful is missing or the review is the user's own, and 404 if the
+// review is not shown on the product.
+func (h *Handlers) VoteReview(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	review, appErr := h.shownReview(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if review.UserID == user.ID {
+		return apperr.Render(c, apperr.BadRequest("You cannot vote on your own review"))
+	}
+
+	var req reviewVoteRequest
+	if err := c.Bind(&req); err != nil || req.Helpful == nil {
+		return apperr.Render(c, apperr.BadRequest("Invalid request body, helpful must be true or false"))
+	}
+
+	vote := models.ReviewVote{ReviewID: review.ID, UserID: user.ID, Helpful: *req.Helpful}
+	if err := repositories.SaveReviewVote(&vote, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving review vote", "error", err)
+		return apperr.Render(c, apperr.Internal("Error saving vote", err))
+	}
+
+	return h.votedReview(c, review.ID, "Vote saved")
+}
+
+// Delete Review Vote Handler [DELETE /products/:product_slug/reviews/:id/vote]
+// 1. Withdraws the user's vote on an approved review of the product.
+// 2. Returns status 200 with the review and its updated vote counts.
+// 3. Returns status 404 if the review is not shown on the product or the user has not voted on it.
+func (h *Handlers) DeleteReviewVote(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	review, appErr := h.shownReview(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	deleted, err := repositories.DeleteReviewVote(review.ID, user.ID, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting review vote", "error", err)
+		return apperr.Render(c, apperr.Internal("Error deleting vote", err))
+	}
+	if !deleted {
+		return apperr.Render(c, apperr.NotFound("You have not voted on this review"))
+	}
+
+	return h.votedReview(c, review.ID, "Vote deleted")
+}
+
+// votedReview responds with the review as recounted after a vote.
+func (h *Handlers) votedReview(c echo.Context, reviewID int64, message string) error {
+	review, err := repositories.GetReviewByID(reviewID, h.db(c))
+	if err != nil {
+		return apperr.Render(c, apperr.Internal("Error fetching review", err))
+	}
+
+	return jsonResponse(c, http.StatusOK, message, reviewWithPhotoURLs(review))
+}
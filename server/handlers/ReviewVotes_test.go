@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	db "keylab/database"
+	"keylab/database/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewVotes(t *testing.T) {
+	h, testDB, user, product := setupReviewHandler(t)
+	defer db.CleanupTestDB(t, testDB)
+	e := echo.New()
+
+	newUser := func(email string, phone string) models.User {
+		u := models.User{Forename: "Jane", Surname: "Doe", Email: email, Password: "hashedpassword", PhoneNumber: phone}
+		assert.NoError(t, testDB.DB.Create(&u).Error)
+		return u
+	}
+	alice := newUser("alice@example.com", "+1234567891")
+	bob := newUser("bob@example.com", "+1234567892")
+
+	good := models.ProductReviews{ProductID: product.ID, UserID: user.ID, Rating: 5, Comment: "Great"}
+	poor := models.ProductReviews{ProductID: product.ID, UserID: alice.ID, Rating: 2, Comment: "Meh"}
+	assert.NoError(t, testDB.DB.Create(&good).Error)
+	assert.NoError(t, testDB.DB.Create(&poor).Error)
+
+	call := func(handler echo.HandlerFunc, method string, query string, body interface{}, as *models.User, reviewID int64) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, "/?"+query, bytes.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if as != nil {
+			c.Set("user", *as)
+		}
+		c.SetParamNames("product_slug", "id")
+		c.SetParamValues(product.Slug, strconv.FormatInt(reviewID, 10))
+
+		assert.NoError(t, handler(c))
+		return rec
+	}
+	vote := func(as models.User, review models.ProductReviews, helpful interface{}) *httptest.ResponseRecorder {
+		return call(h.VoteReview, http.MethodPut, "", map[string]interface{}{"helpful": helpful}, &as, review.ID)
+	}
+	counts := func(review models.ProductReviews) [2]int {
+		assert.NoError(t, testDB.DB.First(&review, review.ID).Error)
+		return [2]int{review.HelpfulCount, review.NotHelpfulCount}
+	}
+	list := func(query string) ([]int64, map[string]int) {
+		rec := call(h.GetReviewsByProduct, http.MethodGet, query, nil, nil, 0)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return nil, nil
+		}
+
+		var response struct {
+			Data struct {
+				Reviews  []models.ProductReviews `json:"reviews"`
+				Metadata map[string]int          `json:"metadata"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		ids := []int64{}
+		for _, review := range response.Data.Reviews {
+			ids = append(ids, review.ID)
+		}
+		return ids, response.Data.Metadata
+	}
+
+	t.Run("Vote", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, vote(alice, good, true).Code)
+		assert.Equal(t, http.StatusOK, vote(bob, good, false).Code)
+		assert.Equal(t, [2]int{1, 1}, counts(good))
+
+		assert.Equal(t, http.StatusOK, vote(bob, good, true).Code, "a vote can be changed")
+		assert.Equal(t, [2]int{2, 0}, counts(good))
+
+		assert.Equal(t, http.StatusOK, vote(bob, poor, false).Code)
+		assert.Equal(t, [2]int{0, 1}, counts(poor))
+	})
+
+	t.Run("Invalid Votes", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, vote(user, good, true).Code, "own review")
+		assert.Equal(t, http.StatusBadRequest, vote(bob, good, nil).Code)
+		assert.Equal(t, http.StatusNotFound, call(h.VoteReview, http.MethodPut, "", map[string]interface{}{"helpful": true}, &bob, good.ID+poor.ID).Code)
+	})
+
+	t.Run("Sort And Paginate", func(t *testing.T) {
+		ids, metadata := list("")
+		assert.Equal(t, []int64{good.ID, poor.ID}, ids, "the most helpful first")
+		assert.Equal(t, 2, metadata["total"])
+
+		ids, _ = list("sort=lowest")
+		assert.Equal(t, []int64{poor.ID, good.ID}, ids)
+
+		ids, metadata = list("sort=highest&per_page=1&page=2")
+		assert.Equal(t, []int64{poor.ID}, ids)
+		assert.Equal(t, map[string]int{"page": 2, "per_page": 1, "total": 2}, metadata)
+
+		assert.Equal(t, http.StatusBadRequest, call(h.GetReviewsByProduct, http.MethodGet, "sort=best", nil, nil, 0).Code)
+	})
+
+	t.Run("Withdraw", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(h.DeleteReviewVote, http.MethodDelete, "", nil, &bob, poor.ID).Code)
+		assert.Equal(t, [2]int{0, 0}, counts(poor))
+		assert.Equal(t, http.StatusNotFound, call(h.DeleteReviewVote, http.MethodDelete, "", nil, &bob, poor.ID).Code)
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"keylab/database/models"
+	"keylab/i18n"
 	"net/http"
 	"strconv"
 
@@ -20,7 +21,7 @@ func (h *Handlers) GetAllRoles(c echo.Context) error {
 
 	includePermissions := c.QueryParam("include_permissions") == "true"
 
-	if err := h.DB.Find(&roles).Error; err != nil {
+	if err := h.db(c).Find(&roles).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching roles")
 	}
 
@@ -35,7 +36,7 @@ func (h *Handlers) GetAllRoles(c echo.Context) error {
 		for _, role := range roles {
 			var permissions []models.Permission
 
-			if err := h.DB.Model(&models.Permission{}).
+			if err := h.db(c).Model(&models.Permission{}).
 				Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
 				Where("role_permissions.role_id = ?", role.ID).
 				Find(&permissions).Error; err != nil {
@@ -70,12 +71,12 @@ func (h *Handlers) GetRoleById(c echo.Context) error {
 	}
 
 	var role models.Role
-	if err := h.DB.First(&role, id).Error; err != nil {
+	if err := h.db(c).First(&role, id).Error; err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Role not found")
 	}
 
 	var permissions []models.Permission
-	if err := h.DB.Model(&models.Permission{}).
+	if err := h.db(c).Model(&models.Permission{}).
 		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
 		Where("role_permissions.role_id = ?", id).
 		Find(&permissions).Error; err != nil {
@@ -104,18 +105,20 @@ func (h *Handlers) CreateRole(c echo.Context) error {
 	}
 
 	if err := role.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
 	var existingRole models.Role
-	if err := h.DB.Where("name = ?", role.Name).First(&existingRole).Error; err == nil {
+	if err := h.db(c).Where("name = ?", role.Name).First(&existingRole).Error; err == nil {
 		return jsonResponse(c, http.StatusBadRequest, "Role with this name already exists")
 	}
 
-	if err := h.DB.Create(&role).Error; err != nil {
+	if err := h.db(c).Create(&role).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error creating role")
 	}
 
+	h.recordAudit(c, "role.create", models.AuditEntityRole, role.ID, nil, role)
+
 	return jsonResponse(c, http.StatusCreated, "Role created successfully", role)
 }
 
@@ -137,7 +140,7 @@ func (h *Handlers) UpdateRole(c echo.Context) error {
 	}
 
 	var role models.Role
-	if err := h.DB.First(&role, id).Error; err != nil {
+	if err := h.db(c).First(&role, id).Error; err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Role not found")
 	}
 
@@ -151,21 +154,24 @@ func (h *Handlers) UpdateRole(c echo.Context) error {
 
 	if updatedRole.Name != role.Name {
 		var existingRole models.Role
-		if err := h.DB.Where("name = ? AND id != ?", updatedRole.Name, id).First(&existingRole).Error; err == nil {
+		if err := h.db(c).Where("name = ? AND id != ?", updatedRole.Name, id).First(&existingRole).Error; err == nil {
 			return jsonResponse(c, http.StatusBadRequest, "Role with this name already exists")
 		}
 	}
 
+	before := role
 	role.Name = updatedRole.Name
 
 	if err := role.Validate(); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 
-	if err := h.DB.Save(&role).Error; err != nil {
+	if err := h.db(c).Save(&role).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating role")
 	}
 
+	h.recordAudit(c, "role.update", models.AuditEntityRole, role.ID, before, role)
+
 	return jsonResponse(c, http.StatusOK, "Role updated successfully", role)
 }
 
@@ -186,22 +192,24 @@ func (h *Handlers) DeleteRole(c echo.Context) error {
 	}
 
 	var role models.Role
-	if err := h.DB.First(&role, id).Error; err != nil {
+	if err := h.db(c).First(&role, id).Error; err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Role not found")
 	}
 
-	if err := h.DB.Model(&models.User{}).Where("role_id = ?", id).Update("role_id", nil).Error; err != nil {
+	if err := h.db(c).Model(&models.User{}).Where("role_id = ?", id).Update("role_id", nil).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error updating user roles")
 	}
 
-	if err := h.DB.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+	if err := h.db(c).Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error removing role permissions")
 	}
 
-	if err := h.DB.Delete(&role).Error; err != nil {
+	if err := h.db(c).Delete(&role).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error deleting role")
 	}
 
+	h.recordAudit(c, "role.delete", models.AuditEntityRole, role.ID, role, nil)
+
 	return jsonResponse(c, http.StatusOK, "Role deleted successfully")
 }
 
@@ -224,7 +232,7 @@ func (h *Handlers) AddPermissionToRole(c echo.Context) error {
 	}
 
 	var role models.Role
-	if err := h.DB.First(&role, roleId).Error; err != nil {
+	if err := h.db(c).First(&role, roleId).Error; err != nil {
 		return jsonResponse(c, http.StatusNotFound, "Role not found")
 	}
 
@@ -238,12 +246,12 @@ func (h *Handlers) AddPermissionToRole(c echo.Context) error {
 
 	for _, permID := range request.PermissionIDs {
 		var perm models.Permission
-		if err := h.DB.First(&perm, permID).Error; err != nil {
-			return jsonResponse(c, http.StatusBadRequest, "Permission ID "+strconv.FormatInt(permID, 10)+" not found")
+		if err := h.db(c).First(&perm, permID).Error; err != nil {
+			return jsonResponse(c, http.StatusBadRequest, i18n.Format(requestLocale(c), "Permission ID {id} not found", map[string]string{"id": strconv.FormatInt(permID, 10)}))
 		}
 
 		var existingRP models.RolePermission
-		if err := h.DB.Where("role_id = ? AND permission_id = ?", roleId, permID).First(&existingRP).Error; err == nil {
+		if err := h.db(c).Where("role_id = ? AND permission_id = ?", roleId, permID).First(&existingRP).Error; err == nil {
 			continue
 		}
 
@@ -252,9 +260,14 @@ func (h *Handlers) AddPermissionToRole(c echo.Context) error {
 			PermissionID: permID,
 		}
 
-		if err := h.DB.Create(&rolePermission).Error; err != nil {
+		if err := h.db(c).Create(&rolePermission).Error; err != nil {
 			return jsonResponse(c, http.StatusInternalServerError, "Error adding permission to role")
 		}
+
+		h.recordAudit(c, "role.permission_add", models.AuditEntityRole, roleId, nil, map[string]interface{}{
+			"permission_id":   perm.ID,
+			"permission_name": perm.Name,
+		})
 	}
 
 	return jsonResponse(c, http.StatusOK, "Permissions added successfully")
@@ -279,7 +292,7 @@ func (h *Handlers) RemovePermissionFromRole(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid permission ID")
 	}
 
-	result := h.DB.Where("role_id = ? AND permission_id = ?", roleId, permId).Delete(&models.RolePermission{})
+	result := h.db(c).Where("role_id = ? AND permission_id = ?", roleId, permId).Delete(&models.RolePermission{})
 
 	if result.Error != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error removing permission from role")
@@ -289,6 +302,10 @@ func (h *Handlers) RemovePermissionFromRole(c echo.Context) error {
 		return jsonResponse(c, http.StatusNotFound, "Role-permission association not found")
 	}
 
+	h.recordAudit(c, "role.permission_remove", models.AuditEntityRole, roleId, map[string]interface{}{
+		"permission_id": permId,
+	}, nil)
+
 	return jsonResponse(c, http.StatusOK, "Permission removed from role")
 }
 
@@ -300,7 +317,7 @@ func (h *Handlers) RemovePermissionFromRole(c echo.Context) error {
 func (h *Handlers) GetAllPermissions(c echo.Context) error {
 	var permissions []models.Permission
 
-	if err := h.DB.Find(&permissions).Error; err != nil {
+	if err := h.db(c).Find(&permissions).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching permissions")
 	}
 
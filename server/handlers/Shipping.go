@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/apperr"
+	"keylab/database/models"
+	"keylab/repositories"
+	"keylab/shipping"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Get Shipping Options Handler [GET /cart/shipping-options?address_id=1]
+// 1. Fetches the user's cart and the address, which must be theirs.
+// 2. Quotes the shipping methods of the address's zone for the cart, in the request's currency.
+// 3. Returns status 200 with the options, cheapest first. Addresses outside every zone have none.
+// 4. Returns status 400 if the address ID is invalid.
+// 5. Returns status 404 if the address or cart items are not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetShippingOptions(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	addressID, err := convertToInt64(c.QueryParam("address_id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid address ID")
+	}
+
+	var address models.Address
+	if err := h.db(c).Where("id = ? AND user_id = ?", addressID, user.ID).First(&address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Address not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching address", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching address")
+	}
+
+	cartItems, err := repositories.GetCartItemsByUserID(user.ID, h.db(c))
+	if err != nil {
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching cart items")
+	}
+	if len(cartItems) == 0 {
+		return jsonResponse(c, http.StatusNotFound, "No cart items found for the user")
+	}
+
+	pricing, appErr := h.requestPricing(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := repositories.PriceCartItems(cartItems, pricing, h.db(c)); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error pricing cart items", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching prices")
+	}
+
+	options, err := repositories.GetShippingOptions(address, repositories.ShippingCart(cartItems), pricing, h.db(c))
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching shipping options", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching shipping options")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Shipping options fetched successfully", map[string]interface{}{
+		"currency": pricing.Currency,
+		"options":  options,
+	})
+}
+
+// chooseShipping picks the option the customer chose out of those for their address. Orders ship
+// without a method only while no shipping zones are set up at all.
+func chooseShipping(options []shipping.Option, zonesConfigured bool, methodID int64) (*shipping.Option, *apperr.Error) {
+	if len(options) == 0 {
+		if zonesConfigured {
+			return nil, apperr.BadRequest("Shipping not available for the address")
+		}
+		if methodID != 0 {
+			return nil, apperr.BadRequest("Shipping method not available for the address")
+		}
+		return nil, nil
+	}
+
+	if methodID == 0 {
+		return nil, apperr.BadRequest("Choose a shipping method")
+	}
+
+	for i := range options {
+		if options[i].MethodID == methodID {
+			return &options[i], nil
+		}
+	}
+
+	return nil, apperr.BadRequest("Shipping method not available for the address")
+}
+
+// Get Shipping Zones Handler [GET /admin/shipping/zones]
+// 1. Fetches every shipping zone with its methods.
+// 2. Returns status 200 with the zones.
+// 3. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetShippingZones(c echo.Context) error {
+	var zones []models.ShippingZone
+	if err := h.db(c).Preload("Methods").Order("country").Order("postal_prefix").Find(&zones).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching shipping zones", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching shipping zones")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Shipping zones fetched successfully", zones)
+}
+
+// Create Shipping Zone Handler [POST /admin/shipping/zones]
+// 1. Parses the zone from the request body and validates it.
+// 2. Checks no zone covers the same country and postal prefix.
+// 3. Returns status 201 with the zone if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 409 if the region already has a zone.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) CreateShippingZone(c echo.Context) error {
+	var zone models.ShippingZone
+	if err := c.Bind(&zone); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for shipping zone")
+	}
+	zone.ID = 0
+	zone.Methods = nil
+
+	if appErr := h.saveShippingZone(c, &zone); appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	h.recordAudit(c, "shipping_zone.create", models.AuditEntityShippingZone, zone.ID, nil, zone)
+
+	return jsonResponse(c, http.StatusCreated, "Shipping zone created", zone)
+}
+
+// Update Shipping Zone Handler [PUT /admin/shipping/zones/:id]
+// 1. Fetches the zone by ID.
+// 2. Parses the changes from the request body and validates them like a new zone.
+// 3. Returns status 200 with the zone if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 404 if the zone is not found.
+// 6. Returns status 409 if the region already has a zone.
+// 7. Returns status 500 if an error occurs.
+
+func (h *Handlers) UpdateShippingZone(c echo.Context) error {
+	zone, appErr := h.findShippingZone(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	before := zone
+
+	if err := c.Bind(&zone); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for shipping zone")
+	}
+	zone.ID = before.ID
+	zone.Methods = nil
+
+	if appErr := h.saveShippingZone(c, &zone); appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	h.recordAudit(c, "shipping_zone.update", models.AuditEntityShippingZone, zone.ID, before, zone)
+
+	return jsonResponse(c, http.StatusOK, "Shipping zone updated", zone)
+}
+
+// Delete Shipping Zone Handler [DELETE /admin/shipping/zones/:id]
+// 1. Fetches the zone by ID.
+// 2. Deletes it with its methods. Orders keep the name and cost of the method they used.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if the zone is not found.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteShippingZone(c echo.Context) error {
+	zone, appErr := h.findShippingZone(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := h.db(c).Delete(&zone).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting shipping zone", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting shipping zone")
+	}
+
+	h.recordAudit(c, "shipping_zone.delete", models.AuditEntityShippingZone, zone.ID, zone, nil)
+
+	return jsonResponse(c, http.StatusOK, "Shipping zone deleted", zone)
+}
+
+// findShippingZone fetches the shipping zone in the :id param.
+func (h *Handlers) findShippingZone(c echo.Context) (models.ShippingZone, *apperr.Error) {
+	var zone models.ShippingZone
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return zone, apperr.BadRequest("Invalid shipping zone ID")
+	}
+
+	if err := h.db(c).First(&zone, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return zone, apperr.NotFound("Shipping zone not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching shipping zone", "error", err)
+		return zone, apperr.Internal("Error fetching shipping zones", err)
+	}
+
+	return zone, nil
+}
+
+// saveShippingZone validates and saves a new or changed shipping zone.
+func (h *Handlers) saveShippingZone(c echo.Context, zone *models.ShippingZone) *apperr.Error {
+	zone.PostalPrefix = shipping.NormalizePostalCode(zone.PostalPrefix)
+	if err := zone.Validate(); err != nil {
+		return apperr.Validation(err)
+	}
+
+	var existing models.ShippingZone
+	err := h.db(c).Where("country = ? AND postal_prefix = ? AND id <> ?", zone.Country, zone.PostalPrefix, zone.ID).First(&existing).Error
+	if err == nil {
+		return apperr.Conflict("Shipping zone already exists for the region")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(c.Request().Context(), "Error fetching shipping zone", "error", err)
+		return apperr.Internal("Error fetching shipping zones", err)
+	}
+
+	if err := h.db(c).Omit("Methods").Save(zone).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving shipping zone", "error", err)
+		return apperr.Internal("Error saving shipping zone", err)
+	}
+
+	return nil
+}
+
+// Create Shipping Method Handler [POST /admin/shipping/methods]
+// 1. Parses the method from the request body and validates it.
+// 2. Checks its zone exists.
+// 3. Returns status 201 with the method if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) CreateShippingMethod(c echo.Context) error {
+	var method models.ShippingMethod
+	if err := c.Bind(&method); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for shipping method")
+	}
+	method.ID = 0
+
+	if appErr := h.saveShippingMethod(c, &method); appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	h.recordAudit(c, "shipping_method.create", models.AuditEntityShippingMethod, method.ID, nil, method)
+
+	return jsonResponse(c, http.StatusCreated, "Shipping method created", method)
+}
+
+// Update Shipping Method Handler [PUT /admin/shipping/methods/:id]
+// 1. Fetches the method by ID.
+// 2. Parses the changes from the request body and validates them like a new method.
+// 3. Returns status 200 with the method if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 404 if the method is not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) UpdateShippingMethod(c echo.Context) error {
+	method, appErr := h.findShippingMethod(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	before := method
+
+	if err := c.Bind(&method); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for shipping method")
+	}
+	method.ID = before.ID
+
+	if appErr := h.saveShippingMethod(c, &method); appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	h.recordAudit(c, "shipping_method.update", models.AuditEntityShippingMethod, method.ID, before, method)
+
+	return jsonResponse(c, http.StatusOK, "Shipping method updated", method)
+}
+
+// Delete Shipping Method Handler [DELETE /admin/shipping/methods/:id]
+// 1. Fetches the method by ID.
+// 2. Deletes it. Orders keep the name and cost of the method they used.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if the method is not found.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteShippingMethod(c echo.Context) error {
+	method, appErr := h.findShippingMethod(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := h.db(c).Delete(&method).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting shipping method", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting shipping method")
+	}
+
+	h.recordAudit(c, "shipping_method.delete", models.AuditEntityShippingMethod, method.ID, method, nil)
+
+	return jsonResponse(c, http.StatusOK, "Shipping method deleted", method)
+}
+
+// findShippingMethod is findShippingZone for shipping methods.
+func (h *Handlers) findShippingMethod(c echo.Context) (models.ShippingMethod, *apperr.Error) {
+	var method models.ShippingMethod
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return method, apperr.BadRequest("Invalid shipping method ID")
+	}
+
+	if err := h.db(c).First(&method, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return method, apperr.NotFound("Shipping method not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching shipping method", "error", err)
+		return method, apperr.Internal("Error fetching shipping method", err)
+	}
+
+	return method, nil
+}
+
+// saveShippingMethod validates and saves a new or changed shipping method.
+func (h *Handlers) saveShippingMethod(c echo.Context, method *models.ShippingMethod) *apperr.Error {
+	if err := method.Validate(); err != nil {
+		return apperr.Validation(err)
+	}
+
+	if err := h.db(c).First(&models.ShippingZone{}, method.ZoneID).Error; err != nil {
+		return apperr.BadRequest("Shipping zone not found")
+	}
+
+	if err := h.db(c).Save(method).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving shipping method", "error", err)
+		return apperr.Internal("Error saving shipping method", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/shipping"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShipping(t *testing.T) {
+	h, testDB, user, product := setupCartTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	assert.NoError(t, testDB.DB.Model(&product).Update("weight", 600).Error)
+
+	post := func(handler echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	var zone models.ShippingZone
+	t.Run("Zones And Methods", func(t *testing.T) {
+		rec := post(h.CreateShippingZone, `{"name": "Mainland", "country": "United Kingdom"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, http.StatusConflict, post(h.CreateShippingZone, `{"name": "Again", "country": "United Kingdom"}`).Code)
+		assert.Equal(t, http.StatusCreated, post(h.CreateShippingZone, `{"name": "Northern Ireland", "country": "United Kingdom", "postal_prefix": "bt"}`).Code)
+
+		var response struct {
+			Data models.ShippingZone `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		zone = response.Data
+
+		assert.Equal(t, http.StatusBadRequest, post(h.CreateShippingMethod, fmt.Sprintf(`{"zone_id": %d, "name": "Drone", "type": "drone", "rate_type": "flat"}`, zone.ID)).Code)
+		assert.Equal(t, http.StatusCreated, post(h.CreateShippingMethod, fmt.Sprintf(`{"zone_id": %d, "name": "Standard", "type": "standard", "rate_type": "free_over", "price": 3.95, "free_over": 50}`, zone.ID)).Code)
+		assert.Equal(t, http.StatusCreated, post(h.CreateShippingMethod, fmt.Sprintf(`{"zone_id": %d, "name": "Express", "type": "express", "rate_type": "weight", "price": 5, "per_kg": 2}`, zone.ID)).Code)
+		assert.Equal(t, http.StatusCreated, post(h.CreateShippingMethod, fmt.Sprintf(`{"zone_id": %d, "name": "Pickup", "type": "pickup", "rate_type": "flat"}`, zone.ID)).Code)
+	})
+
+	billing := models.Address{UserID: user.ID, Street: "1 Main", City: "London", County: "London", PostalCode: "SW1A 1AA", Country: "United Kingdom", Type: models.Billing}
+	shippingAddress := models.Address{UserID: user.ID, Street: "1 Main", City: "London", County: "London", PostalCode: "SW1A 1AA", Country: "United Kingdom", Type: models.Shipping}
+	assert.NoError(t, testDB.DB.Create(&billing).Error)
+	assert.NoError(t, testDB.DB.Create(&shippingAddress).Error)
+	assert.NoError(t, testDB.DB.Create(&models.CartItems{UserID: user.ID, ProductID: product.ID, Quantity: 2}).Error)
+
+	t.Run("Shipping Options", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/cart/shipping-options?address_id=%d", shippingAddress.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", user)
+
+		assert.NoError(t, h.GetShippingOptions(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data struct {
+				Options []shipping.Option `json:"options"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		options := response.Data.Options
+		assert.Len(t, options, 3)
+		assert.Equal(t, "Pickup", options[0].Name)
+		assert.Equal(t, "Standard", options[1].Name)
+		assert.True(t, options[1].Cost.IsZero(), "free over 50.00")
+		assert.Equal(t, "Express", options[2].Name)
+		assert.Equal(t, "9.00", options[2].Cost.String(), "5.00 and two started kilograms")
+	})
+
+	checkout := func(address models.Address, methodID int64) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"billing_address_id":  billing.ID,
+			"shipping_address_id": address.ID,
+			"shipping_method_id":  methodID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/cart/checkout", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", user)
+
+		assert.NoError(t, h.CheckoutCart(c))
+		return rec
+	}
+
+	t.Run("Checkout Outside The Zones", func(t *testing.T) {
+		belfast := models.Address{UserID: user.ID, Street: "1 Main", City: "Belfast", County: "Antrim", PostalCode: "BT1 1AA", Country: "United Kingdom", Type: models.Shipping}
+		paris := models.Address{UserID: user.ID, Street: "1 Rue", City: "Paris", County: "Paris", PostalCode: "75001", Country: "France", Type: models.Shipping}
+		assert.NoError(t, testDB.DB.Create(&belfast).Error)
+		assert.NoError(t, testDB.DB.Create(&paris).Error)
+
+		assert.Equal(t, http.StatusBadRequest, checkout(belfast, 0).Code, "the zone has no methods")
+		assert.Equal(t, http.StatusBadRequest, checkout(paris, 0).Code, "no zone covers the address")
+	})
+
+	t.Run("Checkout Stores The Method", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, checkout(shippingAddress, 0).Code)
+		assert.Equal(t, http.StatusBadRequest, checkout(shippingAddress, 999).Code)
+
+		var express models.ShippingMethod
+		assert.NoError(t, testDB.DB.Where("name = ?", "Express").First(&express).Error)
+		assert.Equal(t, http.StatusOK, checkout(shippingAddress, express.ID).Code)
+
+		var order models.Order
+		assert.NoError(t, testDB.DB.Where("user_id = ?", user.ID).First(&order).Error)
+		assert.Equal(t, &express.ID, order.ShippingMethodID)
+		assert.Equal(t, "Express", order.ShippingMethod)
+		assert.Equal(t, "9.00", order.ShippingCost.String())
+		assert.Equal(t, "59.00", order.Total.String())
+	})
+}
+
+func TestChooseShipping(t *testing.T) {
+	options := []shipping.Option{{MethodID: 1, Name: "Standard"}, {MethodID: 2, Name: "Express"}}
+
+	chosen, appErr := chooseShipping(options, true, 2)
+	if assert.Nil(t, appErr) {
+		assert.Equal(t, "Express", chosen.Name)
+	}
+
+	_, appErr = chooseShipping(options, true, 0)
+	assert.NotNil(t, appErr, "a method must be chosen")
+
+	_, appErr = chooseShipping(nil, true, 0)
+	assert.NotNil(t, appErr, "the address is outside every zone")
+
+	chosen, appErr = chooseShipping(nil, false, 0)
+	assert.Nil(t, appErr, "no zones are set up yet")
+	assert.Nil(t, chosen)
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/apperr"
+	"keylab/database/models"
+	"log/slog"
+	"net/http"
+
+	"github.com/gosimple/slug"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type taxClassRequest struct {
+	Name string `json:"name" form:"name"`
+	Slug string `json:"slug" form:"slug"`
+}
+
+// Get Tax Classes Handler [GET /admin/tax/classes]
+// 1. Fetches every tax class.
+// 2. Returns status 200 with the tax classes.
+// 3. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetTaxClasses(c echo.Context) error {
+	var taxClasses []models.TaxClass
+	if err := h.db(c).Order("name").Find(&taxClasses).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching tax classes", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching tax classes")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Tax classes fetched successfully", taxClasses)
+}
+
+// Create Tax Class Handler [POST /admin/tax/classes]
+// 1. Parses the tax class from the request body, the slug defaults to one made from the name.
+// 2. Checks no tax class has the same slug.
+// 3. Returns status 201 with the tax class if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 409 if the slug is taken.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) CreateTaxClass(c echo.Context) error {
+	var request taxClassRequest
+	if err := c.Bind(&request); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for tax class")
+	}
+
+	taxClass := models.TaxClass{Name: request.Name, Slug: request.Slug}
+	if taxClass.Slug == "" {
+		taxClass.Slug = slug.Make(taxClass.Name)
+	}
+
+	if err := taxClass.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Where("slug = ?", taxClass.Slug).First(&models.TaxClass{}).Error; err == nil {
+		return jsonResponse(c, http.StatusConflict, "Tax class already exists with the same slug")
+	}
+
+	if err := h.db(c).Create(&taxClass).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error creating tax class", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error creating tax class")
+	}
+
+	h.recordAudit(c, "tax_class.create", models.AuditEntityTaxClass, taxClass.ID, nil, taxClass)
+
+	return jsonResponse(c, http.StatusCreated, "Tax class created", taxClass)
+}
+
+// Update Tax Class Handler [PUT /admin/tax/classes/:id]
+// 1. Fetches the tax class by ID.
+// 2. Renames it. Slugs are kept, the standard class is found by its slug.
+// 3. Returns status 200 with the tax class if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 404 if the tax class is not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) UpdateTaxClass(c echo.Context) error {
+	taxClass, appErr := h.findTaxClass(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	before := taxClass
+
+	var request taxClassRequest
+	if err := c.Bind(&request); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for tax class")
+	}
+
+	taxClass.Name = request.Name
+	if err := taxClass.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Save(&taxClass).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error updating tax class", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error updating tax class")
+	}
+
+	h.recordAudit(c, "tax_class.update", models.AuditEntityTaxClass, taxClass.ID, before, taxClass)
+
+	return jsonResponse(c, http.StatusOK, "Tax class updated", taxClass)
+}
+
+// Delete Tax Class Handler [DELETE /admin/tax/classes/:id]
+// 1. Fetches the tax class by ID, the standard class cannot be deleted.
+// 2. Deletes it with its rates. Its categories fall back to the standard class.
+// 3. Returns status 200 if successful.
+// 4. Returns status 400 for the standard class.
+// 5. Returns status 404 if the tax class is not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteTaxClass(c echo.Context) error {
+	taxClass, appErr := h.findTaxClass(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if taxClass.Slug == models.StandardTaxClass {
+		return jsonResponse(c, http.StatusBadRequest, "The standard tax class cannot be deleted")
+	}
+
+	if err := h.db(c).Delete(&taxClass).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting tax class", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting tax class")
+	}
+
+	h.recordAudit(c, "tax_class.delete", models.AuditEntityTaxClass, taxClass.ID, taxClass, nil)
+
+	return jsonResponse(c, http.StatusOK, "Tax class deleted", taxClass)
+}
+
+// findTaxClass fetches the tax class in the :id param.
+func (h *Handlers) findTaxClass(c echo.Context) (models.TaxClass, *apperr.Error) {
+	var taxClass models.TaxClass
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return taxClass, apperr.BadRequest("Invalid tax class ID")
+	}
+
+	if err := h.db(c).First(&taxClass, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return taxClass, apperr.NotFound("Tax class not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching tax class", "error", err)
+		return taxClass, apperr.Internal("Error fetching tax classes", err)
+	}
+
+	return taxClass, nil
+}
+
+// Get Tax Rates Handler [GET /admin/tax/rates] or [GET /admin/tax/rates?country=United Kingdom&tax_class_id=1]
+// 1. Fetches the tax rates, optionally only those of a country or tax class.
+// 2. Returns status 200 with the tax rates.
+// 3. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetTaxRates(c echo.Context) error {
+	query := h.db(c).Order("country").Order("county").Order("tax_class_id")
+
+	if country := c.QueryParam("country"); country != "" {
+		query = query.Where("country = ?", country)
+	}
+	if taxClassID := c.QueryParam("tax_class_id"); taxClassID != "" {
+		query = query.Where("tax_class_id = ?", taxClassID)
+	}
+
+	var taxRates []models.TaxRate
+	if err := query.Find(&taxRates).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching tax rates", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching tax rates")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Tax rates fetched successfully", taxRates)
+}
+
+// Create Tax Rate Handler [POST /admin/tax/rates]
+// 1. Parses the tax rate from the request body and validates it.
+// 2. Checks the tax class exists and has no rate for the same country and county yet.
+// 3. Returns status 201 with the tax rate if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 409 if the region already has a rate for the class.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) CreateTaxRate(c echo.Context) error {
+	var taxRate models.TaxRate
+	if err := c.Bind(&taxRate); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for tax rate")
+	}
+	taxRate.ID = 0
+
+	if appErr := h.saveTaxRate(c, &taxRate); appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	h.recordAudit(c, "tax_rate.create", models.AuditEntityTaxRate, taxRate.ID, nil, taxRate)
+
+	return jsonResponse(c, http.StatusCreated, "Tax rate created", taxRate)
+}
+
+// Update Tax Rate Handler [PUT /admin/tax/rates/:id]
+// 1. Fetches the tax rate by ID.
+// 2. Parses the changes from the request body and validates them like a new rate.
+// 3. Returns status 200 with the tax rate if successful.
+// 4. Returns status 400 if the input is invalid.
+// 5. Returns status 404 if the tax rate is not found.
+// 6. Returns status 409 if the region already has a rate for the class.
+// 7. Returns status 500 if an error occurs.
+
+func (h *Handlers) UpdateTaxRate(c echo.Context) error {
+	taxRate, appErr := h.findTaxRate(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	before := taxRate
+
+	if err := c.Bind(&taxRate); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for tax rate")
+	}
+	taxRate.ID = before.ID
+
+	if appErr := h.saveTaxRate(c, &taxRate); appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	h.recordAudit(c, "tax_rate.update", models.AuditEntityTaxRate, taxRate.ID, before, taxRate)
+
+	return jsonResponse(c, http.StatusOK, "Tax rate updated", taxRate)
+}
+
+// Delete Tax Rate Handler [DELETE /admin/tax/rates/:id]
+// 1. Fetches the tax rate by ID.
+// 2. Deletes it, so its region is no longer taxed for the class.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if the tax rate is not found.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteTaxRate(c echo.Context) error {
+	taxRate, appErr := h.findTaxRate(c)
+	if appErr != nil {
+		return apperr.Render(c, appErr)
+	}
+
+	if err := h.db(c).Delete(&taxRate).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting tax rate", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting tax rate")
+	}
+
+	h.recordAudit(c, "tax_rate.delete", models.AuditEntityTaxRate, taxRate.ID, taxRate, nil)
+
+	return jsonResponse(c, http.StatusOK, "Tax rate deleted", taxRate)
+}
+
+// findTaxRate is findTaxClass for tax rates.
+func (h *Handlers) findTaxRate(c echo.Context) (models.TaxRate, *apperr.Error) {
+	var taxRate models.TaxRate
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return taxRate, apperr.BadRequest("Invalid tax rate ID")
+	}
+
+	if err := h.db(c).First(&taxRate, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return taxRate, apperr.NotFound("Tax rate not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching tax rate", "error", err)
+		return taxRate, apperr.Internal("Error fetching tax rates", err)
+	}
+
+	return taxRate, nil
+}
+
+// saveTaxRate validates and saves a new or changed tax rate.
+func (h *Handlers) saveTaxRate(c echo.Context, taxRate *models.TaxRate) *apperr.Error {
+	if err := taxRate.Validate(); err != nil {
+		return apperr.Validation(err)
+	}
+
+	if err := h.db(c).First(&models.TaxClass{}, taxRate.TaxClassID).Error; err != nil {
+		return apperr.BadRequest("Tax class not found")
+	}
+
+	var existing models.TaxRate
+	err := h.db(c).Where("tax_class_id = ? AND country = ? AND county = ? AND id <> ?", taxRate.TaxClassID, taxRate.Country, taxRate.County, taxRate.ID).First(&existing).Error
+	if err == nil {
+		return apperr.Conflict("Tax rate already exists for the region")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(c.Request().Context(), "Error fetching tax rate", "error", err)
+		return apperr.Internal("Error fetching tax rates", err)
+	}
+
+	if err := h.db(c).Save(taxRate).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving tax rate", "error", err)
+		return apperr.Internal("Error saving tax rate", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/tax"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTax(t *testing.T) {
+	h, testDB, user, product := setupCartTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	var standard models.TaxClass
+	assert.NoError(t, testDB.DB.Where("slug = ?", models.StandardTaxClass).First(&standard).Error)
+
+	createRate := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/tax/rates", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, h.CreateTaxRate(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("Tax Rates", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, createRate(fmt.Sprintf(`{"tax_class_id": %d, "country": "United Kingdom", "name": "VAT", "rate": 120}`, standard.ID)).Code)
+		assert.Equal(t, http.StatusBadRequest, createRate(`{"tax_class_id": 999, "country": "United Kingdom", "name": "VAT", "rate": 20}`).Code)
+		assert.Equal(t, http.StatusCreated, createRate(fmt.Sprintf(`{"tax_class_id": %d, "country": "United Kingdom", "name": "VAT", "rate": 20}`, standard.ID)).Code)
+		assert.Equal(t, http.StatusConflict, createRate(fmt.Sprintf(`{"tax_class_id": %d, "country": "United Kingdom", "name": "VAT", "rate": 5}`, standard.ID)).Code)
+	})
+
+	t.Run("Standard Class Cannot Be Deleted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(standard.ID))
+
+		assert.NoError(t, h.DeleteTaxClass(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Checkout Records Tax", func(t *testing.T) {
+		billing := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "United Kingdom", Type: models.Billing}
+		shipping := models.Address{UserID: user.ID, Street: "1 Main", City: "City", County: "County", PostalCode: "12345", Country: "united kingdom", Type: models.Shipping}
+		assert.NoError(t, testDB.DB.Create(&billing).Error)
+		assert.NoError(t, testDB.DB.Create(&shipping).Error)
+		assert.NoError(t, testDB.DB.Create(&models.CartItems{UserID: user.ID, ProductID: product.ID, Quantity: 2}).Error)
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"billing_address_id":  billing.ID,
+			"shipping_address_id": shipping.ID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/cart/checkout", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user", user)
+
+		assert.NoError(t, h.CheckoutCart(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var order models.Order
+		assert.NoError(t, testDB.DB.Preload("OrderItems").Preload("Taxes").Where("user_id = ?", user.ID).First(&order).Error)
+		assert.Equal(t, tax.Inclusive, order.TaxMode)
+		assert.Equal(t, "50.00", order.Total.String())
+		assert.Equal(t, "8.33", order.TaxTotal.String())
+
+		assert.Len(t, order.Taxes, 1)
+		assert.Equal(t, "VAT", order.Taxes[0].Name)
+		assert.Equal(t, "20", order.Taxes[0].Rate.String())
+		assert.Equal(t, "41.67", order.Taxes[0].Taxable.String())
+
+		assert.Len(t, order.OrderItems, 1)
+		assert.Equal(t, "VAT", order.OrderItems[0].TaxName)
+		assert.Equal(t, "8.33", order.OrderItems[0].TaxAmount.String())
+	})
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"errors"
+	"keylab/database/models"
+	"keylab/i18n"
+	"keylab/repositories"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type translationRequest struct {
+	Name        string `json:"name" form:"name"`
+	Description string `json:"description" form:"description"`
+}
+
+// localizeProducts shows products in the request's locale. A failed lookup is logged and the
+// products are served in the default language rather than failing the request.
+func (h *Handlers) localizeProducts(c echo.Context, products []models.Product) {
+	if err := repositories.LocalizeProducts(products, requestLocale(c), h.db(c)); err != nil {
+		slog.WarnContext(c.Request().Context(), "Error localizing products", "locale", requestLocale(c), "error", err)
+	}
+}
+
+// localizeCategories is localizeProducts for categories.
+func (h *Handlers) localizeCategories(c echo.Context, categories ...*models.ProductCategory) {
+	if err := repositories.LocalizeCategories(categories, requestLocale(c), h.db(c)); err != nil {
+		slog.WarnContext(c.Request().Context(), "Error localizing categories", "locale", requestLocale(c), "error", err)
+	}
+}
+
+// translationLocale reads the :locale param. Only the non-default supported locales can have
+// translations, the default language lives on the product or category itself.
+func translationLocale(c echo.Context) (string, bool) {
+	locale := c.Param("locale")
+	return locale, locale != i18n.Default && i18n.IsSupported(locale)
+}
+
+// Get Product Translations Handler [GET /products/:id/translations]
+// 1. Fetches the product by ID.
+// 2. Returns status 200 with every translation of the product.
+// 3. Returns status 404 if the product is not found.
+// 4. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetProductTranslations(c echo.Context) error {
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
+	}
+
+	product, err := repositories.GetProductByID(id, h.db(c))
+	if err != nil {
+		return jsonResponse(c, http.StatusNotFound, "Product not found")
+	}
+
+	var translations []models.ProductTranslation
+	if err := h.db(c).Where("product_id = ?", product.ID).Order("locale").Find(&translations).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching product translations", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching translations")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Translations fetched successfully", translations)
+}
+
+// Save Product Translation Handler [PUT /products/:id/translations/:locale]
+// 1. Validates the locale and fetches the product by ID.
+// 2. Creates the translation, or replaces the existing one for the locale.
+// 3. Returns status 200 with the translation if successful.
+// 4. Returns status 400 if the locale or input is invalid.
+// 5. Returns status 404 if the product is not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) SaveProductTranslation(c echo.Context) error {
+	locale, ok := translationLocale(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid locale")
+	}
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
+	}
+
+	product, err := repositories.GetProductByID(id, h.db(c))
+	if err != nil {
+		return jsonResponse(c, http.StatusNotFound, "Product not found")
+	}
+
+	var request translationRequest
+	if err := c.Bind(&request); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for translation")
+	}
+
+	var translation models.ProductTranslation
+	err = h.db(c).Where("product_id = ? AND locale = ?", product.ID, locale).First(&translation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(c.Request().Context(), "Error fetching product translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching translations")
+	}
+
+	var before interface{}
+	if translation.ID != 0 {
+		before = translation
+	}
+
+	translation.ProductID = product.ID
+	translation.Locale = locale
+	translation.Name = request.Name
+	translation.Description = request.Description
+
+	if err := translation.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Save(&translation).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving product translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error saving translation")
+	}
+
+	h.recordAudit(c, "product_translation.save", models.AuditEntityProductTranslation, translation.ID, before, translation)
+
+	return jsonResponse(c, http.StatusOK, "Translation saved", translation)
+}
+
+// Delete Product Translation Handler [DELETE /products/:id/translations/:locale]
+// 1. Fetches the product's translation for the locale.
+// 2. Deletes it, so the product falls back to the default language.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if there is no such translation.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteProductTranslation(c echo.Context) error {
+	locale, ok := translationLocale(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid locale")
+	}
+
+	id, err := convertToInt64(c.Param("id"))
+	if err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid product ID")
+	}
+
+	var translation models.ProductTranslation
+	if err := h.db(c).Where("product_id = ? AND locale = ?", id, locale).First(&translation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Translation not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching product translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching translations")
+	}
+
+	if err := h.db(c).Delete(&translation).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting product translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting translation")
+	}
+
+	h.recordAudit(c, "product_translation.delete", models.AuditEntityProductTranslation, translation.ID, translation, nil)
+
+	return jsonResponse(c, http.StatusOK, "Translation deleted", translation)
+}
+
+// Get Category Translations Handler [GET /categories/:slug/translations]
+// 1. Fetches the category by slug.
+// 2. Returns status 200 with every translation of the category.
+// 3. Returns status 404 if the category is not found.
+// 4. Returns status 500 if an error occurs.
+
+func (h *Handlers) GetCategoryTranslations(c echo.Context) error {
+	var category models.ProductCategory
+	if err := h.db(c).Where("slug = ?", c.Param("slug")).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Category not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching category by slug", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching category by slug")
+	}
+
+	var translations []models.ProductCategoryTranslation
+	if err := h.db(c).Where("category_id = ?", category.ID).Order("locale").Find(&translations).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error fetching category translations", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching translations")
+	}
+
+	return jsonResponse(c, http.StatusOK, "Translations fetched successfully", translations)
+}
+
+// Save Category Translation Handler [PUT /categories/:slug/translations/:locale]
+// 1. Validates the locale and fetches the category by slug.
+// 2. Creates the translation, or replaces the existing one for the locale.
+// 3. Returns status 200 with the translation if successful.
+// 4. Returns status 400 if the locale or input is invalid.
+// 5. Returns status 404 if the category is not found.
+// 6. Returns status 500 if an error occurs.
+
+func (h *Handlers) SaveCategoryTranslation(c echo.Context) error {
+	locale, ok := translationLocale(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid locale")
+	}
+
+	var category models.ProductCategory
+	if err := h.db(c).Where("slug = ?", c.Param("slug")).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Category not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching category by slug", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching category by slug")
+	}
+
+	var request translationRequest
+	if err := c.Bind(&request); err != nil {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid input for translation")
+	}
+
+	var translation models.ProductCategoryTranslation
+	err := h.db(c).Where("category_id = ? AND locale = ?", category.ID, locale).First(&translation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(c.Request().Context(), "Error fetching category translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching translations")
+	}
+
+	var before interface{}
+	if translation.ID != 0 {
+		before = translation
+	}
+
+	translation.CategoryID = category.ID
+	translation.Locale = locale
+	translation.Name = request.Name
+	translation.Description = request.Description
+
+	if err := translation.Validate(); err != nil {
+		return validationError(c, err)
+	}
+
+	if err := h.db(c).Save(&translation).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error saving category translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error saving translation")
+	}
+
+	h.recordAudit(c, "category_translation.save", models.AuditEntityCategoryTranslation, translation.ID, before, translation)
+
+	return jsonResponse(c, http.StatusOK, "Translation saved", translation)
+}
+
+// Delete Category Translation Handler [DELETE /categories/:slug/translations/:locale]
+// 1. Fetches the category's translation for the locale.
+// 2. Deletes it, so the category falls back to the default language.
+// 3. Returns status 200 if successful.
+// 4. Returns status 404 if there is no such translation.
+// 5. Returns status 500 if an error occurs.
+
+func (h *Handlers) DeleteCategoryTranslation(c echo.Context) error {
+	locale, ok := translationLocale(c)
+	if !ok {
+		return jsonResponse(c, http.StatusBadRequest, "Invalid locale")
+	}
+
+	var translation models.ProductCategoryTranslation
+	err := h.db(c).
+		Joins("JOIN product_categories ON product_categories.id = product_category_translations.category_id").
+		Where("product_categories.slug = ? AND product_category_translations.locale = ?", c.Param("slug"), locale).
+		First(&translation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jsonResponse(c, http.StatusNotFound, "Translation not found")
+		}
+
+		slog.ErrorContext(c.Request().Context(), "Error fetching category translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error fetching translations")
+	}
+
+	if err := h.db(c).Delete(&translation).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error deleting category translation", "error", err)
+		return jsonResponse(c, http.StatusInternalServerError, "Error deleting translation")
+	}
+
+	h.recordAudit(c, "category_translation.delete", models.AuditEntityCategoryTranslation, translation.ID, translation, nil)
+
+	return jsonResponse(c, http.StatusOK, "Translation deleted", translation)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "keylab/database"
+	"keylab/i18n"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductTranslations(t *testing.T) {
+	h, testDB, product := setupProductTest(t)
+	defer db.CleanupTestDB(t, testDB)
+
+	e := echo.New()
+
+	saveTranslation := func(locale string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/products/%d/translations/%s", product.ID, locale), strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "locale")
+		c.SetParamValues(fmt.Sprint(product.ID), locale)
+
+		assert.NoError(t, h.SaveProductTranslation(c))
+		return rec
+	}
+
+	getProduct := func(locale string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/products/"+product.Slug, nil)
+		req = req.WithContext(i18n.WithLocale(req.Context(), locale))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("slug")
+		c.SetParamValues(product.Slug)
+
+		assert.NoError(t, h.GetProductBySlug(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("Unsupported Locale", func(t *testing.T) {
+		rec := saveTranslation("fr", `{"name": "Produit", "description": "Un produit"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = saveTranslation(i18n.Default, `{"name": "Product", "description": "A product"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Missing Fields", func(t *testing.T) {
+		rec := saveTranslation("nl", `{"name": "Voorbeeldproduct"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Save And Replace", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, saveTranslation("nl", `{"name": "Voorbeeld", "description": "Dit is een voorbeeld"}`).Code)
+		assert.Equal(t, http.StatusOK, saveTranslation("nl", `{"name": "Voorbeeldproduct", "description": "Dit is een voorbeeldproduct"}`).Code)
+
+		var count int64
+		testDB.DB.Table("product_translations").Where("product_id = ?", product.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Catalog Uses Best Locale", func(t *testing.T) {
+		response := getProduct("nl")
+		assert.Equal(t, "Product gevonden", response["message"])
+		data := response["data"].(map[string]interface{})
+		assert.Equal(t, "Voorbeeldproduct", data["name"])
+		assert.Equal(t, "Dit is een voorbeeldproduct", data["description"])
+	})
+
+	t.Run("Catalog Falls Back To Default", func(t *testing.T) {
+		data := getProduct("de")["data"].(map[string]interface{})
+		assert.Equal(t, product.Name, data["name"])
+		assert.Equal(t, product.Description, data["description"])
+	})
+
+	t.Run("Delete Translation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/products/%d/translations/nl", product.ID), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "locale")
+		c.SetParamValues(fmt.Sprint(product.ID), "nl")
+
+		assert.NoError(t, h.DeleteProductTranslation(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		data := getProduct("nl")["data"].(map[string]interface{})
+		assert.Equal(t, product.Name, data["name"])
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"keylab/database/models"
 	"keylab/repositories"
 	"keylab/utils"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -25,24 +25,24 @@ func (h *Handlers) GetUserProfile(c echo.Context) error {
 
 	authenticatedUser, ok := c.Get("user").(models.User)
 	if !ok {
-		log.Println("Unauthorized access attempt")
+		slog.WarnContext(c.Request().Context(), "Unauthorized access attempt")
 		return jsonResponse(c, http.StatusUnauthorized, "Unauthorized")
 	}
 
 	userID, err := convertToInt64(c.Param("id"))
 	if err != nil {
-		log.Printf("Invalid user ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Invalid user ID", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
 
 	if authenticatedUser.ID != userID {
-		log.Printf("Unauthorized access attempt by user %d to access user %d", authenticatedUser.ID, userID)
+		slog.WarnContext(c.Request().Context(), "Unauthorized access attempt", "user_id", authenticatedUser.ID, "target_user_id", userID)
 		return jsonResponse(c, http.StatusForbidden, "Access denied")
 	}
 
-	user, err := repositories.FindUserByID(userID, h.DB)
+	user, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
-		log.Printf("User not found: %v", err)
+		slog.ErrorContext(c.Request().Context(), "User not found", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "User not found")
 	}
 
@@ -72,7 +72,7 @@ func (h *Handlers) UpdateUserProfile(c echo.Context) error {
 		return jsonResponse(c, http.StatusUnauthorized, "Unauthorized to update this profile")
 	}
 
-	existingUser, err := repositories.FindUserByID(userID, h.DB)
+	existingUser, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "User not found")
 	}
@@ -84,11 +84,11 @@ func (h *Handlers) UpdateUserProfile(c echo.Context) error {
 
 	updatedUser.Email = existingUser.Email
 
-	if err := h.DB.Model(&existingUser).Omit("password").Updates(updatedUser).Error; err != nil {
+	if err := h.db(c).Model(&existingUser).Omit("password").Updates(updatedUser).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Could not update user")
 	}
 
-	fullUser, err := repositories.FindUserByID(userID, h.DB)
+	fullUser, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to retrieve updated user")
 	}
@@ -109,7 +109,7 @@ func (h *Handlers) UpdateUserProfile(c echo.Context) error {
 func (h *Handlers) ChangeUserPassword(c echo.Context) error {
 	userID, err := convertToInt64(c.Param("id"))
 	if err != nil {
-		log.Printf("Invalid user ID: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Invalid user ID", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
 
@@ -125,7 +125,7 @@ func (h *Handlers) ChangeUserPassword(c echo.Context) error {
 	}
 
 	if err := c.Bind(&body); err != nil {
-		log.Printf("Error binding password data: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error binding password data", "error", err)
 		return jsonResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 
@@ -133,26 +133,26 @@ func (h *Handlers) ChangeUserPassword(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "New password and confirmation do not match")
 	}
 
-	user, err := repositories.FindUserByID(userID, h.DB)
+	user, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
-		log.Printf("User not found: %v", err)
+		slog.ErrorContext(c.Request().Context(), "User not found", "error", err)
 		return jsonResponse(c, http.StatusNotFound, "User not found")
 	}
 
 	if utils.ComparePasswordHash(body.CurrentPassword, user.Password) != nil {
-		log.Printf("Incorrect password for user ID: %d", userID)
+		slog.WarnContext(c.Request().Context(), "Incorrect password", "user_id", userID)
 		return jsonResponse(c, http.StatusUnauthorized, "Incorrect password")
 	}
 
 	hashedPassword, err := utils.HashPassword(body.NewPassword)
 	if err != nil {
-		log.Printf("Error hashing password: %v", err)
+		slog.ErrorContext(c.Request().Context(), "Error hashing password", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Could not update password")
 	}
 
 	user.Password = hashedPassword
-	if err := h.DB.Model(&user).Select("Password").Updates(&user).Error; err != nil {
-		log.Printf("Error updating password: %v", err)
+	if err := h.db(c).Model(&user).Select("Password").Updates(&user).Error; err != nil {
+		slog.ErrorContext(c.Request().Context(), "Error updating password", "error", err)
 		return jsonResponse(c, http.StatusInternalServerError, "Could not update password")
 	}
 
@@ -182,7 +182,7 @@ func (h *Handlers) GetUsersOrders(c echo.Context) error {
 	}
 
 	var orders []models.Order
-	if err := h.DB.Where("user_id = ?", userID).Preload("OrderItems").Preload("OrderItems.Product").Find(&orders).Error; err != nil {
+	if err := h.db(c).Where("user_id = ?", userID).Preload("OrderItems").Preload("OrderItems.Product").Find(&orders).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Error fetching orders")
 	}
 
@@ -207,16 +207,18 @@ func (h *Handlers) UpdateUserByAdmin(c echo.Context) error {
 	if err != nil {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
-	existingUser, err := repositories.FindUserByID(userID, h.DB)
+	existingUser, err := repositories.FindUserByID(userID, h.db(c))
 	if err != nil {
 		return jsonResponse(c, http.StatusNotFound, "User not found")
 	}
+	before := existingUser
+
 	var updatedUser models.User
 	if err := c.Bind(&updatedUser); err != nil {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid request body")
 	}
 	if err := updatedUser.Validate("Email", "Forename", "Surname"); err != nil {
-		return jsonResponse(c, http.StatusBadRequest, err.Error())
+		return validationError(c, err)
 	}
 	updates := map[string]interface{}{
 		"forename":     updatedUser.Forename,
@@ -224,9 +226,12 @@ func (h *Handlers) UpdateUserByAdmin(c echo.Context) error {
 		"email":        updatedUser.Email,
 		"phone_number": updatedUser.PhoneNumber,
 	}
-	if err := h.DB.Model(&existingUser).Updates(updates).Error; err != nil {
+	if err := h.db(c).Model(&existingUser).Updates(updates).Error; err != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to update user")
 	}
+
+	h.recordAudit(c, "user.update", models.AuditEntityUser, existingUser.ID, before, existingUser)
+
 	return jsonResponse(c, http.StatusOK, "User updated successfully", existingUser)
 }
 
@@ -242,10 +247,14 @@ func (h *Handlers) DeleteUserByAdmin(c echo.Context) error {
 		return jsonResponse(c, http.StatusBadRequest, "Invalid user ID")
 	}
 
-	result := h.DB.Model(&models.User{}).Where("id = ?", userID).Update("is_deleted", true)
+	result := h.db(c).Model(&models.User{}).Where("id = ?", userID).Update("is_deleted", true)
 	if result.Error != nil {
 		return jsonResponse(c, http.StatusInternalServerError, "Failed to delete user")
 	}
 
+	if result.RowsAffected > 0 {
+		h.recordAudit(c, "user.delete", models.AuditEntityUser, userID, map[string]interface{}{"is_deleted": false}, map[string]interface{}{"is_deleted": true})
+	}
+
 	return jsonResponse(c, http.StatusOK, "User deleted successfully")
 }
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"bytes"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -55,3 +58,56 @@ func TestGetSortOrder(t *testing.T) {
 	order = getSortOrder(c)
 	assert.Equal(t, "created_at desc", order)
 }
+
+func TestProductSortOrder(t *testing.T) {
+	e := echo.New()
+	order := func(query string) string {
+		req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		return productSortOrder(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	assert.Equal(t, "average_rating desc, review_count desc", order("sort=rating"))
+	assert.Equal(t, "average_rating asc, review_count asc", order("sort=rating&direction=asc"))
+	assert.Equal(t, "name asc", order("sort=name&direction=asc"))
+}
+
+func TestUploadImages(t *testing.T) {
+	e := echo.New()
+	upload := func(destination string, filenames ...string) ([]map[string]interface{}, int) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for _, filename := range filenames {
+			part, err := writer.CreateFormFile("images", filename)
+			assert.NoError(t, err)
+			_, _ = part.Write([]byte("image"))
+		}
+		assert.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+		uploaded, appErr := uploadImages(e.NewContext(req, httptest.NewRecorder()), "images", destination, []string{".png"})
+		if appErr != nil {
+			return nil, appErr.Status
+		}
+		return uploaded, http.StatusOK
+	}
+
+	t.Run("Saved", func(t *testing.T) {
+		destination := t.TempDir()
+		uploaded, status := upload(destination, "a.png", "b.PNG")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Len(t, uploaded, 2)
+	})
+
+	t.Run("Checked Before Saving", func(t *testing.T) {
+		destination := t.TempDir()
+		_, status := upload(destination, "a.png", "b.gif")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		entries, err := os.ReadDir(destination)
+		assert.NoError(t, err)
+		assert.Empty(t, entries, "nothing is written when one of the files is rejected")
+	})
+
+}
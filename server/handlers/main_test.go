@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"fmt"
+	"keylab/config"
+	"os"
+	"testing"
+)
+
+// TestMain loads a known configuration so the tests do not depend on a .env file or the
+// environment of whoever runs them.
+func TestMain(m *testing.M) {
+	_, err := config.Initialize([]string{
+		"--env-file", "",
+		"--client-url", "http://localhost:5173",
+		"--server-url", "http://localhost:8080",
+		"--sessions-key", "test-sessions-key-with-32-characters",
+		"--hash-key", "test-hash-key-with-at-least-32-chars",
+		"--mariadb-user", "keylab",
+		"--mariadb-password", "password",
+		"--mariadb-host", "localhost",
+		"--mariadb-database", "keylab",
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+}
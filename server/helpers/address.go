@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ListenAddress turns a public URL such as SERVER_URL into the address to listen on. URLs without
+// an explicit port fall back to the scheme's default port.
+func ListenAddress(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if parsedURL.Host == "" {
+		return "", fmt.Errorf("URL %q has no host", rawURL)
+	}
+
+	port := parsedURL.Port()
+	if port == "" {
+		switch parsedURL.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return "", fmt.Errorf("URL %q has no port and no known default for scheme %q", rawURL, parsedURL.Scheme)
+		}
+	}
+
+	return ":" + port, nil
+}
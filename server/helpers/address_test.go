@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenAddress(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		isError  bool
+	}{
+		{"http://localhost:8080", ":8080", false},
+		{"https://api.keylab.example", ":443", false},
+		{"http://api.keylab.example/", ":80", false},
+		{"http://[::1]:9000", ":9000", false},
+		{"ftp://api.keylab.example", "", true},
+		{"localhost:8080", "", true},
+		{"", "", true},
+	}
+
+	for _, test := range tests {
+		result, err := ListenAddress(test.input)
+		if test.isError {
+			assert.Error(t, err, test.input)
+		} else {
+			assert.NoError(t, err, test.input)
+			assert.Equal(t, test.expected, result)
+		}
+	}
+}
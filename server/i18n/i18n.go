@@ -0,0 +1,123 @@
+// Package i18n picks the response language for a request and translates API messages.
+//
+// Messages are written in English throughout the code and English is the key into every
+// catalog, so a message without a translation is simply returned as it is. Catalogs live in
+// locales/<locale>.json as a flat object of English message to translation. Messages with
+// variable parts use {name} placeholders, which Format fills in after translating.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the language the messages are written in and the fallback for every lookup.
+const Default = "en"
+
+// Supported lists every locale the API answers in, Default first.
+var Supported = []string{Default, "nl", "de"}
+
+//go:embed locales/*.json
+var files embed.FS
+
+var (
+	catalogs = mustLoadCatalogs()
+	matcher  = newMatcher()
+)
+
+func mustLoadCatalogs() map[string]map[string]string {
+	loaded := make(map[string]map[string]string, len(Supported))
+	for _, locale := range Supported[1:] {
+		data, err := files.ReadFile(path.Join("locales", locale+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %q: %v", locale, err))
+		}
+
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %q: %v", locale, err))
+		}
+
+		loaded[locale] = messages
+	}
+
+	return loaded
+}
+
+func newMatcher() language.Matcher {
+	tags := make([]language.Tag, len(Supported))
+	for i, locale := range Supported {
+		tags[i] = language.MustParse(locale)
+	}
+
+	return language.NewMatcher(tags)
+}
+
+// Match returns the supported locale that best fits an Accept-Language header, e.g. "nl" for
+// "nl-BE,nl;q=0.9,en;q=0.8". An empty or unparsable header, or one that only names languages
+// we do not have, gives Default.
+func Match(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+
+	return Supported[index]
+}
+
+// IsSupported reports whether locale is one of Supported.
+func IsSupported(locale string) bool {
+	for _, supported := range Supported {
+		if supported == locale {
+			return true
+		}
+	}
+
+	return false
+}
+
+// T translates an English message into locale, falling back to the message itself.
+func T(locale string, message string) string {
+	if translated, ok := catalogs[locale][message]; ok && translated != "" {
+		return translated
+	}
+
+	return message
+}
+
+// Format translates template and then replaces each {name} placeholder with args[name].
+func Format(locale string, template string, args map[string]string) string {
+	message := T(locale, template)
+	for name, value := range args {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+
+	return message
+}
+
+type localeKey struct{}
+
+// WithLocale stores the request's locale on ctx.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale stored by WithLocale, or Default.
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+
+	return Default
+}
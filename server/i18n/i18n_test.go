@@ -0,0 +1,146 @@
+package i18n
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := map[string]string{
+		"":                              Default,
+		"not a header;;":                Default,
+		"nl":                            "nl",
+		"nl-BE,nl;q=0.9,en;q=0.8":       "nl",
+		"de-AT":                         "de",
+		"fr-FR,de;q=0.5":                "de",
+		"en-GB,en;q=0.9":                "en",
+		"ja,zh;q=0.8":                   Default,
+		"en;q=0.4,nl;q=0.6":             "nl",
+		"*":                             Default,
+		"de-CH, de;q=0.9, en;q=0.8, *;": "de",
+	}
+
+	for header, expected := range tests {
+		assert.Equal(t, expected, Match(header), "Accept-Language: %q", header)
+	}
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	assert.Equal(t, "Product niet gevonden", T("nl", "Product not found"))
+	assert.Equal(t, "Produkt nicht gefunden", T("de", "Product not found"))
+	assert.Equal(t, "Product not found", T("en", "Product not found"))
+	assert.Equal(t, "Product not found", T("fr", "Product not found"))
+	assert.Equal(t, "Some new message", T("nl", "Some new message"))
+}
+
+func TestFormat(t *testing.T) {
+	args := map[string]string{"field": "email"}
+	assert.Equal(t, "email is required", Format("en", "{field} is required", args))
+	assert.Equal(t, "email is verplicht", Format("nl", "{field} is required", args))
+	assert.Equal(t, "Berechtigungs-ID 7 nicht gefunden", Format("de", "Permission ID {id} not found", map[string]string{"id": "7"}))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, Default, FromContext(ctx))
+	assert.Equal(t, "de", FromContext(WithLocale(ctx, "de")))
+}
+
+// Every catalog translates the same messages and keeps their placeholders, so a message added
+// for one language is not forgotten in another.
+func TestCatalogsAreComplete(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{[a-z_]+\}`)
+
+	reference := catalogs[Supported[1]]
+	for _, locale := range Supported[1:] {
+		catalog := catalogs[locale]
+		assert.Equal(t, sortedKeys(reference), sortedKeys(catalog), "catalog %s", locale)
+
+		for message, translated := range catalog {
+			assert.NotEmpty(t, translated, "catalog %s: %q", locale, message)
+			assert.ElementsMatch(t, placeholder.FindAllString(message, -1), placeholder.FindAllString(translated, -1), "catalog %s: %q", locale, message)
+		}
+	}
+}
+
+// The messages the server renders are in the catalogs: literal messages passed to jsonResponse
+// and the apperr constructors anywhere in the module.
+func TestRenderedMessagesAreTranslated(t *testing.T) {
+	// Position of the message among each function's arguments
+	messageArg := map[string]int{
+		"jsonResponse":        2,
+		"apperr.New":          2,
+		"apperr.FromStatus":   1,
+		"apperr.BadRequest":   0,
+		"apperr.Unauthorized": 0,
+		"apperr.Forbidden":    0,
+		"apperr.NotFound":     0,
+		"apperr.Conflict":     0,
+		"apperr.Internal":     0,
+	}
+
+	catalog := catalogs[Supported[1]]
+	fset := token.NewFileSet()
+	err := filepath.WalkDir("..", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			var name string
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				if pkg, ok := fun.X.(*ast.Ident); ok {
+					name = pkg.Name + "." + fun.Sel.Name
+				}
+			}
+
+			index, ok := messageArg[name]
+			if !ok || index >= len(call.Args) {
+				return true
+			}
+
+			if literal, ok := call.Args[index].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				message, _ := strconv.Unquote(literal.Value)
+				_, translated := catalog[message]
+				assert.True(t, translated, "%s: %q is not in the catalogs", fset.Position(literal.Pos()), message)
+			}
+			return true
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func sortedKeys(catalog map[string]string) []string {
+	keys := make([]string, 0, len(catalog))
+	for key := range catalog {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDIsAddedFromContext(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := Setup(&buffer, "info", "json")
+	defer slog.SetDefault(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	logger.InfoContext(ctx, "Handled request")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, "abc123", record["request_id"])
+	assert.Equal(t, "Handled request", record["msg"])
+}
+
+func TestRedactStruct(t *testing.T) {
+	config := struct {
+		SERVER_URL       string
+		SESSIONS_KEY     string
+		MARIADB_PASSWORD string
+		HASH_KEY         string
+	}{
+		SERVER_URL:       "http://localhost:8080",
+		SESSIONS_KEY:     "0123456789abcdef0123456789abcdef",
+		MARIADB_PASSWORD: "hunter2",
+	}
+
+	value := RedactStruct(config).String()
+	assert.Contains(t, value, "http://localhost:8080")
+	assert.NotContains(t, value, "0123456789abcdef0123456789abcdef")
+	assert.NotContains(t, value, "hunter2")
+	assert.Contains(t, value, "HASH_KEY=")
+}
+
+func TestRedactValues(t *testing.T) {
+	value := RedactValues(map[interface{}]interface{}{
+		"user_id":    int64(7),
+		"csrf_token": "secret-token",
+	}).String()
+
+	assert.Contains(t, value, "user_id=7")
+	assert.NotContains(t, value, "secret-token")
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+const Redacted = "[REDACTED]"
+
+var secretNameParts = []string{"password", "secret", "key", "token"}
+
+// IsSecret reports whether a configuration or session field name looks like it holds a secret.
+func IsSecret(name string) bool {
+	lower := strings.ToLower(name)
+	for _, part := range secretNameParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactStruct renders the exported fields of a struct as a slog group, replacing every non-empty
+// field whose name looks like a secret with [REDACTED].
+func RedactStruct(value interface{}) slog.Value {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if v.Kind() != reflect.Struct {
+		return slog.AnyValue(value)
+	}
+
+	attrs := make([]slog.Attr, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if IsSecret(field.Name) && !v.Field(i).IsZero() {
+			attrs = append(attrs, slog.String(field.Name, Redacted))
+			continue
+		}
+
+		attrs = append(attrs, slog.Any(field.Name, v.Field(i).Interface()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// RedactValues renders session values as a slog group, keeping only identifiers and replacing
+// everything else with [REDACTED].
+func RedactValues(values map[interface{}]interface{}) slog.Value {
+	attrs := make([]slog.Attr, 0, len(values))
+	for key, value := range values {
+		name, _ := key.(string)
+		if name == "user_id" {
+			attrs = append(attrs, slog.Any(name, value))
+			continue
+		}
+
+		attrs = append(attrs, slog.String(name, Redacted))
+	}
+
+	return slog.GroupValue(attrs...)
+}
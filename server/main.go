@@ -1,47 +1,124 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"keylab/config"
-	db "keylab/database"
-	"keylab/routes"
-	"log"
-	"net/url"
+	"keylab/logging"
+	"os"
 	"strings"
-
-	"github.com/gorilla/sessions"
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 )
 
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"serve", "run the API server (default when no command is given)", serve},
+	{"migrate", "manage the database schema", migrateCommand},
+	{"seed", "load development seed data", seed},
+	{"create-admin", "create an admin user, or promote an existing one", createAdmin},
+	{"users", "manage users", usersCommand},
+	{"config", "print the effective configuration with secrets redacted", printConfig},
+}
+
 func main() {
-	config := config.Initialize()
-	fmt.Println("Starting KeyLab server...")
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return serve(args)
+	}
 
-	e := echo.New()
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     []string{config.CLIENT_URL},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept},
-		AllowCredentials: true,
-	}))
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+
+	if args[0] == "help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "keylab: unknown command %q\n\n", args[0])
+	printUsage(os.Stderr)
+	return 2
+}
 
-	if len(config.SESSIONS_KEY) < 32 || len(config.HASH_KEY) < 32 {
-		e.Logger.Fatal("SESSIONS_KEY and HASH_KEY are empty or need to be at least 32 characters.")
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: keylab <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Every command accepts the configuration flags, run "keylab <command> -h" to list them.`)
+}
 
-	session := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
-	session.Options.HttpOnly = true
-	session.Options.Secure = true
+// newFlagSet returns the flag set for a command, the configuration flags are added by
+// config.InitializeFlags.
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet("keylab "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: keylab %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
 
-	db := db.InitDB()
-	routes.RegisterRoutes(e, session, db)
+	return fs
+}
 
-	parsedURL, err := url.Parse(config.SERVER_URL)
+// initialize loads the configuration for a management command and sends logs to stderr, keeping
+// stdout for the command's own output.
+func initialize(fs *flag.FlagSet, args []string) (*config.Config, error) {
+	loaded, err := config.InitializeFlags(fs, args)
 	if err != nil {
-		log.Fatalf("Error parsing backend URL: %v", err)
+		return nil, err
+	}
+
+	logging.Setup(os.Stderr, loaded.LOG_LEVEL, loaded.LOG_FORMAT)
+	return loaded, nil
+}
+
+// fail reports err and returns the exit code. Asking for help is not a failure.
+func fail(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, "keylab:", err)
+	return 1
+}
+
+// usageError reports a wrong invocation and prints the command's usage.
+func usageError(fs *flag.FlagSet, format string, a ...interface{}) int {
+	fmt.Fprintf(os.Stderr, "keylab: "+format+"\n\n", a...)
+	fs.Usage()
+	return 2
+}
+
+// printConfig implements "config": it prints the effective configuration with secrets redacted,
+// followed by any validation errors.
+func printConfig(args []string) int {
+	loaded, err := config.LoadFlags(newFlagSet("config", "[flags]"), args)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := loaded.WriteRedacted(os.Stdout); err != nil {
+		return fail(err)
+	}
+
+	if err := loaded.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return 1
 	}
 
-	fmt.Println("Server started at", config.SERVER_URL)
-	port := strings.Split(parsedURL.Host, ":")[1]
-	e.Logger.Fatal(e.Start(":" + port))
+	return 0
 }
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareUsesRouteTemplate(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/products/:slug", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues(http.MethodGet, "/products/:slug", "200"))
+
+	for _, slug := range []string{"keychron-k8", "varmilo-va87m"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products/"+slug, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	after := testutil.ToFloat64(HTTPRequests.WithLabelValues(http.MethodGet, "/products/:slug", "200"))
+	assert.Equal(t, float64(2), after-before)
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	handler := Handler("metrics-token")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer metrics-token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), "keylab_checkouts_total"))
+}
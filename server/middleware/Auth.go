@@ -1,10 +1,10 @@
 package middleware
 
 import (
+	"keylab/apperr"
 	"keylab/database/models"
 	"keylab/handlers"
 	"keylab/repositories"
-	"net/http"
 
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
@@ -17,17 +17,17 @@ func AuthMiddleware(sessionStore *sessions.CookieStore, db *gorm.DB) echo.Middle
 			session, err := sessionStore.Get(c.Request(), handlers.SessionName)
 
 			if err != nil || session.Values["user_id"] == nil {
-				return c.JSON(http.StatusUnauthorized, "Unauthorized")
+				return apperr.Unauthorized("Unauthorized")
 			}
 
 			userID, ok := session.Values["user_id"].(int64)
 			if !ok {
-				return c.JSON(http.StatusUnauthorized, "Invalid session data")
+				return apperr.Unauthorized("Invalid session data")
 			}
 
 			user, err := repositories.FindUserByID(userID, db)
 			if err != nil {
-				return c.JSON(http.StatusUnauthorized, "Unauthorized")
+				return apperr.Unauthorized("Unauthorized")
 			}
 
 			c.Set("user", user)
@@ -42,16 +42,16 @@ func PermissionMiddleware(db *gorm.DB, requiredPermissions ...string) echo.Middl
 
 			user, ok := c.Get("user").(models.User)
 			if !ok {
-				return c.JSON(http.StatusUnauthorized, "Unauthorized user")
+				return apperr.Unauthorized("Unauthorized user")
 			}
 
 			hasPermission, err := repositories.CheckRolePermissions(user.RoleID, requiredPermissions, db)
 			if err != nil {
-				return c.JSON(http.StatusInternalServerError, "A error occured! Please contact administration")
+				return apperr.Internal("A error occured! Please contact administration", err)
 			}
 
 			if !hasPermission {
-				return c.JSON(http.StatusForbidden, "You do not have access to this resource")
+				return apperr.Forbidden("You do not have access to this resource")
 			}
 
 			return next(c)
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"keylab/apperr"
+	"keylab/handlers"
+	"keylab/utils"
+	"net/http"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+)
+
+// CSRFMiddleware implements the synchronizer token pattern for cookie authenticated requests.
+// Safe methods pass through and every other request must echo the session's token in the
+// X-CSRF-Token header. Tokens are issued by [GET /auth/csrf]. There is no bearer exemption, since
+// requests are only ever authenticated by the session cookie.
+func CSRFMiddleware(sessionStore *sessions.CookieStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+				return next(c)
+			}
+
+			session, err := sessionStore.Get(c.Request(), handlers.SessionName)
+			if err != nil {
+				return apperr.New(http.StatusForbidden, apperr.CodeInvalidCSRFToken, "Invalid CSRF token")
+			}
+
+			expected, _ := session.Values[handlers.CSRFSessionKey].(string)
+			if !utils.CompareCSRFToken(c.Request().Header.Get(handlers.CSRFHeader), expected) {
+				return apperr.New(http.StatusForbidden, apperr.CodeInvalidCSRFToken, "Invalid CSRF token")
+			}
+
+			return next(c)
+		}
+	}
+}
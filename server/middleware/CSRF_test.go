@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"keylab/apperr"
+	"keylab/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	e := echo.New()
+	sessionStore := sessions.NewCookieStore([]byte("0123456789abcdef0123456789abcdef"), []byte("abcdef0123456789abcdef0123456789"))
+
+	// Issue a session cookie carrying a known CSRF token.
+	req := httptest.NewRequest(http.MethodGet, "/auth/csrf", nil)
+	rec := httptest.NewRecorder()
+	session, _ := sessionStore.Get(req, handlers.SessionName)
+	session.Values[handlers.CSRFSessionKey] = "known-token"
+	assert.NoError(t, session.Save(req, rec))
+	cookie := rec.Result().Cookies()[0]
+
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	handler := CSRFMiddleware(sessionStore)(next)
+
+	tests := []struct {
+		name          string
+		method        string
+		token         string
+		authorization string
+		withCookie    bool
+		want          int
+	}{
+		{name: "Safe Method", method: http.MethodGet, withCookie: true, want: http.StatusOK},
+		{name: "Missing Token", method: http.MethodPost, withCookie: true, want: http.StatusForbidden},
+		{name: "Wrong Token", method: http.MethodPut, token: "wrong-token", withCookie: true, want: http.StatusForbidden},
+		{name: "No Session", method: http.MethodPost, token: "known-token", want: http.StatusForbidden},
+		{name: "Valid Token", method: http.MethodDelete, token: "known-token", withCookie: true, want: http.StatusOK},
+		{name: "Bearer Not Exempt", method: http.MethodPost, authorization: "Bearer abc", withCookie: true, want: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/cart/checkout", nil)
+			if test.withCookie {
+				req.AddCookie(cookie)
+			}
+			if test.token != "" {
+				req.Header.Set(handlers.CSRFHeader, test.token)
+			}
+			if test.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, test.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if err := handler(c); err != nil {
+				apperr.ErrorHandler(err, c)
+			}
+			assert.Equal(t, test.want, rec.Code)
+		})
+	}
+}
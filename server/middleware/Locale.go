@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"keylab/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+// LocaleMiddleware picks the response language from the Accept-Language header and stores it on
+// the request's context.Context for handlers and the error handler. The chosen locale is echoed
+// in Content-Language, and Vary tells caches the response depends on the header.
+func LocaleMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := i18n.Match(c.Request().Header.Get(headerAcceptLanguage))
+
+			c.SetRequest(c.Request().WithContext(i18n.WithLocale(c.Request().Context(), locale)))
+			c.Response().Header().Set(headerContentLanguage, locale)
+			c.Response().Header().Add(echo.HeaderVary, headerAcceptLanguage)
+
+			return next(c)
+		}
+	}
+}
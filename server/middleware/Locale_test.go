@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"keylab/i18n"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	tests := map[string]string{
+		"":                        i18n.Default,
+		"de-AT,de;q=0.9":          "de",
+		"nl-BE,nl;q=0.9,en;q=0.8": "nl",
+		"fr-FR":                   i18n.Default,
+	}
+
+	for header, expected := range tests {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Accept-Language", header)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var locale string
+		handler := LocaleMiddleware()(func(c echo.Context) error {
+			locale = i18n.FromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+
+		assert.NoError(t, handler(c))
+		assert.Equal(t, expected, locale, "Accept-Language: %q", header)
+		assert.Equal(t, expected, rec.Header().Get("Content-Language"))
+		assert.Equal(t, "Accept-Language", rec.Header().Get(echo.HeaderVary))
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"keylab/logging"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+const RequestIDKey = "request_id"
+
+// RequestIDMiddleware reuses an incoming X-Request-ID or generates one, echoes it in the response,
+// and stores it on both the echo.Context and the request's context.Context so that handler,
+// repository and GORM logs for the request all carry it.
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return echomiddleware.RequestIDWithConfig(echomiddleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			c.Set(RequestIDKey, requestID)
+			c.SetRequest(c.Request().WithContext(logging.WithRequestID(c.Request().Context(), requestID)))
+		},
+	})
+}
+
+// RequestLoggerMiddleware writes one structured access log record per request.
+func RequestLoggerMiddleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURI:       true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v echomiddleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			if v.Status >= 500 {
+				level = slog.LevelError
+			} else if v.Status >= 400 {
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", v.URI),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+
+			logger.LogAttrs(c.Request().Context(), level, "HTTP request", attrs...)
+			return nil
+		},
+	})
+}
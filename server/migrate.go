@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	db "keylab/database"
+	"strconv"
+)
+
+// migrateCommand implements "migrate up|down|goto|status".
+func migrateCommand(args []string) int {
+	fs := newFlagSet("migrate", "up | down [steps] | goto <version> | status [flags]")
+	if _, err := initialize(fs, args); err != nil {
+		return fail(err)
+	}
+
+	if fs.NArg() == 0 {
+		return usageError(fs, "missing migrate subcommand")
+	}
+
+	database := db.InitDB()
+
+	var err error
+	switch fs.Arg(0) {
+	case "up":
+		err = db.MigrateUp(database)
+
+	case "down":
+		steps := 1
+		if fs.NArg() > 1 {
+			if steps, err = strconv.Atoi(fs.Arg(1)); err != nil || steps < 1 {
+				return usageError(fs, "steps must be a positive number, got %q", fs.Arg(1))
+			}
+		}
+		err = db.MigrateDown(database, steps)
+
+	case "goto":
+		if fs.NArg() < 2 {
+			return usageError(fs, "missing version")
+		}
+		version, parseErr := strconv.ParseUint(fs.Arg(1), 10, 32)
+		if parseErr != nil {
+			return usageError(fs, "invalid version %q", fs.Arg(1))
+		}
+		err = db.MigrateGoto(database, uint(version))
+
+	case "status":
+	default:
+		return usageError(fs, "unknown migrate subcommand %q", fs.Arg(0))
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	state, err := db.GetMigrationState(context.Background(), database)
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Printf("current: %d\nlatest:  %d\npending: %d\n", state.Current, state.Latest, state.Pending())
+	if state.Dirty {
+		fmt.Printf("migration %d is dirty, fix the schema by hand and migrate goto a clean version\n", state.Current)
+		return 1
+	}
+
+	return 0
+}
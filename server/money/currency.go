@@ -0,0 +1,66 @@
+package money
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const (
+	GBP Currency = "GBP"
+	EUR Currency = "EUR"
+	USD Currency = "USD"
+)
+
+// symbols holds every currency the shop can price in. All of them have two decimal places.
+var symbols = map[Currency]string{
+	GBP: "£",
+	EUR: "€",
+	USD: "$",
+}
+
+// ParseCurrency returns the supported currency for a code such as "gbp" or "EUR".
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := symbols[currency]; !ok {
+		return "", fmt.Errorf("unsupported currency %q", code)
+	}
+
+	return currency, nil
+}
+
+// Currencies lists the supported currency codes in alphabetical order.
+func Currencies() []Currency {
+	currencies := make([]Currency, 0, len(symbols))
+	for currency := range symbols {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+
+	return currencies
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Symbol returns the currency's sign, e.g. "£", or its code when it has none.
+func (c Currency) Symbol() string {
+	if symbol, ok := symbols[c]; ok {
+		return symbol
+	}
+
+	return string(c)
+}
+
+// Format writes an amount for people to read, e.g. "£19.99" or "-€0.50".
+func (c Currency) Format(m Money) string {
+	if m.IsNegative() {
+		return "-" + c.Symbol() + m.Neg().String()
+	}
+
+	return c.Symbol() + m.String()
+}
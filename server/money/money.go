@@ -0,0 +1,266 @@
+// Package money does exact arithmetic on prices and totals.
+//
+// A Money holds a whole number of minor units (pence, cents), so adding prices and multiplying
+// them by quantities is exact. Every supported currency has two decimal places, which is also
+// the scale of the DECIMAL(10,2) columns amounts are stored in.
+//
+// Rounding rules: parsing never rounds, an amount with more than two decimal places is an
+// error. Multiplying by a fraction (a percentage, an exchange rate, a tax rate) rounds the
+// result half away from zero to the nearest minor unit. Round once per line and add the
+// rounded lines up, never round a sum again.
+//
+// A Money carries no currency code of its own. The currency of every stored amount is fixed by
+// where it lives: catalog prices and shipping rates are in BASE_CURRENCY, a ProductPrice row
+// names its currency, and an order and its items record their Currency and ExchangeRate on the
+// row. Amounts only change currency through Convert, which pricing applies to products and
+// shipping methods before any arithmetic, so the totals of a cart or order only ever add amounts
+// that are already in the same currency. Use Currency.Format to display one.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of minor units in a major unit.
+const Scale = 100
+
+// Money is an exact amount in minor units. The zero value is 0.00.
+type Money struct {
+	minor int64
+}
+
+var (
+	ErrInvalid   = errors.New("invalid amount")
+	ErrPrecision = errors.New("amount has more than two decimal places")
+)
+
+// FromMinor returns the amount of the given number of minor units, e.g. FromMinor(1999) is 19.99.
+func FromMinor(minor int64) Money {
+	return Money{minor: minor}
+}
+
+// Parse reads a decimal amount such as "19.99", "-0.5" or "20". It is exact, so "19.999" is an
+// error rather than being rounded.
+func Parse(s string) (Money, error) {
+	minor, err := parseScaled(s, Scale)
+	if errors.Is(err, errNotExact) {
+		return Money{}, fmt.Errorf("%w: %q", ErrPrecision, s)
+	}
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{minor: minor}, nil
+}
+
+var errNotExact = errors.New("not exact")
+
+// parseScaled reads a decimal number as a whole multiple of 1/scale.
+func parseScaled(s string, scale int64) (int64, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	r.Mul(r, big.NewRat(scale, 1))
+	if !r.IsInt() {
+		return 0, errNotExact
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalid, s)
+	}
+
+	return r.Num().Int64(), nil
+}
+
+// MustParse is Parse for amounts known to be valid, such as literals in seeders and tests.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// FromFloat converts a float from outside the shop, rounding half away from zero. The float is
+// read as its shortest decimal representation first, so 1.005 becomes 1.01 rather than 1.00.
+func FromFloat(f float64) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return Money{}
+	}
+
+	return Money{minor: round(r.Mul(r, big.NewRat(Scale, 1)))}
+}
+
+// Minor returns the amount in minor units.
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Float64 returns the amount in major units. It is meant for metrics and display, never for
+// further arithmetic.
+func (m Money) Float64() float64 {
+	return float64(m.minor) / Scale
+}
+
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.minor < o.minor:
+		return -1
+	case m.minor > o.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{minor: m.minor + o.minor}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{minor: m.minor - o.minor}
+}
+
+func (m Money) Neg() Money {
+	return Money{minor: -m.minor}
+}
+
+// Mul multiplies by a whole quantity, which is exact.
+func (m Money) Mul(quantity int64) Money {
+	return Money{minor: m.minor * quantity}
+}
+
+// MulRat multiplies by a fraction and rounds the result half away from zero.
+func (m Money) MulRat(r *big.Rat) Money {
+	product := new(big.Rat).SetInt64(m.minor)
+	return Money{minor: round(product.Mul(product, r))}
+}
+
+// Convert converts the amount into another currency at rate, rounding half away from zero.
+func (m Money) Convert(rate Rate) Money {
+	return m.MulRat(rate.Rat())
+}
+
+// Percent returns percent of m, e.g. 17.5% of 10.00 is 1.75, rounded half away from zero. The
+// percentage itself is a Money so that it keeps the two decimal places it is stored with.
+func (m Money) Percent(percent Money) Money {
+	return m.MulRat(big.NewRat(percent.minor, 100*Scale))
+}
+
+// round rounds r half away from zero to an integer.
+func round(r *big.Rat) int64 {
+	num, denom := new(big.Int).Set(r.Num()), r.Denom()
+
+	quotient, remainder := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(denom) >= 0 {
+		if num.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient.Int64()
+}
+
+// String formats the amount with exactly two decimal places, e.g. "19.90" or "-0.05".
+func (m Money) String() string {
+	sign, minor := "", m.minor
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, minor/Scale, minor%Scale)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places, e.g. 19.90, so clients
+// that read prices as numbers keep working while the digits stay exact.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding a decimal amount.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	text := string(data)
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam lets Echo bind form and query values.
+func (m *Money) UnmarshalParam(param string) error {
+	return m.UnmarshalText([]byte(param))
+}
+
+// Scan reads a DECIMAL column, which the MySQL driver returns as text, without going through a
+// float.
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		return m.UnmarshalText(v)
+	case string:
+		return m.UnmarshalText([]byte(v))
+	case int64:
+		*m = Money{minor: v * Scale}
+		return nil
+	case float64:
+		*m = FromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into money.Money", value)
+	}
+}
+
+// Value writes the amount as decimal text, which DECIMAL columns store exactly.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,157 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	valid := map[string]int64{
+		"0":           0,
+		"0.01":        1,
+		"19.99":       1999,
+		"19.9":        1990,
+		"20":          2000,
+		"-0.05":       -5,
+		" 7.50 ":      750,
+		"1e2":         10000,
+		"99999999.99": 9999999999,
+	}
+	for input, minor := range valid {
+		m, err := Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, minor, m.Minor(), input)
+	}
+
+	_, err := Parse("19.999")
+	assert.ErrorIs(t, err, ErrPrecision)
+
+	for _, input := range []string{"", "abc", "1,50", "£5"} {
+		_, err := Parse(input)
+		assert.ErrorIs(t, err, ErrInvalid, input)
+	}
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "0.00", Money{}.String())
+	assert.Equal(t, "19.90", FromMinor(1990).String())
+	assert.Equal(t, "-0.05", FromMinor(-5).String())
+	assert.Equal(t, "1234.05", FromMinor(123405).String())
+}
+
+// Adding floats drifts, 0.1 + 0.2 is not 0.3, and a cart of ten 0.10 items totals 0.9999999.
+func TestArithmeticIsExact(t *testing.T) {
+	total := Money{}
+	for i := 0; i < 10; i++ {
+		total = total.Add(MustParse("0.10"))
+	}
+	assert.Equal(t, MustParse("1.00"), total)
+
+	assert.Equal(t, MustParse("0.30"), MustParse("0.10").Add(MustParse("0.20")))
+	assert.Equal(t, MustParse("209.97"), MustParse("69.99").Mul(3))
+	assert.Equal(t, MustParse("-0.01"), MustParse("19.99").Sub(MustParse("20.00")))
+}
+
+func TestRoundingIsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		amount  string
+		percent string
+		want    string
+	}{
+		{"10.00", "17.50", "1.75"},
+		{"0.05", "50.00", "0.03"},   // 0.025 rounds up
+		{"0.07", "50.00", "0.04"},   // 0.035 rounds up
+		{"0.09", "50.00", "0.05"},   // 0.045 rounds up, not to even
+		{"-0.05", "50.00", "-0.03"}, // and away from zero for negatives
+		{"19.99", "20.00", "4.00"},  // 3.998
+		{"0.01", "1.00", "0.00"},    // 0.0001
+	}
+
+	for _, test := range tests {
+		got := MustParse(test.amount).Percent(MustParse(test.percent))
+		assert.Equal(t, test.want, got.String(), "%s%% of %s", test.percent, test.amount)
+	}
+
+	assert.Equal(t, "1.16", MustParse("1.00").MulRat(big.NewRat(11589, 10000)).String())
+}
+
+func TestFromFloat(t *testing.T) {
+	assert.Equal(t, int64(101), FromFloat(1.005).Minor())
+	assert.Equal(t, int64(6999), FromFloat(69.99).Minor())
+	assert.Equal(t, int64(-250), FromFloat(-2.5).Minor())
+}
+
+func TestJSON(t *testing.T) {
+	type product struct {
+		Price Money `json:"price"`
+	}
+
+	data, err := json.Marshal(product{Price: MustParse("19.9")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price": 19.90}`, string(data))
+	assert.Equal(t, `{"price":19.90}`, string(data))
+
+	var fromNumber, fromString product
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": 0.30}`), &fromNumber))
+	assert.NoError(t, json.Unmarshal([]byte(`{"price": "0.30"}`), &fromString))
+	assert.Equal(t, int64(30), fromNumber.Price.Minor())
+	assert.Equal(t, fromNumber, fromString)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 0.305}`), &fromNumber))
+}
+
+func TestSQL(t *testing.T) {
+	var m Money
+	assert.NoError(t, m.Scan([]byte("99999999.99")))
+	assert.Equal(t, int64(9999999999), m.Minor())
+
+	value, err := m.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "99999999.99", value)
+
+	assert.NoError(t, m.Scan(int64(3)))
+	assert.Equal(t, "3.00", m.String())
+	assert.Error(t, m.Scan(true))
+}
+
+func TestCurrency(t *testing.T) {
+	currency, err := ParseCurrency(" eur")
+	assert.NoError(t, err)
+	assert.Equal(t, EUR, currency)
+
+	_, err = ParseCurrency("XYZ")
+	assert.Error(t, err)
+
+	assert.Equal(t, "£19.99", GBP.Format(MustParse("19.99")))
+	assert.Equal(t, "-€0.50", EUR.Format(MustParse("-0.5")))
+	assert.Equal(t, []Currency{EUR, GBP, USD}, Currencies())
+}
+
+func TestRate(t *testing.T) {
+	rate, err := ParseRate("1.1589")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(115890000), rate.Scaled())
+	assert.Equal(t, "1.1589", rate.String())
+	assert.Equal(t, "1", RateOne.String())
+
+	_, err = ParseRate("1.123456789")
+	assert.ErrorIs(t, err, ErrRatePrecision)
+
+	assert.Equal(t, "23.17", MustParse("19.99").Convert(rate).String()) // 23.166411
+	assert.Equal(t, "19.99", MustParse("19.99").Convert(RateOne).String())
+
+	var scanned Rate
+	assert.NoError(t, scanned.Scan([]byte("0.85000000")))
+	assert.Equal(t, "0.85", scanned.String())
+
+	value, err := scanned.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.85000000", value)
+
+	data, err := json.Marshal(map[string]Rate{"rate": rate})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"rate":1.1589}`, string(data))
+}
@@ -0,0 +1,134 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the precision of rates, eight decimal places as in their DECIMAL(18,8) columns.
+const RateScale = 100000000
+
+// Rate is an exact decimal with up to eight places, for exchange rates (the amount of one
+// currency that buys one unit of another) and tax percentages. The zero value is not a usable
+// exchange rate.
+type Rate struct {
+	scaled int64
+}
+
+// ErrRatePrecision is returned for rates with more than eight decimal places.
+var ErrRatePrecision = errors.New("rate has more than eight decimal places")
+
+// RateOne is the rate of a currency to itself.
+var RateOne = Rate{scaled: RateScale}
+
+// ParseRate reads a decimal rate such as "1.1589". Like Parse it never rounds.
+func ParseRate(s string) (Rate, error) {
+	scaled, err := parseScaled(s, RateScale)
+	if errors.Is(err, errNotExact) {
+		return Rate{}, fmt.Errorf("%w: %q", ErrRatePrecision, s)
+	}
+	if err != nil {
+		return Rate{}, err
+	}
+
+	return Rate{scaled: scaled}, nil
+}
+
+// MustParseRate is ParseRate for rates known to be valid.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Scaled returns the rate in units of 1/RateScale.
+func (r Rate) Scaled() int64 {
+	return r.scaled
+}
+
+// Rat returns the rate as an exact fraction.
+func (r Rate) Rat() *big.Rat {
+	return big.NewRat(r.scaled, RateScale)
+}
+
+func (r Rate) IsPositive() bool {
+	return r.scaled > 0
+}
+
+// String formats the rate without trailing zeros, e.g. "1.1589" or "1".
+func (r Rate) String() string {
+	return strings.TrimSuffix(strings.TrimRight(r.Rat().FloatString(8), "0"), ".")
+}
+
+// MarshalJSON writes the rate as a JSON number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding a decimal rate.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	text := string(data)
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	return r.UnmarshalText([]byte(text))
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalText(text []byte) error {
+	parsed, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// UnmarshalParam lets Echo bind form and query values.
+func (r *Rate) UnmarshalParam(param string) error {
+	return r.UnmarshalText([]byte(param))
+}
+
+// Scan reads a DECIMAL column without going through a float.
+func (r *Rate) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case []byte:
+		return r.UnmarshalText(v)
+	case string:
+		return r.UnmarshalText([]byte(v))
+	case int64:
+		*r = Rate{scaled: v * RateScale}
+		return nil
+	case float64:
+		return r.UnmarshalText([]byte(strconv.FormatFloat(v, 'f', -1, 64)))
+	default:
+		return fmt.Errorf("cannot scan %T into money.Rate", value)
+	}
+}
+
+// Value writes the rate as decimal text.
+func (r Rate) Value() (driver.Value, error) {
+	return r.Rat().FloatString(8), nil
+}
@@ -0,0 +1,242 @@
+// Package notify tells staff about things in the shop that need their attention, such as products
+// running low on stock.
+//
+// A Notifier delivers the alerts. The shop logs them and can also send them by email and to a
+// webhook; anything else, such as a chat integration, only needs to implement Notifier.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"keylab/config"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// LowStock is a product whose stock has fallen to its reorder threshold or below.
+type LowStock struct {
+	ProductID int64  `json:"product_id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Stock     int    `json:"stock"`
+	Threshold int    `json:"threshold"`
+}
+
+// Notifier delivers alerts to staff.
+type Notifier interface {
+	LowStock(ctx context.Context, products []LowStock) error
+}
+
+// Multi delivers alerts through every notifier, returning all of their errors.
+type Multi []Notifier
+
+func (m Multi) LowStock(ctx context.Context, products []LowStock) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.LowStock(ctx, products); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Log writes alerts to the application log.
+type Log struct{}
+
+func (Log) LowStock(ctx context.Context, products []LowStock) error {
+	for _, product := range products {
+		slog.WarnContext(ctx, "Product stock is low", "product_id", product.ProductID, "slug", product.Slug, "stock", product.Stock, "threshold", product.Threshold)
+	}
+
+	return nil
+}
+
+// Webhook posts alerts as JSON to a URL, e.g. {"event": "low_stock", "products": [...]}.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+func (w Webhook) LowStock(ctx context.Context, products []LowStock) error {
+	body, err := json.Marshal(map[string]interface{}{"event": "low_stock", "products": products})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("low stock webhook: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = webhookClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("low stock webhook: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("low stock webhook: %s", response.Status)
+	}
+
+	return nil
+}
+
+// Email sends alerts as a plain text email through an SMTP server. Addr is its host:port; From
+// and To may include names, e.g. "Keylab <shop@example.com>".
+type Email struct {
+	Addr      string
+	Auth      smtp.Auth
+	From      string
+	To        []string
+	StoreName string
+}
+
+// smtpTimeout bounds a whole email delivery, like webhookClient's timeout does for webhooks.
+const smtpTimeout = 10 * time.Second
+
+// sendMail is replaced in tests.
+var sendMail = sendMailContext
+
+// sendMailContext is smtp.SendMail with a deadline: the delivery gives up after smtpTimeout, or
+// when ctx is done if that is sooner.
+func sendMailContext(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(msg); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (e Email) LowStock(ctx context.Context, products []LowStock) error {
+	subject := fmt.Sprintf("%s: %d products low on stock", e.StoreName, len(products))
+	if len(products) == 1 {
+		subject = fmt.Sprintf("%s: %s is low on stock", e.StoreName, products[0].Name)
+	}
+
+	var body strings.Builder
+	body.WriteString("These products have fallen to their reorder threshold:\r\n\r\n")
+	for _, product := range products {
+		fmt.Fprintf(&body, "- %s (%s): %d left, threshold %d\r\n", product.Name, product.Slug, product.Stock, product.Threshold)
+	}
+
+	message := strings.Join([]string{
+		"From: " + e.From,
+		"To: " + strings.Join(e.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body.String(),
+	}, "\r\n")
+
+	to := make([]string, len(e.To))
+	for i, recipient := range e.To {
+		to[i] = envelopeAddress(recipient)
+	}
+
+	if err := sendMail(ctx, e.Addr, e.Auth, envelopeAddress(e.From), to, []byte(message)); err != nil {
+		return fmt.Errorf("low stock email: %w", err)
+	}
+
+	return nil
+}
+
+// envelopeAddress returns the bare address of "Name <address>", which SMTP needs.
+func envelopeAddress(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		return parsed.Address
+	}
+
+	return address
+}
+
+// FromConfig builds the notifier for the configured channels. Alerts are always logged, and also
+// emailed to LOW_STOCK_EMAIL and posted to LOW_STOCK_WEBHOOK_URL when those are set.
+func FromConfig(cfg *config.Config) Notifier {
+	notifiers := Multi{Log{}}
+
+	if cfg.LOW_STOCK_WEBHOOK_URL != "" {
+		notifiers = append(notifiers, Webhook{URL: cfg.LOW_STOCK_WEBHOOK_URL})
+	}
+
+	if recipients := cfg.LowStockRecipients(); len(recipients) > 0 {
+		email := Email{Addr: cfg.SMTP_ADDR, From: cfg.SMTP_FROM, To: recipients, StoreName: cfg.STORE_NAME}
+		if cfg.SMTP_USERNAME != "" {
+			host, _, _ := net.SplitHostPort(cfg.SMTP_ADDR)
+			email.Auth = smtp.PlainAuth("", cfg.SMTP_USERNAME, cfg.SMTP_PASSWORD, host)
+		}
+		notifiers = append(notifiers, email)
+	}
+
+	return notifiers
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var products = []LowStock{{ProductID: 1, Name: "Tactile switch", Slug: "tactile-switch", Stock: 3, Threshold: 5}}
+
+func TestWebhook(t *testing.T) {
+	var received struct {
+		Event    string     `json:"event"`
+		Products []LowStock `json:"products"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, Webhook{URL: server.URL}.LowStock(context.Background(), products))
+	assert.Equal(t, "low_stock", received.Event)
+	assert.Equal(t, products, received.Products)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	assert.ErrorContains(t, Webhook{URL: failing.URL}.LowStock(context.Background(), products), "502")
+}
+
+func TestEmail(t *testing.T) {
+	var from string
+	var to []string
+	var message string
+	sendMail = func(ctx context.Context, addr string, a smtp.Auth, sender string, recipients []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		from, to, message = sender, recipients, string(msg)
+		return nil
+	}
+	defer func() { sendMail = sendMailContext }()
+
+	email := Email{Addr: "smtp.example.com:587", From: "Keylab <shop@example.com>", To: []string{"Stock Room <stock@example.com>"}, StoreName: "Keylab"}
+	assert.NoError(t, email.LowStock(context.Background(), products))
+
+	assert.Equal(t, "shop@example.com", from)
+	assert.Equal(t, []string{"stock@example.com"}, to)
+	assert.Contains(t, message, "Subject: Keylab: Tactile switch is low on stock\r\n")
+	assert.Contains(t, message, "- Tactile switch (tactile-switch): 3 left, threshold 5\r\n")
+}
+
+func TestEmailGivesUpOnSilentServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	// Accepts the connection and never greets
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err = sendMailContext(ctx, listener.Addr().String(), nil, "shop@example.com", []string{"stock@example.com"}, []byte("Subject: test\r\n\r\n"))
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 2*time.Second)
+}
+
+type failing struct{}
+
+func (failing) LowStock(ctx context.Context, products []LowStock) error {
+	return errors.New("unreachable")
+}
+
+func TestMulti(t *testing.T) {
+	delivered := 0
+	counting := notifierFunc(func() { delivered++ })
+
+	err := Multi{failing{}, counting, counting}.LowStock(context.Background(), products)
+	assert.ErrorContains(t, err, "unreachable")
+	assert.Equal(t, 2, delivered, "one failing notifier does not stop the others")
+}
+
+type notifierFunc func()
+
+func (f notifierFunc) LowStock(ctx context.Context, products []LowStock) error {
+	f()
+	return nil
+}
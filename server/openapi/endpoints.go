@@ -0,0 +1,543 @@
+package openapi
+
+import (
+	"keylab/analytics"
+	"keylab/database/models"
+	"keylab/handlers"
+	"keylab/money"
+	"keylab/productcsv"
+	"keylab/ratings"
+	"keylab/repositories"
+	"keylab/shipping"
+	"keylab/tax"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// endpoint documents one route. Every route registered in routes.RegisterRoutes needs an entry
+// here, which routes_test.go enforces.
+type endpoint struct {
+	method     string
+	path       string // Echo syntax, e.g. /products/:slug
+	tag        string
+	summary    string
+	auth       bool
+	permission string
+	query      []*Parameter
+	body       *Schema // JSON request body
+	form       *Schema // multipart/form-data request body, offered alongside body when both are set
+	status     int     // success status, 200 when zero
+	data       *Schema // the data field of the success envelope, an empty array when nil
+	raw        *Schema // a response that is not wrapped in the envelope
+	rawType    string
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func (e endpoint) operation() *Operation {
+	operation := &Operation{
+		OperationID: operationID(e.method, e.path),
+		Summary:     e.summary,
+		Tags:        []string{e.tag},
+		Permission:  e.permission,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(e.path, -1) {
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name: match[1], In: "path", Required: true, Schema: pathParamSchema(match[1]),
+		})
+	}
+	operation.Parameters = append(operation.Parameters, e.query...)
+
+	if e.body != nil || e.form != nil {
+		content := map[string]MediaType{}
+		if e.body != nil {
+			content["application/json"] = MediaType{Schema: e.body}
+		}
+		if e.form != nil {
+			content["multipart/form-data"] = MediaType{Schema: e.form}
+		}
+		operation.RequestBody = &RequestBody{Required: true, Content: content}
+	}
+
+	status := e.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if e.raw != nil {
+		success.Content = map[string]MediaType{e.rawType: {Schema: e.raw}}
+	} else {
+		success.Content = map[string]MediaType{"application/json": {Schema: envelope(e.data)}}
+	}
+	operation.Responses[strconv.Itoa(status)] = success
+
+	errorStatuses := []int{http.StatusBadRequest, http.StatusInternalServerError}
+	if e.method == http.MethodGet && strings.Contains(e.path, ":") {
+		errorStatuses = append(errorStatuses, http.StatusNotFound)
+	}
+	if e.auth {
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized)
+		operation.Security = []map[string][]string{{"session": {}}}
+		if e.method != http.MethodGet {
+			operation.Security = []map[string][]string{{"session": {}, "csrf": {}}}
+		}
+	}
+	if e.permission != "" || (e.auth && e.method != http.MethodGet) {
+		errorStatuses = append(errorStatuses, http.StatusForbidden)
+	}
+	for _, status := range errorStatuses {
+		operation.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {Schema: errorRef}},
+		}
+	}
+
+	return operation
+}
+
+// operationID turns GET /products/:slug into getProductsBySlug.
+func operationID(method string, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, ":") {
+			b.WriteString("By")
+			segment = segment[1:]
+		}
+		for _, word := range nonAlphanumeric.Split(segment, -1) {
+			if word == "" {
+				continue
+			}
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			b.WriteString(string(runes))
+		}
+	}
+
+	return b.String()
+}
+
+func pathParamSchema(name string) *Schema {
+	if name == "id" || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "_id") {
+		return &Schema{Type: "integer", Format: "int64"}
+	}
+
+	return &Schema{Type: "string"}
+}
+
+// envelope wraps data in the {"message", "data"} shape every handler responds with.
+func envelope(data *Schema) *Schema {
+	if data == nil {
+		data = &Schema{Type: "array", Items: &Schema{}, Description: "Empty"}
+	}
+
+	return object(map[string]*Schema{
+		"message": {Type: "string"},
+		"data":    data,
+	}, "message", "data")
+}
+
+var errorRef = &Schema{Ref: "#/components/schemas/Error"}
+
+// errorSchema describes the apperr envelope every failed request is answered with.
+func errorSchema() *Schema {
+	field := object(map[string]*Schema{
+		"field":   {Type: "string", Description: "JSON name of the invalid field"},
+		"tag":     {Type: "string", Description: "Validation rule that failed, e.g. required or max"},
+		"param":   {Type: "string", Description: "Parameter of the rule, e.g. the maximum length"},
+		"message": {Type: "string"},
+	}, "field", "tag", "message")
+
+	return object(map[string]*Schema{
+		"message": {Type: "string"},
+		"error": object(map[string]*Schema{
+			"code":       {Type: "string", Description: "Stable machine readable code, e.g. not_found or validation_failed"},
+			"status":     integerSchema,
+			"fields":     arrayOf(field),
+			"request_id": {Type: "string", Description: "Also sent in the X-Request-ID header"},
+		}, "code", "status"),
+	}, "message", "error")
+}
+
+func queryParam(name string, schema *Schema, description string) *Parameter {
+	return &Parameter{Name: name, In: "query", Schema: schema, Description: description}
+}
+
+var (
+	stringSchema  = &Schema{Type: "string"}
+	integerSchema = &Schema{Type: "integer"}
+
+	paginationParams = []*Parameter{
+		queryParam("page", integerSchema, "Page number, starting at 1"),
+		queryParam("per_page", integerSchema, "Items per page, 50 by default"),
+	}
+	currencyParams = []*Parameter{
+		queryParam("currency", stringSchema, "Currency to show prices in, also read from the X-Currency header. The base currency by default"),
+	}
+	verifiedParam = queryParam("verified", &Schema{Type: "boolean"}, "Only reviews that are, or are not, verified purchases")
+	sortParams    = []*Parameter{
+		queryParam("sort", stringSchema, "Field to sort by, created_at by default"),
+		queryParam("direction", &Schema{Type: "string", Enum: []interface{}{"asc", "desc"}}, "Sort direction, desc by default"),
+	}
+	productSortParams = []*Parameter{
+		queryParam("sort", stringSchema, "Field to sort by, created_at by default, or rating for the average rating and then the number of reviews"),
+		sortParams[1],
+	}
+)
+
+func paginated(params ...[]*Parameter) []*Parameter {
+	all := append([]*Parameter{}, paginationParams...)
+	for _, p := range params {
+		all = append(all, p...)
+	}
+
+	return all
+}
+
+func endpoints(g *generator) []endpoint {
+	g.enum(models.OrderStatus(""), "pending", "shipped", "delivered", "cancelled", "returned")
+	g.enum(models.AddressType(""), "billing", "shipping")
+	currencies := []interface{}{}
+	for _, currency := range money.Currencies() {
+		currencies = append(currencies, currency)
+	}
+	g.enum(money.Currency(""), currencies...)
+	g.enum(tax.Mode(""), tax.Inclusive, tax.Exclusive)
+	g.enum(shipping.MethodType(""), shipping.Standard, shipping.Express, shipping.Pickup)
+	g.enum(shipping.RateType(""), shipping.Flat, shipping.Weight, shipping.FreeOver)
+	g.enum(models.ImportStatus(""), models.ImportPending, models.ImportRunning, models.ImportCompleted, models.ImportFailed)
+	g.enum(analytics.Interval(""), analytics.Day, analytics.Week, analytics.Month)
+	g.enum(models.ReviewStatus(""), models.ReviewPending, models.ReviewApproved, models.ReviewRejected, models.ReviewHidden)
+	g.enum(models.ReportReason(""), models.ReportSpam, models.ReportAbuse, models.ReportOffTopic, models.ReportOther)
+	g.enum(models.QuestionStatus(""), models.QuestionPublished, models.QuestionHidden)
+	g.enum(ratings.Sort(""), ratings.MostHelpful, ratings.Newest, ratings.Highest, ratings.Lowest)
+
+	pagination := object(map[string]*Schema{
+		"page":     integerSchema,
+		"per_page": integerSchema,
+		"total":    integerSchema,
+	}, "page", "per_page", "total")
+	g.components["Pagination"] = pagination
+	g.components["Error"] = errorSchema()
+	paginationRef := &Schema{Ref: "#/components/schemas/Pagination"}
+
+	product := g.of(models.Product{})
+	productList := object(map[string]*Schema{
+		"products": arrayOf(object(map[string]*Schema{"data": product}, "data")),
+		"metadata": paginationRef,
+	}, "products", "metadata")
+	uploadedImage := object(map[string]*Schema{
+		"filename": stringSchema,
+		"url":      stringSchema,
+	})
+	imageForm := object(map[string]*Schema{
+		"images": arrayOf(&Schema{Type: "string", Format: "binary"}),
+	}, "images")
+	productFields := g.components["Product"].Properties
+	productForm := object(map[string]*Schema{
+		"name":                productFields["name"],
+		"description":         productFields["description"],
+		"price":               productFields["price"],
+		"stock":               productFields["stock"],
+		"weight":              productFields["weight"],
+		"category_id":         productFields["category_id"],
+		"low_stock_threshold": productFields["low_stock_threshold"],
+		"images":              imageForm.Properties["images"],
+	}, "name", "description", "price", "stock", "category_id")
+
+	category := g.of(models.ProductCategory{})
+	productTranslation := g.of(models.ProductTranslation{})
+	categoryTranslation := g.of(models.ProductCategoryTranslation{})
+	translation := object(map[string]*Schema{
+		"name":        stringSchema,
+		"description": stringSchema,
+	}, "name", "description")
+	exchangeRate := g.of(models.ExchangeRate{})
+	productPrice := g.of(models.ProductPrice{})
+	taxClass := g.of(models.TaxClass{})
+	taxRate := g.of(models.TaxRate{})
+	shippingZone := g.of(models.ShippingZone{})
+	shippingMethod := g.of(models.ShippingMethod{})
+	taxClassBody := object(map[string]*Schema{"name": stringSchema, "slug": stringSchema}, "name")
+	review := g.of(models.ProductReviews{})
+	reviewReport := g.of(models.ReviewReport{})
+	reviewFields := g.components["ProductReviews"].Properties
+	reportFields := g.components["ReviewReport"].Properties
+	reviewForm := object(map[string]*Schema{
+		"rating":  reviewFields["rating"],
+		"title":   reviewFields["title"],
+		"comment": reviewFields["comment"],
+		"photos":  imageForm.Properties["images"],
+	}, "rating", "comment")
+	reviewList := object(map[string]*Schema{"reviews": arrayOf(review), "metadata": paginationRef}, "reviews", "metadata")
+	reviewListParams := paginated([]*Parameter{
+		queryParam("sort", g.of(ratings.Sort("")), "Order of the reviews, the most helpful first by default"),
+		verifiedParam,
+	})
+	question := g.of(models.ProductQuestion{})
+	answer := g.of(models.ProductAnswer{})
+	questionBody := object(map[string]*Schema{"body": g.components["ProductQuestion"].Properties["body"]}, "body")
+	answerBody := object(map[string]*Schema{"body": g.components["ProductAnswer"].Properties["body"]}, "body")
+	questionList := object(map[string]*Schema{"questions": arrayOf(question), "metadata": paginationRef}, "questions", "metadata")
+	questionStatus := object(map[string]*Schema{"status": g.of(models.QuestionStatus(""))}, "status")
+	user := g.of(models.User{})
+	role := g.of(models.Role{})
+	permission := g.of(models.Permission{})
+	order := g.of(models.Order{})
+	orderedItem := g.of(models.OrderedItem{})
+	cartItem := g.of(models.CartItems{})
+	contact := g.of(models.ContactUsRequest{})
+	orderWithItems := object(map[string]*Schema{
+		"order":         order,
+		"ordered_items": arrayOf(orderedItem),
+	}, "order", "ordered_items")
+
+	pdf := &Schema{Type: "string", Format: "binary"}
+	productImport := g.of(models.ProductImport{})
+	importForm := object(map[string]*Schema{
+		"file":    {Type: "string", Format: "binary", Description: "CSV file with the columns " + strings.Join(productcsv.Columns, ", ")},
+		"dry_run": {Type: "boolean", Description: "Only check the file, without saving anything"},
+	}, "file")
+
+	dateRangeParams := []*Parameter{
+		queryParam("from", stringSchema, "Start date (2006-01-02) or RFC 3339 timestamp, 30 days before to by default"),
+		queryParam("to", stringSchema, "End date, inclusive, or RFC 3339 timestamp, now by default"),
+	}
+	topParams := append([]*Parameter{
+		queryParam("rank", &Schema{Type: "string", Enum: []interface{}{analytics.ByRevenue, analytics.ByUnits}}, "What to rank by, revenue by default"),
+		queryParam("limit", integerSchema, "How many to return, 10 by default, at most 100"),
+	}, dateRangeParams...)
+	top := g.of(analytics.Top{})
+
+	credentials := object(map[string]*Schema{
+		"email":    {Type: "string", Format: "email"},
+		"password": stringSchema,
+	}, "email", "password")
+	status := object(map[string]*Schema{"status": g.of(models.OrderStatus(""))}, "status")
+
+	return []endpoint{
+		// Operations
+		{method: http.MethodGet, path: "/healthz", tag: "Operations", summary: "Liveness probe"},
+		{method: http.MethodGet, path: "/readyz", tag: "Operations", summary: "Readiness probe, checks the database and migrations"},
+		{method: http.MethodGet, path: "/openapi.json", tag: "Operations", summary: "This OpenAPI document", raw: &Schema{Type: "object"}, rawType: "application/json"},
+		{method: http.MethodGet, path: "/docs", tag: "Operations", summary: "API documentation page", raw: stringSchema, rawType: "text/html"},
+
+		// Auth
+		{method: http.MethodGet, path: "/auth/csrf", tag: "Auth", summary: "Get the CSRF token for the session",
+			data: object(map[string]*Schema{"csrf_token": stringSchema}, "csrf_token")},
+		{method: http.MethodPost, path: "/auth/register", tag: "Auth", summary: "Register and log in", body: user},
+		{method: http.MethodPost, path: "/auth/login", tag: "Auth", summary: "Log in", body: credentials},
+		{method: http.MethodPost, path: "/auth/logout", tag: "Auth", summary: "Log out"},
+		{method: http.MethodGet, path: "/auth/validate", tag: "Auth", summary: "Get the logged in user", data: user},
+		{method: http.MethodGet, path: "/test/permission", tag: "Auth", summary: "Check the admin:dashboard permission", auth: true, permission: "admin:dashboard"},
+
+		// Categories
+		{method: http.MethodGet, path: "/categories", tag: "Categories", summary: "List categories", data: arrayOf(category)},
+		{method: http.MethodGet, path: "/categories/:slug", tag: "Categories", summary: "Get a category", data: category},
+		{method: http.MethodPost, path: "/categories", tag: "Categories", summary: "Create a category", auth: true, permission: "categories:create", body: category, status: http.StatusCreated, data: category},
+		{method: http.MethodDelete, path: "/categories/:slug", tag: "Categories", summary: "Delete a category", auth: true, permission: "categories:delete", data: category},
+		{method: http.MethodPut, path: "/categories/:slug", tag: "Categories", summary: "Update a category", auth: true, permission: "categories:update", body: category, data: category},
+		{method: http.MethodGet, path: "/categories/:slug/translations", tag: "Categories", summary: "List the translations of a category", auth: true, permission: "categories:update", data: arrayOf(categoryTranslation)},
+		{method: http.MethodPut, path: "/categories/:slug/translations/:locale", tag: "Categories", summary: "Create or replace a category translation", auth: true, permission: "categories:update", body: translation, data: categoryTranslation},
+		{method: http.MethodDelete, path: "/categories/:slug/translations/:locale", tag: "Categories", summary: "Delete a category translation", auth: true, permission: "categories:update", data: categoryTranslation},
+
+		// Products
+		{method: http.MethodGet, path: "/products", tag: "Products", summary: "List products", query: paginated(productSortParams, currencyParams), data: productList},
+		{method: http.MethodGet, path: "/products/:slug", tag: "Products", summary: "Get a product", query: currencyParams, data: product},
+		{method: http.MethodGet, path: "/products/:slug/recommendations", tag: "Products", summary: "Products in stock often bought with a product, or else from its category",
+			query: append([]*Parameter{queryParam("limit", integerSchema, "How many to return, 8 by default, at most 24")}, currencyParams...), data: arrayOf(product)},
+		{method: http.MethodGet, path: "/products/category/:id", tag: "Products", summary: "List products in a category", query: paginated(productSortParams, currencyParams), data: productList},
+		{method: http.MethodGet, path: "/products/search/:query", tag: "Products", summary: "Search products by name", query: paginated(productSortParams, currencyParams), data: productList},
+		{method: http.MethodGet, path: "/products/image/:path", tag: "Products", summary: "Get a product image file", raw: &Schema{Type: "string", Format: "binary"}, rawType: "image/*"},
+		{method: http.MethodPost, path: "/products", tag: "Products", summary: "Create a product with images", auth: true, permission: "products:create", form: productForm, status: http.StatusCreated,
+			data: object(map[string]*Schema{"product": product, "product_images": arrayOf(uploadedImage)}, "product")},
+		{method: http.MethodDelete, path: "/products/:id", tag: "Products", summary: "Delete a product", auth: true, permission: "products:delete", data: product},
+		{method: http.MethodPut, path: "/products/:id", tag: "Products", summary: "Update a product", auth: true, permission: "products:update", body: product, data: product},
+		{method: http.MethodPost, path: "/products/:slug/image", tag: "Products", summary: "Upload product images", auth: true, permission: "products:upload_image", form: imageForm,
+			data: object(map[string]*Schema{"product": product, "images": arrayOf(uploadedImage)}, "product", "images")},
+		{method: http.MethodDelete, path: "/products/:slug/image/:id", tag: "Products", summary: "Delete a product image", auth: true, permission: "products:delete_image"},
+		{method: http.MethodGet, path: "/products/:id/translations", tag: "Products", summary: "List the translations of a product", auth: true, permission: "products:update", data: arrayOf(productTranslation)},
+		{method: http.MethodPut, path: "/products/:id/translations/:locale", tag: "Products", summary: "Create or replace a product translation", auth: true, permission: "products:update", body: translation, data: productTranslation},
+		{method: http.MethodDelete, path: "/products/:id/translations/:locale", tag: "Products", summary: "Delete a product translation", auth: true, permission: "products:update", data: productTranslation},
+		{method: http.MethodGet, path: "/products/:id/prices", tag: "Products", summary: "List the prices of a product in other currencies", auth: true, permission: "products:update", data: arrayOf(productPrice)},
+		{method: http.MethodPut, path: "/products/:id/prices/:currency", tag: "Products", summary: "Create or replace the price of a product in a currency", auth: true, permission: "products:update",
+			body: object(map[string]*Schema{"price": g.of(money.Money{})}, "price"), data: productPrice},
+		{method: http.MethodDelete, path: "/products/:id/prices/:currency", tag: "Products", summary: "Delete the price of a product in a currency", auth: true, permission: "products:update", data: productPrice},
+
+		// Currencies
+		{method: http.MethodGet, path: "/currencies", tag: "Currencies", summary: "List the currencies prices can be shown in",
+			data: object(map[string]*Schema{"base": g.of(money.Currency("")), "currencies": arrayOf(g.of(money.Currency("")))}, "base", "currencies")},
+
+		// Reviews
+		{method: http.MethodGet, path: "/products/:product_slug/reviews", tag: "Reviews", summary: "List reviews of a product", query: reviewListParams, data: reviewList},
+		{method: http.MethodGet, path: "/products/:product_slug/reviews/user/:user_id", tag: "Reviews", summary: "Get a user's review of a product", query: reviewListParams, data: reviewList},
+		{method: http.MethodGet, path: "/products/:product_slug/reviews/:id", tag: "Reviews", summary: "Get a review", data: review},
+		{method: http.MethodGet, path: "/products/:product_slug/reviews/statistics", tag: "Reviews", summary: "Get review statistics of a product",
+			data: g.of(ratings.Summary{})},
+		{method: http.MethodGet, path: "/users/:user_id/reviews", tag: "Reviews", summary: "List reviews by a user", query: reviewListParams, data: reviewList},
+		{method: http.MethodPost, path: "/products/:product_slug/reviews", tag: "Reviews", summary: "Review a product, with up to 5 photos", auth: true, body: review, form: reviewForm, data: review},
+		{method: http.MethodPut, path: "/products/:product_slug/reviews/:id", tag: "Reviews", summary: "Update your review, adding photos", auth: true, body: review, form: reviewForm, data: review},
+		{method: http.MethodDelete, path: "/products/:product_slug/reviews/:id", tag: "Reviews", summary: "Delete your review", auth: true, data: review},
+		{method: http.MethodGet, path: "/products/:product_slug/reviews/user", tag: "Reviews", summary: "Get your review of a product", auth: true, data: review},
+		{method: http.MethodPost, path: "/products/:product_slug/reviews/:id/report", tag: "Reviews", summary: "Report a review to moderators", auth: true, status: http.StatusCreated,
+			body: object(map[string]*Schema{"reason": reportFields["reason"], "comment": reportFields["comment"]}, "reason"), data: reviewReport},
+		{method: http.MethodPut, path: "/products/:product_slug/reviews/:id/vote", tag: "Reviews", summary: "Vote on whether a review was helpful", auth: true,
+			body: object(map[string]*Schema{"helpful": {Type: "boolean"}}, "helpful"), data: review},
+		{method: http.MethodDelete, path: "/products/:product_slug/reviews/:id/vote", tag: "Reviews", summary: "Withdraw your vote on a review", auth: true, data: review},
+		{method: http.MethodDelete, path: "/products/:product_slug/reviews/:id/photos/:photo_id", tag: "Reviews", summary: "Delete a photo from your review", auth: true, data: review},
+		{method: http.MethodGet, path: "/reviews/photos/:filename", tag: "Reviews", summary: "Get a review photo file", raw: &Schema{Type: "string", Format: "binary"}, rawType: "image/*"},
+		{method: http.MethodGet, path: "/reviews/recent", tag: "Reviews", summary: "List recent reviews",
+			query: []*Parameter{queryParam("limit", integerSchema, "Number of reviews"), verifiedParam}, data: arrayOf(review)},
+
+		// Questions
+		{method: http.MethodGet, path: "/products/:product_slug/questions", tag: "Questions", summary: "List questions about a product with their answers", query: paginated(), data: questionList},
+		{method: http.MethodGet, path: "/products/:product_slug/questions/:id", tag: "Questions", summary: "Get a question with its answers", data: question},
+		{method: http.MethodPost, path: "/products/:product_slug/questions", tag: "Questions", summary: "Ask a question about a product", auth: true, status: http.StatusCreated,
+			body: questionBody, data: question},
+		{method: http.MethodPost, path: "/products/:product_slug/questions/:id/answers", tag: "Questions", summary: "Answer a question, as staff or a customer who received the product", auth: true, status: http.StatusCreated,
+			body: answerBody, data: answer},
+		{method: http.MethodPut, path: "/products/:product_slug/questions/:id/answers/:answer_id/vote", tag: "Questions", summary: "Upvote an answer", auth: true, data: answer},
+		{method: http.MethodDelete, path: "/products/:product_slug/questions/:id/answers/:answer_id/vote", tag: "Questions", summary: "Withdraw your upvote of an answer", auth: true, data: answer},
+
+		// Cart
+		{method: http.MethodGet, path: "/cart", tag: "Cart", summary: "List cart items", auth: true, query: currencyParams, data: arrayOf(cartItem)},
+		{method: http.MethodPost, path: "/cart", tag: "Cart", summary: "Add a product to the cart", auth: true, body: cartItem, status: http.StatusCreated, data: cartItem},
+		{method: http.MethodPut, path: "/cart/:id", tag: "Cart", summary: "Change the quantity of a cart item", auth: true, body: cartItem, data: cartItem},
+		{method: http.MethodDelete, path: "/cart/:id", tag: "Cart", summary: "Remove a cart item", auth: true, data: cartItem},
+		{method: http.MethodGet, path: "/cart/shipping-options", tag: "Cart", summary: "Quote the shipping methods for the cart and an address", auth: true,
+			query: append([]*Parameter{queryParam("address_id", integerSchema, "One of your addresses")}, currencyParams...),
+			data:  object(map[string]*Schema{"currency": g.of(money.Currency("")), "options": arrayOf(g.of(shipping.Option{}))}, "currency", "options")},
+		{method: http.MethodPost, path: "/cart/checkout", tag: "Cart", summary: "Place an order for the cart in the chosen currency", auth: true, query: currencyParams, body: g.of(handlers.CheckoutRequest{})},
+
+		// Users
+		{method: http.MethodGet, path: "/users/:id", tag: "Users", summary: "Get your profile", auth: true, data: user},
+		{method: http.MethodPut, path: "/users/:id", tag: "Users", summary: "Update your profile", auth: true, body: user, data: user},
+		{method: http.MethodPost, path: "/users/:id/change-password", tag: "Users", summary: "Change your password", auth: true,
+			body: object(map[string]*Schema{
+				"current_password":      stringSchema,
+				"new_password":          stringSchema,
+				"password_confirmation": stringSchema,
+			}, "current_password", "new_password", "password_confirmation")},
+		{method: http.MethodGet, path: "/users/:id/orders", tag: "Users", summary: "List your orders", auth: true, data: arrayOf(order)},
+		{method: http.MethodGet, path: "/user/orders/:id", tag: "Users", summary: "Get one of your orders", auth: true, data: orderWithItems},
+		{method: http.MethodGet, path: "/user/orders/:id/invoice", tag: "Users", summary: "Download the invoice of one of your orders", auth: true, raw: pdf, rawType: "application/pdf"},
+
+		// Contact
+		{method: http.MethodPost, path: "/contact", tag: "Contact", summary: "Send a contact request", body: contact, data: contact},
+
+		// Admin
+		{method: http.MethodGet, path: "/admin/users", tag: "Admin", summary: "List users", auth: true, permission: "admin:dashboard", query: paginated(),
+			data: object(map[string]*Schema{"users": arrayOf(user), "metadata": paginationRef}, "users", "metadata")},
+		{method: http.MethodPut, path: "/admin/users/:id", tag: "Admin", summary: "Update a user", auth: true, permission: "admin:dashboard", body: user, data: user},
+		{method: http.MethodDelete, path: "/admin/users/:id", tag: "Admin", summary: "Delete a user", auth: true, permission: "admin:dashboard"},
+		{method: http.MethodGet, path: "/admin/orders", tag: "Admin", summary: "List orders", auth: true, permission: "admin:dashboard",
+			query: paginated(sortParams, []*Parameter{queryParam("status", g.of(models.OrderStatus("")), "Only orders with this status")}),
+			data:  object(map[string]*Schema{"orders": arrayOf(orderWithItems), "metadata": paginationRef}, "orders", "metadata")},
+		{method: http.MethodGet, path: "/admin/orders/:id", tag: "Admin", summary: "Get an order", auth: true, permission: "admin:dashboard", data: orderWithItems},
+		{method: http.MethodGet, path: "/admin/orders/user/:id", tag: "Admin", summary: "List a user's orders", auth: true, permission: "admin:dashboard",
+			query: paginated(sortParams, []*Parameter{queryParam("status", g.of(models.OrderStatus("")), "Only orders with this status")}),
+			data:  object(map[string]*Schema{"user": user, "orders": arrayOf(orderWithItems), "metadata": paginationRef}, "user", "orders", "metadata")},
+		{method: http.MethodPut, path: "/admin/orders/:id/status", tag: "Admin", summary: "Change the status of an order", auth: true, permission: "admin:dashboard", body: status, data: order},
+		{method: http.MethodGet, path: "/admin/orders/:id/invoice", tag: "Admin", summary: "Download the invoice of an order", auth: true, permission: "admin:dashboard", raw: pdf, rawType: "application/pdf"},
+		{method: http.MethodGet, path: "/admin/orders/:id/packing-slip", tag: "Admin", summary: "Download the packing slip of an order", auth: true, permission: "admin:dashboard", raw: pdf, rawType: "application/pdf"},
+		{method: http.MethodGet, path: "/admin/roles", tag: "Admin", summary: "List roles", auth: true, permission: "admin:dashboard",
+			query: []*Parameter{queryParam("include_permissions", &Schema{Type: "boolean"}, "Include each role's permissions")}, data: arrayOf(role)},
+		{method: http.MethodGet, path: "/admin/roles/:id", tag: "Admin", summary: "Get a role with its permissions", auth: true, permission: "admin:dashboard",
+			data: object(map[string]*Schema{"role": role, "permissions": arrayOf(permission)}, "role", "permissions")},
+		{method: http.MethodPost, path: "/admin/roles", tag: "Admin", summary: "Create a role", auth: true, permission: "admin:dashboard", body: role, status: http.StatusCreated, data: role},
+		{method: http.MethodPut, path: "/admin/roles/:id", tag: "Admin", summary: "Update a role", auth: true, permission: "admin:dashboard", body: role, data: role},
+		{method: http.MethodDelete, path: "/admin/roles/:id", tag: "Admin", summary: "Delete a role", auth: true, permission: "admin:dashboard"},
+		{method: http.MethodPost, path: "/admin/roles/:id/permissions", tag: "Admin", summary: "Add permissions to a role", auth: true, permission: "admin:dashboard",
+			body: object(map[string]*Schema{"permission_ids": arrayOf(&Schema{Type: "integer", Format: "int64"})}, "permission_ids")},
+		{method: http.MethodDelete, path: "/admin/roles/:roleId/permissions/:permissionId", tag: "Admin", summary: "Remove a permission from a role", auth: true, permission: "admin:dashboard"},
+		{method: http.MethodGet, path: "/admin/permissions", tag: "Admin", summary: "List permissions", auth: true, permission: "admin:dashboard", data: arrayOf(permission)},
+		{method: http.MethodGet, path: "/admin/audit", tag: "Admin", summary: "Search the audit log", auth: true, permission: "admin:dashboard",
+			query: paginated([]*Parameter{
+				queryParam("actor_id", integerSchema, "Only changes made by this user"),
+				queryParam("entity_type", stringSchema, "Only changes to this kind of entity"),
+				queryParam("entity_id", integerSchema, "Only changes to this entity"),
+				queryParam("from", stringSchema, "Start date (2006-01-02) or RFC 3339 timestamp"),
+				queryParam("to", stringSchema, "End date (inclusive) or RFC 3339 timestamp"),
+			}),
+			data: object(map[string]*Schema{"audit_logs": arrayOf(g.of(models.AuditLog{})), "metadata": paginationRef}, "audit_logs", "metadata")},
+		{method: http.MethodGet, path: "/admin/exchange-rates", tag: "Admin", summary: "List exchange rates from the base currency", auth: true, permission: "admin:dashboard", data: arrayOf(exchangeRate)},
+		{method: http.MethodPut, path: "/admin/exchange-rates/:currency", tag: "Admin", summary: "Create or replace an exchange rate", auth: true, permission: "admin:dashboard",
+			body: object(map[string]*Schema{"rate": g.of(money.Rate{})}, "rate"), data: exchangeRate},
+		{method: http.MethodDelete, path: "/admin/exchange-rates/:currency", tag: "Admin", summary: "Delete an exchange rate", auth: true, permission: "admin:dashboard", data: exchangeRate},
+		{method: http.MethodGet, path: "/admin/tax/classes", tag: "Admin", summary: "List tax classes", auth: true, permission: "admin:dashboard", data: arrayOf(taxClass)},
+		{method: http.MethodPost, path: "/admin/tax/classes", tag: "Admin", summary: "Create a tax class", auth: true, permission: "admin:dashboard", body: taxClassBody, status: http.StatusCreated, data: taxClass},
+		{method: http.MethodPut, path: "/admin/tax/classes/:id", tag: "Admin", summary: "Rename a tax class", auth: true, permission: "admin:dashboard", body: taxClassBody, data: taxClass},
+		{method: http.MethodDelete, path: "/admin/tax/classes/:id", tag: "Admin", summary: "Delete a tax class with its rates", auth: true, permission: "admin:dashboard", data: taxClass},
+		{method: http.MethodGet, path: "/admin/tax/rates", tag: "Admin", summary: "List tax rates", auth: true, permission: "admin:dashboard",
+			query: []*Parameter{
+				queryParam("country", stringSchema, "Only rates of this country"),
+				queryParam("tax_class_id", integerSchema, "Only rates of this tax class"),
+			},
+			data: arrayOf(taxRate)},
+		{method: http.MethodPost, path: "/admin/tax/rates", tag: "Admin", summary: "Create a tax rate", auth: true, permission: "admin:dashboard", body: taxRate, status: http.StatusCreated, data: taxRate},
+		{method: http.MethodPut, path: "/admin/tax/rates/:id", tag: "Admin", summary: "Update a tax rate", auth: true, permission: "admin:dashboard", body: taxRate, data: taxRate},
+		{method: http.MethodDelete, path: "/admin/tax/rates/:id", tag: "Admin", summary: "Delete a tax rate", auth: true, permission: "admin:dashboard", data: taxRate},
+		{method: http.MethodGet, path: "/admin/shipping/zones", tag: "Admin", summary: "List shipping zones with their methods", auth: true, permission: "admin:dashboard", data: arrayOf(shippingZone)},
+		{method: http.MethodPost, path: "/admin/shipping/zones", tag: "Admin", summary: "Create a shipping zone", auth: true, permission: "admin:dashboard", body: shippingZone, status: http.StatusCreated, data: shippingZone},
+		{method: http.MethodPut, path: "/admin/shipping/zones/:id", tag: "Admin", summary: "Update a shipping zone", auth: true, permission: "admin:dashboard", body: shippingZone, data: shippingZone},
+		{method: http.MethodDelete, path: "/admin/shipping/zones/:id", tag: "Admin", summary: "Delete a shipping zone with its methods", auth: true, permission: "admin:dashboard", data: shippingZone},
+		{method: http.MethodPost, path: "/admin/shipping/methods", tag: "Admin", summary: "Create a shipping method", auth: true, permission: "admin:dashboard", body: shippingMethod, status: http.StatusCreated, data: shippingMethod},
+		{method: http.MethodPut, path: "/admin/shipping/methods/:id", tag: "Admin", summary: "Update a shipping method", auth: true, permission: "admin:dashboard", body: shippingMethod, data: shippingMethod},
+		{method: http.MethodDelete, path: "/admin/shipping/methods/:id", tag: "Admin", summary: "Delete a shipping method", auth: true, permission: "admin:dashboard", data: shippingMethod},
+		{method: http.MethodGet, path: "/admin/products/export.csv", tag: "Admin", summary: "Export all products as CSV", auth: true, permission: "admin:dashboard", raw: stringSchema, rawType: "text/csv"},
+		{method: http.MethodPost, path: "/admin/products/import", tag: "Admin", summary: "Import products from CSV, upserting by slug. Large files answer 202 and import in the background", auth: true, permission: "admin:dashboard", form: importForm, data: productImport},
+		{method: http.MethodGet, path: "/admin/products/import/:id", tag: "Admin", summary: "Get the status and row errors of a product import", auth: true, permission: "admin:dashboard", data: productImport},
+		{method: http.MethodGet, path: "/admin/analytics/sales", tag: "Admin", summary: "Orders and revenue per day, week or month, with the totals and average order value", auth: true, permission: "admin:dashboard",
+			query: append([]*Parameter{queryParam("interval", g.of(analytics.Interval("")), "Period length, day by default")}, dateRangeParams...),
+			data: object(map[string]*Schema{
+				"from":     {Type: "string", Format: "date-time"},
+				"to":       {Type: "string", Format: "date-time"},
+				"interval": g.of(analytics.Interval("")),
+				"totals":   g.of(analytics.Totals{}),
+				"series":   arrayOf(g.of(analytics.Point{})),
+			}, "from", "to", "interval", "totals", "series")},
+		{method: http.MethodGet, path: "/admin/analytics/products", tag: "Admin", summary: "Top selling products", auth: true, permission: "admin:dashboard", query: topParams, data: arrayOf(top)},
+		{method: http.MethodGet, path: "/admin/analytics/categories", tag: "Admin", summary: "Top selling categories", auth: true, permission: "admin:dashboard", query: topParams, data: arrayOf(top)},
+		{method: http.MethodGet, path: "/admin/analytics/customers", tag: "Admin", summary: "New and returning customers", auth: true, permission: "admin:dashboard", query: dateRangeParams, data: g.of(analytics.Customers{})},
+		{method: http.MethodGet, path: "/admin/analytics/statuses", tag: "Admin", summary: "Orders and revenue per order status", auth: true, permission: "admin:dashboard", query: dateRangeParams, data: arrayOf(g.of(analytics.StatusCount{}))},
+		{method: http.MethodGet, path: "/admin/inventory/low-stock", tag: "Admin", summary: "Products at or below their low stock threshold", auth: true, permission: "admin:dashboard", query: paginated(),
+			data: object(map[string]*Schema{"products": arrayOf(g.of(repositories.LowStockProduct{})), "metadata": paginationRef}, "products", "metadata")},
+		{method: http.MethodGet, path: "/admin/reviews", tag: "Admin", summary: "List reviews for moderation", auth: true, permission: "admin:dashboard",
+			query: paginated([]*Parameter{
+				queryParam("status", g.of(models.ReviewStatus("")), "Only reviews with this status"),
+				queryParam("product", stringSchema, "Only reviews of the product with this slug"),
+				queryParam("user_id", integerSchema, "Only reviews by this user"),
+				queryParam("reported", &Schema{Type: "boolean"}, "Only reviews with open reports, the most reported first"),
+				verifiedParam,
+			}),
+			data: reviewList},
+		{method: http.MethodGet, path: "/admin/reviews/:id", tag: "Admin", summary: "Get a review with its reports", auth: true, permission: "admin:dashboard",
+			data: object(map[string]*Schema{"review": review, "reports": arrayOf(reviewReport)}, "review", "reports")},
+		{method: http.MethodPut, path: "/admin/reviews/:id/status", tag: "Admin", summary: "Approve, reject or hide a review", auth: true, permission: "admin:dashboard",
+			body: object(map[string]*Schema{"status": g.of(models.ReviewStatus(""))}, "status"), data: review},
+		{method: http.MethodGet, path: "/admin/questions", tag: "Admin", summary: "List questions for moderation", auth: true, permission: "admin:dashboard",
+			query: paginated([]*Parameter{
+				queryParam("status", g.of(models.QuestionStatus("")), "Only questions with this status"),
+				queryParam("product", stringSchema, "Only questions about the product with this slug"),
+				queryParam("user_id", integerSchema, "Only questions by this user"),
+				queryParam("unanswered", &Schema{Type: "boolean"}, "Only questions without a published answer"),
+			}),
+			data: questionList},
+		{method: http.MethodGet, path: "/admin/questions/:id", tag: "Admin", summary: "Get a question with all of its answers", auth: true, permission: "admin:dashboard", data: question},
+		{method: http.MethodPut, path: "/admin/questions/:id/status", tag: "Admin", summary: "Publish or hide a question", auth: true, permission: "admin:dashboard", body: questionStatus, data: question},
+		{method: http.MethodDelete, path: "/admin/questions/:id", tag: "Admin", summary: "Delete a question with its answers", auth: true, permission: "admin:dashboard"},
+		{method: http.MethodPut, path: "/admin/answers/:id/status", tag: "Admin", summary: "Publish or hide an answer", auth: true, permission: "admin:dashboard", body: questionStatus, data: answer},
+		{method: http.MethodPut, path: "/admin/answers/:id/official", tag: "Admin", summary: "Mark an answer official, listing it first", auth: true, permission: "admin:dashboard",
+			body: object(map[string]*Schema{"official": {Type: "boolean"}}, "official"), data: answer},
+		{method: http.MethodDelete, path: "/admin/answers/:id", tag: "Admin", summary: "Delete an answer", auth: true, permission: "admin:dashboard"},
+	}
+}
@@ -0,0 +1,170 @@
+// Package openapi describes the HTTP API as an OpenAPI 3.1 document and serves it together with a
+// small documentation page.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"keylab/i18n"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem maps lower case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Permission  string                `json:"x-permission,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// Path converts an Echo route path such as /products/:slug to OpenAPI syntax, /products/{slug}.
+func Path(echoPath string) string {
+	return pathParamPattern.ReplaceAllString(echoPath, "{$1}")
+}
+
+// Build assembles the document from the endpoint table in endpoints.go.
+func Build() *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:   "KeyLab API",
+			Version: "1.0.0",
+			Description: "Successful responses are a JSON envelope with a message and a data field, " +
+				"failed ones carry an error object with a stable code instead. Cookie " +
+				"authenticated requests that change state must send the token from GET /auth/csrf in " +
+				"the X-CSRF-Token header. Messages, and product and category names and descriptions, " +
+				"are in the best match for the Accept-Language header out of " + strings.Join(i18n.Supported, ", ") +
+				", falling back to " + i18n.Default + ".",
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				"session": {Type: "apiKey", In: "cookie", Name: "keylab", Description: "Session cookie set by POST /auth/login"},
+				"csrf":    {Type: "apiKey", In: "header", Name: "X-CSRF-Token", Description: "Token from GET /auth/csrf"},
+			},
+		},
+	}
+
+	tags := map[string]bool{}
+	for _, endpoint := range endpoints(g) {
+		path := Path(endpoint.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(endpoint.method)] = endpoint.operation()
+
+		if !tags[endpoint.tag] {
+			tags[endpoint.tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: endpoint.tag})
+		}
+	}
+
+	doc.Components.Schemas = g.components
+	return doc
+}
+
+// Operations lists every documented "METHOD /path" pair, sorted.
+func (d *Document) Operations() []string {
+	var operations []string
+	for path, item := range d.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(operations)
+
+	return operations
+}
+
+var (
+	specJSON []byte
+	specErr  error
+	specOnce sync.Once
+)
+
+// Handler [GET /openapi.json] serves the document.
+func Handler(c echo.Context) error {
+	specOnce.Do(func() {
+		specJSON, specErr = json.Marshal(Build())
+	})
+	if specErr != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"message": "Error building API specification", "data": []string{}})
+	}
+
+	return c.JSONBlob(http.StatusOK, specJSON)
+}
+
+//go:embed docs.html
+var docsHTML []byte
+
+// DocsHandler [GET /docs] serves a self-contained page that renders /openapi.json.
+func DocsHandler(c echo.Context) error {
+	return c.HTMLBlob(http.StatusOK, docsHTML)
+}
@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"encoding/json"
+	"keylab/money"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema (draft 2020-12, as used by OpenAPI 3.1) the spec needs.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MultipleOf           *float64           `json:"multipleOf,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	moneyType      = reflect.TypeOf(money.Money{})
+	rateType       = reflect.TypeOf(money.Rate{})
+	minorUnit      = 1.0 / money.Scale
+)
+
+// generator turns Go types into schemas. Named structs become components referenced with $ref,
+// which also takes care of recursive types such as Product and ProductCategory.
+type generator struct {
+	components map[string]*Schema
+	enums      map[reflect.Type][]interface{}
+}
+
+func newGenerator() *generator {
+	return &generator{components: map[string]*Schema{}, enums: map[reflect.Type][]interface{}{}}
+}
+
+// enum registers the allowed values of a string type such as models.OrderStatus.
+func (g *generator) enum(value interface{}, values ...interface{}) {
+	g.enums[reflect.TypeOf(value)] = values
+}
+
+// of returns the schema for the type of value.
+func (g *generator) of(value interface{}) *Schema {
+	return g.schema(reflect.TypeOf(value))
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if values, ok := g.enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{Description: "Any JSON value"}
+	case t == moneyType:
+		return &Schema{Type: "number", MultipleOf: &minorUnit, Description: "Exact amount with two decimal places"}
+	case t == rateType:
+		return &Schema{Type: "number", Description: "Exact rate with up to eight decimal places"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schema(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return g.component(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) component(t reflect.Type) *Schema {
+	ref := &Schema{Ref: "#/components/schemas/" + t.Name()}
+	if _, ok := g.components[t.Name()]; ok {
+		return ref
+	}
+
+	// Reserve the name first so recursive fields resolve to the reference
+	g.components[t.Name()] = &Schema{}
+	*g.components[t.Name()] = *g.object(t)
+
+	return ref
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	object := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schema(field.Type)
+		if applyValidation(property, field.Tag.Get("validate"), boundScale(field.Type)) {
+			object.Required = append(object.Required, name)
+		}
+		object.Properties[name] = property
+	}
+
+	return object
+}
+
+// applyValidation copies the validator rules that have a JSON Schema equivalent onto the schema
+// and reports whether the field is required. Numeric bounds are divided by scale.
+func applyValidation(schema *Schema, tag string, scale float64) bool {
+	required := false
+	if tag == "" || tag == "-" || schema.Ref != "" {
+		return strings.HasPrefix(tag, "required")
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		number, err := strconv.ParseFloat(param, 64)
+		hasNumber := err == nil
+		number /= scale
+
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "e164":
+			schema.Pattern = `^\+[1-9]\d{1,14}$`
+		case "min", "gte":
+			if hasNumber {
+				setBound(schema, number, true)
+			}
+		case "max", "lte":
+			if hasNumber {
+				setBound(schema, number, false)
+			}
+		}
+	}
+
+	return required
+}
+
+// boundScale is the factor the validator's numeric bounds of t are in: money is validated in minor
+// units and rates in hundred-millionths.
+func boundScale(t reflect.Type) float64 {
+	switch t {
+	case moneyType:
+		return 100
+	case rateType:
+		return 1e8
+	}
+
+	return 1
+}
+
+func setBound(schema *Schema, bound float64, lower bool) {
+	if schema.Type == "string" {
+		length := int(bound)
+		if lower {
+			schema.MinLength = &length
+		} else {
+			schema.MaxLength = &length
+		}
+		return
+	}
+
+	if lower {
+		schema.Minimum = &bound
+	} else {
+		schema.Maximum = &bound
+	}
+}
+
+// nullable allows null in addition to the schema.
+func nullable(schema *Schema) *Schema {
+	if typeName, ok := schema.Type.(string); ok && schema.Ref == "" {
+		schema.Type = []string{typeName, "null"}
+		return schema
+	}
+
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
+
+// object builds an inline object schema from named properties.
+func object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+func arrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
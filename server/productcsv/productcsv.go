@@ -0,0 +1,240 @@
+// Package productcsv reads and writes the product catalog as CSV.
+//
+// A file has a header row naming its columns, in any order. Products are matched by slug, which
+// is derived from the name when left empty, and categories are named by their slug. The images
+// column holds image filenames or http(s) URLs separated by "|". Prices are in the base currency
+// and weights in grams.
+package productcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"keylab/money"
+	"strconv"
+	"strings"
+
+	"github.com/gosimple/slug"
+)
+
+// Columns are the columns of an exported file, in order.
+var Columns = []string{"slug", "name", "description", "price", "stock", "weight", "category", "images"}
+
+// required are the columns an imported file must have. Files without slug, weight or images leave
+// those to their defaults.
+var required = []string{"name", "description", "price", "stock", "category"}
+
+// ImageSeparator separates the entries of the images column.
+const ImageSeparator = "|"
+
+// Record is one product in a file. Line is the line the record started on when it was read.
+type Record struct {
+	Line        int
+	Slug        string
+	Name        string
+	Description string
+	Price       money.Money
+	Stock       int
+	Weight      int
+	Category    string
+	Images      []string
+}
+
+// RowError is a problem with one record of a file.
+type RowError struct {
+	Line    int    `json:"line"`
+	Slug    string `json:"slug,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+var ErrHeader = errors.New("invalid header")
+
+// Read parses a whole file. A file that is not CSV or has an unusable header is an error; records
+// with invalid values are left out and reported as row errors instead, so the rest of the file can
+// still be imported.
+func Read(r io.Reader) ([]Record, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("%w: the file is empty", ErrHeader)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns, err := readHeader(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader.FieldsPerRecord = len(header)
+
+	var records []Record
+	var rowErrors []RowError
+	lines := map[string]int{}
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) || !errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				return nil, nil, err
+			}
+			rowErrors = append(rowErrors, RowError{Line: parseErr.StartLine, Message: fmt.Sprintf("expected %d fields, got %d", len(header), len(fields))})
+			continue
+		}
+
+		record, err := parseRecord(columns, fields)
+		record.Line = line
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Line: line, Slug: record.Slug, Message: err.Error()})
+			continue
+		}
+
+		if first, ok := lines[record.Slug]; ok {
+			rowErrors = append(rowErrors, RowError{Line: line, Slug: record.Slug, Message: fmt.Sprintf("slug is already used on line %d", first)})
+			continue
+		}
+		lines[record.Slug] = line
+
+		records = append(records, record)
+	}
+
+	return records, rowErrors, nil
+}
+
+// readHeader maps each known column to its index.
+func readHeader(header []string) (map[string]int, error) {
+	known := map[string]bool{}
+	for _, column := range Columns {
+		known[column] = true
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrHeader, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: column %q appears twice", ErrHeader, name)
+		}
+		columns[name] = i
+	}
+
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrHeader, name)
+		}
+	}
+
+	return columns, nil
+}
+
+func parseRecord(columns map[string]int, fields []string) (Record, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	record := Record{
+		Slug:        field("slug"),
+		Name:        field("name"),
+		Description: field("description"),
+		Category:    field("category"),
+	}
+
+	if record.Slug == "" {
+		record.Slug = slug.Make(record.Name)
+	}
+	if !slug.IsSlug(record.Slug) {
+		return record, fmt.Errorf("slug %q may only contain lower case letters, digits and dashes", record.Slug)
+	}
+	if record.Category == "" {
+		return record, errors.New("category is required")
+	}
+
+	price, err := money.Parse(field("price"))
+	if err != nil {
+		return record, fmt.Errorf("price %q is not an amount", field("price"))
+	}
+	record.Price = price
+
+	if record.Stock, err = strconv.Atoi(field("stock")); err != nil {
+		return record, fmt.Errorf("stock %q is not a whole number", field("stock"))
+	}
+	if value := field("weight"); value != "" {
+		if record.Weight, err = strconv.Atoi(value); err != nil {
+			return record, fmt.Errorf("weight %q is not a whole number", value)
+		}
+	}
+
+	for _, image := range strings.Split(field("images"), ImageSeparator) {
+		if image = strings.TrimSpace(image); image != "" {
+			record.Images = append(record.Images, image)
+		}
+	}
+
+	return record, nil
+}
+
+// Writer writes records with the header of Columns.
+type Writer struct {
+	csv         *csv.Writer
+	wroteHeader bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{csv: csv.NewWriter(w)}
+}
+
+// Write writes the record, after the header when it is the first one.
+func (w *Writer) Write(record Record) error {
+	if err := w.header(); err != nil {
+		return err
+	}
+
+	return w.csv.Write([]string{
+		record.Slug,
+		record.Name,
+		record.Description,
+		record.Price.String(),
+		strconv.Itoa(record.Stock),
+		strconv.Itoa(record.Weight),
+		record.Category,
+		strings.Join(record.Images, ImageSeparator),
+	})
+}
+
+// Flush writes the header if nothing was written yet, so an empty catalog still exports its
+// columns, and flushes the buffered records.
+func (w *Writer) Flush() error {
+	if err := w.header(); err != nil {
+		return err
+	}
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *Writer) header() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+
+	return w.csv.Write(Columns)
+}
@@ -0,0 +1,77 @@
+package productcsv
+
+import (
+	"keylab/money"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	records := []Record{
+		{Slug: "tactile-switch", Name: "Tactile switch", Description: "A switch, \"tactile\"\nwith a bump", Price: money.MustParse("0.45"), Stock: 900, Weight: 2, Category: "switches", Images: []string{"1700000000.png", "https://example.com/switch.jpg"}},
+		{Slug: "keycaps", Name: "Keycaps", Description: "PBT", Price: money.MustParse("39.99"), Stock: 0, Category: "keycaps"},
+	}
+
+	var out strings.Builder
+	writer := NewWriter(&out)
+	for _, record := range records {
+		assert.NoError(t, writer.Write(record))
+	}
+	assert.NoError(t, writer.Flush())
+	assert.True(t, strings.HasPrefix(out.String(), "slug,name,description,price,stock,weight,category,images\n"))
+
+	read, rowErrors, err := Read(strings.NewReader(out.String()))
+	assert.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Len(t, read, 2)
+
+	records[0].Line = 2
+	records[1].Line = 4
+	assert.Equal(t, records, read)
+}
+
+func TestReadHeader(t *testing.T) {
+	_, _, err := Read(strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrHeader)
+
+	_, _, err = Read(strings.NewReader("name,description,price,stock,category,colour\n"))
+	assert.ErrorContains(t, err, `unknown column "colour"`)
+
+	_, _, err = Read(strings.NewReader("name,description,price,stock\n"))
+	assert.ErrorContains(t, err, `missing column "category"`)
+
+	records, _, err := Read(strings.NewReader("\ufeffCategory, Name,description,price,stock\nswitches,Linear switch,Smooth,0.40,100\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "linear-switch", records[0].Slug, "the slug defaults to the name")
+	assert.Equal(t, "switches", records[0].Category)
+}
+
+func TestReadRowErrors(t *testing.T) {
+	file := strings.Join([]string{
+		"slug,name,description,price,stock,category",
+		"a,A,Fine,1.00,1,switches",
+		"b,B,Bad price,1.999,1,switches",
+		"c,C,Bad stock,1.00,many,switches",
+		"d,D,Too few fields",
+		"Not A Slug,E,Bad slug,1.00,1,switches",
+		"a,A again,Duplicate,1.00,1,switches",
+		"f,F,No category,1.00,1,",
+		"g,G,Fine,2.00,2,keycaps",
+	}, "\n")
+
+	records, rowErrors, err := Read(strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "g"}, []string{records[0].Slug, records[1].Slug})
+	assert.Equal(t, 9, records[1].Line)
+
+	assert.Equal(t, []RowError{
+		{Line: 3, Slug: "b", Message: `price "1.999" is not an amount`},
+		{Line: 4, Slug: "c", Message: `stock "many" is not a whole number`},
+		{Line: 5, Message: "expected 6 fields, got 3"},
+		{Line: 6, Slug: "Not A Slug", Message: `slug "Not A Slug" may only contain lower case letters, digits and dashes`},
+		{Line: 7, Slug: "a", Message: "slug is already used on line 2"},
+		{Line: 8, Slug: "f", Message: "category is required"},
+	}, rowErrors)
+}
@@ -0,0 +1,84 @@
+// Package ratings orders product reviews and summarises their ratings. The sorting and counting
+// happen in SQL in the repositories, using the expressions built here.
+package ratings
+
+import (
+	"fmt"
+	"math"
+)
+
+// Sort is the order reviews are listed in.
+type Sort string
+
+const (
+	MostHelpful Sort = "helpful"
+	Newest      Sort = "newest"
+	Highest     Sort = "highest"
+	Lowest      Sort = "lowest"
+)
+
+// ParseSort returns the order named s.
+func ParseSort(s string) (Sort, bool) {
+	switch Sort(s) {
+	case MostHelpful, Newest, Highest, Lowest:
+		return Sort(s), true
+	default:
+		return "", false
+	}
+}
+
+// Stars is the highest rating a review can give; the lowest is 1.
+const Stars = 5
+
+// Summary is the number of approved reviews of a product, their average rating and how many gave
+// each rating from 1 to Stars.
+type Summary struct {
+	TotalReviews  int64         `json:"total_reviews"`
+	AverageRating float64       `json:"average_rating"`
+	Distribution  map[int]int64 `json:"distribution"`
+}
+
+// Summarise builds the summary of the number of reviews per rating. Ratings missing from counts
+// had no reviews.
+func Summarise(counts map[int]int64) Summary {
+	summary := Summary{Distribution: make(map[int]int64, Stars)}
+
+	var sum int64
+	for stars := 1; stars <= Stars; stars++ {
+		count := counts[stars]
+		summary.Distribution[stars] = count
+		summary.TotalReviews += count
+		sum += int64(stars) * count
+	}
+
+	if summary.TotalReviews > 0 {
+		summary.AverageRating = float64(sum) / float64(summary.TotalReviews)
+	}
+
+	return summary
+}
+
+// z is the standard normal quantile of the 95% confidence level helpfulness is ranked at.
+const z = 1.96
+
+// WilsonLowerBound is the lower bound of the Wilson score interval of the share of helpful votes.
+// It ranks a review that 9 of 10 voters found helpful above one that its only voter found
+// helpful, and reviews without votes last.
+func WilsonLowerBound(helpful int, notHelpful int) float64 {
+	n := float64(helpful + notHelpful)
+	if n == 0 {
+		return 0
+	}
+
+	p := float64(helpful) / n
+	return (p + z*z/(2*n) - z*math.Sqrt(p*(1-p)/n+z*z/(4*n*n))) / (1 + z*z/n)
+}
+
+// WilsonSQL is WilsonLowerBound as a MariaDB expression of the columns holding the helpful and not
+// helpful vote counts.
+func WilsonSQL(helpful string, notHelpful string) string {
+	n := fmt.Sprintf("(%s + %s)", helpful, notHelpful)
+	p := fmt.Sprintf("((%s + 0e0) / %s)", helpful, n)
+
+	return fmt.Sprintf("CASE WHEN %[1]s = 0 THEN 0 ELSE (%[2]s + %[3]g / (2 * %[1]s) - %[4]g * SQRT(%[2]s * (1 - %[2]s) / %[1]s + %[3]g / (4 * %[1]s * %[1]s))) / (1 + %[3]g / %[1]s) END", n, p, z*z, z)
+}
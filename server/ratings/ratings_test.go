@@ -0,0 +1,44 @@
+package ratings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWilsonLowerBound(t *testing.T) {
+	assert.Zero(t, WilsonLowerBound(0, 0))
+	assert.Zero(t, WilsonLowerBound(0, 3))
+	assert.InDelta(t, 0.2065, WilsonLowerBound(1, 0), 0.0001)
+	assert.InDelta(t, 0.5958, WilsonLowerBound(9, 1), 0.0001)
+
+	assert.Greater(t, WilsonLowerBound(9, 1), WilsonLowerBound(1, 0), "more votes give more confidence")
+	assert.Greater(t, WilsonLowerBound(90, 10), WilsonLowerBound(9, 1))
+	assert.Greater(t, WilsonLowerBound(1, 0), WilsonLowerBound(1, 1))
+}
+
+func TestWilsonSQL(t *testing.T) {
+	assert.Equal(t,
+		"CASE WHEN (h + u) = 0 THEN 0 ELSE (((h + 0e0) / (h + u)) + 3.8416 / (2 * (h + u)) - 1.96 * SQRT(((h + 0e0) / (h + u)) * (1 - ((h + 0e0) / (h + u))) / (h + u) + 3.8416 / (4 * (h + u) * (h + u)))) / (1 + 3.8416 / (h + u)) END",
+		WilsonSQL("h", "u"))
+}
+
+func TestParseSort(t *testing.T) {
+	sort, ok := ParseSort("helpful")
+	assert.True(t, ok)
+	assert.Equal(t, MostHelpful, sort)
+
+	_, ok = ParseSort("oldest")
+	assert.False(t, ok)
+}
+
+func TestSummarise(t *testing.T) {
+	summary := Summarise(map[int]int64{5: 3, 4: 1, 1: 1})
+	assert.Equal(t, int64(5), summary.TotalReviews)
+	assert.InDelta(t, 4.0, summary.AverageRating, 0.0001)
+	assert.Equal(t, map[int]int64{1: 1, 2: 0, 3: 0, 4: 1, 5: 3}, summary.Distribution)
+
+	empty := Summarise(nil)
+	assert.Zero(t, empty.AverageRating)
+	assert.Len(t, empty.Distribution, Stars)
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"fmt"
+	"keylab/analytics"
+	"keylab/database/models"
+	"keylab/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// salesStatuses are the statuses of orders that count as sales. Cancelled and returned orders only
+// show up in the status breakdown.
+var salesStatuses = []models.OrderStatus{models.Pending, models.Shipped, models.Delivered}
+
+// Amounts in other currencies are converted back to the base currency at the order's rate.
+const (
+	orderRevenueSQL = "COALESCE(SUM(ROUND(orders.total / orders.exchange_rate, 2)), 0)"
+	itemRevenueSQL  = "COALESCE(SUM(ROUND(ordered_items.price * ordered_items.quantity / ordered_items.exchange_rate, 2)), 0)"
+)
+
+// AnalyticsRange is the orders placed from From up to, not including, To.
+type AnalyticsRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r AnalyticsRange) sales(db *gorm.DB) *gorm.DB {
+	return db.Table("orders").
+		Where("orders.order_date >= ? AND orders.order_date < ?", r.From, r.To).
+		Where("orders.status IN ?", salesStatuses)
+}
+
+// GetSalesByPeriod returns the orders and revenue of every period in the range, including empty
+// ones.
+func GetSalesByPeriod(r AnalyticsRange, interval analytics.Interval, db *gorm.DB) ([]analytics.Point, error) {
+	var points []analytics.Point
+	period := interval.SQL("orders.order_date")
+
+	err := r.sales(db).
+		Select(fmt.Sprintf("%s AS period, COUNT(*) AS orders, %s AS revenue", period, orderRevenueSQL)).
+		Group("period").
+		Order("period").
+		Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics.Fill(interval, r.From, r.To, points), nil
+}
+
+// GetSalesTotals returns the orders, revenue and average order value of the range.
+func GetSalesTotals(r AnalyticsRange, db *gorm.DB) (analytics.Totals, error) {
+	var row struct {
+		Orders  int64
+		Revenue money.Money
+	}
+
+	err := r.sales(db).
+		Select(fmt.Sprintf("COUNT(*) AS orders, %s AS revenue", orderRevenueSQL)).
+		Scan(&row).Error
+	if err != nil {
+		return analytics.Totals{}, err
+	}
+
+	return analytics.NewTotals(row.Orders, row.Revenue), nil
+}
+
+func rankOrder(rank analytics.Rank) string {
+	if rank == analytics.ByUnits {
+		return "units DESC, revenue DESC, id"
+	}
+
+	return "revenue DESC, units DESC, id"
+}
+
+// GetTopProducts returns the products that sold the most in the range.
+func GetTopProducts(r AnalyticsRange, rank analytics.Rank, limit int, db *gorm.DB) ([]analytics.Top, error) {
+	top := []analytics.Top{}
+
+	err := r.sales(db).
+		Joins("JOIN ordered_items ON ordered_items.order_id = orders.id").
+		Joins("JOIN products ON products.id = ordered_items.product_id").
+		Select(fmt.Sprintf("products.id AS id, products.name AS name, products.slug AS slug, SUM(ordered_items.quantity) AS units, %s AS revenue", itemRevenueSQL)).
+		Group("products.id, products.name, products.slug").
+		Order(rankOrder(rank)).
+		Limit(limit).
+		Scan(&top).Error
+
+	return top, err
+}
+
+// GetTopCategories returns the categories whose products sold the most in the range.
+func GetTopCategories(r AnalyticsRange, rank analytics.Rank, limit int, db *gorm.DB) ([]analytics.Top, error) {
+	top := []analytics.Top{}
+
+	err := r.sales(db).
+		Joins("JOIN ordered_items ON ordered_items.order_id = orders.id").
+		Joins("JOIN products ON products.id = ordered_items.product_id").
+		Joins("JOIN product_categories ON product_categories.id = products.category_id").
+		Select(fmt.Sprintf("product_categories.id AS id, product_categories.name AS name, product_categories.slug AS slug, SUM(ordered_items.quantity) AS units, %s AS revenue", itemRevenueSQL)).
+		Group("product_categories.id, product_categories.name, product_categories.slug").
+		Order(rankOrder(rank)).
+		Limit(limit).
+		Scan(&top).Error
+
+	return top, err
+}
+
+// GetCustomerCounts splits the customers who ordered in the range by whether they had ordered
+// before it.
+func GetCustomerCounts(r AnalyticsRange, db *gorm.DB) (analytics.Customers, error) {
+	var customers analytics.Customers
+
+	earlier := db.Table("orders AS earlier").
+		Select("1").
+		Where("earlier.user_id = orders.user_id AND earlier.order_date < ?", r.From).
+		Where("earlier.status IN ?", salesStatuses)
+
+	err := r.sales(db).
+		Select("COUNT(DISTINCT orders.user_id) AS total, COUNT(DISTINCT CASE WHEN EXISTS (?) THEN orders.user_id END) AS `returning`", earlier).
+		Scan(&customers).Error
+	if err != nil {
+		return analytics.Customers{}, err
+	}
+
+	customers.New = customers.Total - customers.Returning
+	return customers, nil
+}
+
+// GetStatusBreakdown returns the orders placed in the range in each status, including cancelled
+// and returned ones. Statuses without orders are left out.
+func GetStatusBreakdown(r AnalyticsRange, db *gorm.DB) ([]analytics.StatusCount, error) {
+	statuses := []analytics.StatusCount{}
+
+	err := db.Table("orders").
+		Where("orders.order_date >= ? AND orders.order_date < ?", r.From, r.To).
+		Select(fmt.Sprintf("orders.status AS status, COUNT(*) AS orders, %s AS revenue", orderRevenueSQL)).
+		Group("orders.status").
+		Order("orders DESC, status").
+		Scan(&statuses).Error
+
+	return statuses, err
+}
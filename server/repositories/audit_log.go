@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"keylab/database/models"
+	"keylab/utils"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogFilter struct {
+	ActorID    *int64
+	EntityType string
+	EntityID   *int64
+	From       *time.Time
+	To         *time.Time
+}
+
+// CreateAuditLog appends an entry to the audit log. Entries are never updated or deleted.
+func CreateAuditLog(entry *models.AuditLog, db *gorm.DB) error {
+	if err := db.Create(entry).Error; err != nil {
+		slog.ErrorContext(db.Statement.Context, "Error creating audit log", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RecordAudit completes entry with the fields that differ between before and after and appends it
+// to the audit log. Failures are logged rather than returned, since the action itself has already
+// been committed.
+func RecordAudit(db *gorm.DB, entry models.AuditLog, before interface{}, after interface{}) {
+	ctx := db.Statement.Context
+	attrs := []interface{}{"action", entry.Action, "entity_type", entry.EntityType, "entity_id", entry.EntityID}
+
+	oldValues, newValues, err := utils.DiffJSON(before, after)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error computing audit diff", append(attrs, "error", err)...)
+		return
+	}
+
+	entry.OldValues = oldValues
+	entry.NewValues = newValues
+	if err := CreateAuditLog(&entry, db); err != nil {
+		slog.ErrorContext(ctx, "Error recording audit log", append(attrs, "error", err)...)
+	}
+}
+
+// GetAuditLogs fetches a page of audit log entries matching the filter, newest first, with the total count
+func GetAuditLogs(filter AuditLogFilter, limit int, offset int, db *gorm.DB) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var total int64
+
+	query := db.Model(&models.AuditLog{})
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		slog.ErrorContext(db.Statement.Context, "Error counting audit logs", "error", err)
+		return nil, 0, err
+	}
+
+	err := query.Preload("Actor", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "forename", "surname", "email")
+	}).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&entries).Error
+
+	if err != nil {
+		slog.ErrorContext(db.Statement.Context, "Error fetching audit logs", "error", err)
+	}
+
+	return entries, total, err
+}
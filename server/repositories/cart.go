@@ -3,7 +3,9 @@ package repositories
 import (
 	"errors"
 	"keylab/database/models"
-	"log"
+	"keylab/money"
+	"keylab/tax"
+	"log/slog"
 
 	"gorm.io/gorm"
 )
@@ -14,7 +16,7 @@ func GetCartItemsByUserID(userID int64, db *gorm.DB) ([]models.CartItems, error)
 	err := db.Preload("Product").Preload("Product.Category").Where("user_id = ?", userID).Find(&cartItems).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching cart items for user ID %d: %v", userID, err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching cart items", "user_id", userID, "error", err)
 	}
 
 	return cartItems, err
@@ -26,7 +28,7 @@ func GetCartItemByID(cartItemID int64, db *gorm.DB) (models.CartItems, error) {
 	err := db.Preload("Product").Preload("Product.Category").First(&cartItem, cartItemID).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching cart item by ID %d: %v", cartItemID, err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching cart item by ID", "cart_item_id", cartItemID, "error", err)
 	}
 
 	return cartItem, err
@@ -38,21 +40,33 @@ func GetProductByID(productID int64, db *gorm.DB) (models.Product, error) {
 	err := db.First(&product, productID).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching product by ID %d: %v", productID, err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching product by ID", "product_id", productID, "error", err)
 	}
 
 	return product, err
 }
 
-// Calculate Card Total
-func CalculateTotal(cartItems []models.CartItems, db *gorm.DB) float64 {
-	var total float64
+// CalculateTotal adds up the price of every cart item times its quantity, which is exact.
+func CalculateTotal(cartItems []models.CartItems, db *gorm.DB) money.Money {
+	var total money.Money
 	for _, item := range cartItems {
-		total += item.Product.Price * float64(item.Quantity)
+		total = total.Add(item.Product.Price.Mul(int64(item.Quantity)))
 	}
 	return total
 }
 
+// TaxLines describes each cart item to the tax calculator, with the tax class of its category.
+func TaxLines(cartItems []models.CartItems) []tax.Line {
+	lines := make([]tax.Line, len(cartItems))
+	for i, item := range cartItems {
+		lines[i] = tax.Line{ProductID: item.ProductID, Amount: item.Product.Price.Mul(int64(item.Quantity))}
+		if item.Product.Category != nil && item.Product.Category.TaxClassID != nil {
+			lines[i].TaxClassID = *item.Product.Category.TaxClassID
+		}
+	}
+	return lines
+}
+
 // Fetch or Store Address
 func HandleAddress(userID int64, addressID int64, newAddress *models.Address, addressType models.AddressType, db *gorm.DB) (*models.Address, error) {
 	if addressID != 0 {
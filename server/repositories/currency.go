@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"errors"
+	"keylab/database/models"
+	"keylab/money"
+
+	"gorm.io/gorm"
+)
+
+// ErrCurrencyUnavailable is returned for a currency without an exchange rate.
+var ErrCurrencyUnavailable = errors.New("currency has no exchange rate")
+
+// Pricing is the currency prices are shown and charged in, with the rate it is converted at from
+// the store's base currency.
+type Pricing struct {
+	Base     money.Currency
+	Currency money.Currency
+	Rate     money.Rate
+}
+
+func (p Pricing) IsBase() bool {
+	return p.Currency == p.Base
+}
+
+// GetPricing returns the pricing for currency. The base currency is always available at rate 1,
+// any other currency needs an exchange rate.
+func GetPricing(base money.Currency, currency money.Currency, db *gorm.DB) (Pricing, error) {
+	if currency == base {
+		return Pricing{Base: base, Currency: base, Rate: money.RateOne}, nil
+	}
+
+	var exchangeRate models.ExchangeRate
+	if err := db.Where("currency = ?", currency).First(&exchangeRate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Pricing{}, ErrCurrencyUnavailable
+		}
+		return Pricing{}, err
+	}
+
+	return Pricing{Base: base, Currency: currency, Rate: exchangeRate.Rate}, nil
+}
+
+// PriceProducts sets the price of each product in the pricing's currency: the product's own price
+// in that currency when it has one, its base price converted at the exchange rate otherwise.
+func PriceProducts(products []models.Product, pricing Pricing, db *gorm.DB) error {
+	pointers := make([]*models.Product, len(products))
+	for i := range products {
+		pointers[i] = &products[i]
+	}
+
+	return priceProducts(pointers, pricing, db)
+}
+
+// PriceCartItems is PriceProducts for the products in a cart.
+func PriceCartItems(cartItems []models.CartItems, pricing Pricing, db *gorm.DB) error {
+	var products []*models.Product
+	for _, item := range cartItems {
+		if item.Product != nil {
+			products = append(products, item.Product)
+		}
+	}
+
+	return priceProducts(products, pricing, db)
+}
+
+func priceProducts(products []*models.Product, pricing Pricing, db *gorm.DB) error {
+	if pricing.IsBase() || len(products) == 0 {
+		for _, product := range products {
+			product.Currency = pricing.Currency
+		}
+		return nil
+	}
+
+	productIDs := make([]int64, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+
+	var prices []models.ProductPrice
+	if err := db.Where("product_id IN ? AND currency = ?", productIDs, pricing.Currency).Find(&prices).Error; err != nil {
+		return err
+	}
+
+	byProduct := make(map[int64]money.Money, len(prices))
+	for _, price := range prices {
+		byProduct[price.ProductID] = price.Price
+	}
+
+	for _, product := range products {
+		if product.Currency == pricing.Currency {
+			continue // already priced, e.g. a product shared by two cart items
+		}
+
+		if price, ok := byProduct[product.ID]; ok {
+			product.Price = price
+		} else {
+			product.Price = product.Price.Convert(pricing.Rate)
+		}
+		product.Currency = pricing.Currency
+	}
+
+	return nil
+}
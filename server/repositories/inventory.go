@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"keylab/database/models"
+	"keylab/notify"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// A product is low on stock once its stock falls to its own threshold, or to the shop's default
+// threshold when it has none.
+const (
+	lowStockThresholdSQL = "COALESCE(products.low_stock_threshold, ?)"
+	lowStockSQL          = "products.stock <= " + lowStockThresholdSQL
+)
+
+// LowStockProduct is a row of the low stock report.
+type LowStockProduct struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Slug      string     `json:"slug"`
+	Stock     int        `json:"stock"`
+	Threshold int        `json:"threshold"`
+	AlertedAt *time.Time `json:"alerted_at"`
+}
+
+// TakeStock lowers the product's stock by quantity and returns what is left. Run it in a
+// transaction: the update locks the product's row, so the stock read back is the one this
+// checkout left, whatever concurrent checkouts do.
+func TakeStock(productID int64, quantity int, db *gorm.DB) (int, error) {
+	err := db.Model(&models.Product{}).Where("id = ?", productID).UpdateColumn("stock", gorm.Expr("stock - ?", quantity)).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var stock int
+	err = db.Model(&models.Product{}).Select("stock").Where("id = ?", productID).Scan(&stock).Error
+
+	return stock, err
+}
+
+// ResetLowStockAlerts forgets the alerts sent for the products that have since been restocked above
+// their threshold, so they are alerted again when they next run low.
+func ResetLowStockAlerts(productIDs []int64, threshold int, db *gorm.DB) error {
+	return db.Model(&models.Product{}).
+		Where("id IN ? AND low_stock_alerted_at IS NOT NULL", productIDs).
+		Where("products.stock > "+lowStockThresholdSQL, threshold).
+		UpdateColumn("low_stock_alerted_at", nil).Error
+}
+
+// ClaimLowStockAlerts marks the products that are low on stock and have not been alerted yet as
+// alerted, returning them. Run it in the transaction that lowered their stock, which holds their
+// row locks, so concurrent checkouts cannot both claim a product.
+func ClaimLowStockAlerts(productIDs []int64, threshold int, db *gorm.DB) ([]notify.LowStock, error) {
+	var products []notify.LowStock
+	err := db.Table("products").
+		Select("products.id AS product_id, products.name, products.slug, products.stock, "+lowStockThresholdSQL+" AS threshold", threshold).
+		Where("products.id IN ? AND products.low_stock_alerted_at IS NULL", productIDs).
+		Where(lowStockSQL, threshold).
+		Order("products.id").
+		Scan(&products).Error
+	if err != nil || len(products) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int64, len(products))
+	for i, product := range products {
+		ids[i] = product.ProductID
+	}
+
+	err = db.Model(&models.Product{}).Where("id IN ?", ids).UpdateColumn("low_stock_alerted_at", time.Now()).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// GetLowStockProducts returns a page of the products that are low on stock, the emptiest first, and
+// how many there are.
+func GetLowStockProducts(threshold int, limit int, offset int, db *gorm.DB) ([]LowStockProduct, int64, error) {
+	products := []LowStockProduct{}
+	lowStock := func() *gorm.DB {
+		return db.Table("products").Where(lowStockSQL, threshold)
+	}
+
+	var total int64
+	if err := lowStock().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := lowStock().
+		Select("products.id, products.name, products.slug, products.stock, "+lowStockThresholdSQL+" AS threshold, products.low_stock_alerted_at AS alerted_at", threshold).
+		Order("products.stock, products.name").
+		Limit(limit).
+		Offset(offset).
+		Scan(&products).Error
+
+	return products, total, err
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"errors"
+	"keylab/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IssueInvoice returns the order's invoice, giving it the next invoice number the first time.
+// Locking the highest number keeps concurrent issues from sharing or skipping a number.
+func IssueInvoice(orderID int64, db *gorm.DB) (models.Invoice, error) {
+	var invoice models.Invoice
+	err := db.Where("order_id = ?", orderID).First(&invoice).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return invoice, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var last int64
+		if err := tx.Raw("SELECT COALESCE(MAX(number), 0) FROM invoices FOR UPDATE").Scan(&last).Error; err != nil {
+			return err
+		}
+
+		invoice = models.Invoice{OrderID: orderID, Number: last + 1, IssuedAt: time.Now().UTC().Truncate(time.Second)}
+		return tx.Create(&invoice).Error
+	})
+	if err != nil {
+		// Another request may have issued the invoice first.
+		if again := db.Where("order_id = ?", orderID).First(&invoice).Error; again == nil {
+			return invoice, nil
+		}
+		return models.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+// GetOrderForDocuments fetches an order with everything its invoice and packing slip show.
+func GetOrderForDocuments(orderID int64, db *gorm.DB) (models.Order, error) {
+	var order models.Order
+	err := db.Preload("User").
+		Preload("BillingAddress").
+		Preload("ShippingAddress").
+		Preload("Taxes").
+		Preload("Invoice").
+		Preload("OrderItems.Product").
+		First(&order, orderID).Error
+
+	return order, err
+}
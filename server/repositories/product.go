@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"keylab/database/models"
-	"log"
+	"log/slog"
 
 	"gorm.io/gorm"
 )
@@ -14,7 +14,7 @@ func GetProducts(order string, limit int, offset int, db *gorm.DB) ([]models.Pro
 	err := db.Preload("Category").Preload("Category.Parent").Preload("ProductImages").Order(order).Limit(limit).Offset(offset).Find(&products).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching products: %v", err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching products", "error", err)
 	}
 
 	return products, err
@@ -25,7 +25,7 @@ func GetProductBySlug(slug string, db *gorm.DB) (models.Product, error) {
 	err := db.Preload("Category").Preload("Category.Parent").Preload("ProductImages").Where("slug = ?", slug).First(&product).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching product by slug: %v", err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching product by slug", "error", err)
 	}
 
 	return product, err
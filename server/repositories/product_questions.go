@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"errors"
+	"keylab/database/models"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// answerOrder lists official answers first, then the most upvoted.
+const answerOrder = "product_answers.official DESC, product_answers.upvote_count DESC, product_answers.created_at, product_answers.id"
+
+// withAuthor loads the name of the user who wrote the question or answer.
+func withAuthor(db *gorm.DB) *gorm.DB {
+	return db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "forename", "surname")
+	})
+}
+
+// withAnswers loads the questions' answers with their authors, only the published ones unless all
+// is set.
+func withAnswers(all bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Answers", func(db *gorm.DB) *gorm.DB {
+			if !all {
+				db = db.Where("product_answers.status = ?", models.QuestionPublished)
+			}
+			return db.Order(answerOrder)
+		}).Preload("Answers.User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "forename", "surname")
+		})
+	}
+}
+
+// GetQuestionByID returns a question in any status with all of its answers.
+func GetQuestionByID(id int64, db *gorm.DB) (models.ProductQuestion, error) {
+	var question models.ProductQuestion
+	err := db.Scopes(withAuthor, withAnswers(true)).Where("id = ?", id).First(&question).Error
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(db.Statement.Context, "Error fetching question by ID", "error", err)
+	}
+
+	return question, err
+}
+
+// GetPublishedQuestion returns a published question about the product with its published answers.
+func GetPublishedQuestion(id int64, productID int64, db *gorm.DB) (models.ProductQuestion, error) {
+	var question models.ProductQuestion
+	err := db.Scopes(withAuthor, withAnswers(false)).
+		Where("id = ? AND product_id = ? AND status = ?", id, productID, models.QuestionPublished).
+		First(&question).Error
+
+	return question, err
+}
+
+// GetProductQuestions returns a page of the published questions about a product with their
+// published answers, newest first, and how many there are.
+func GetProductQuestions(productID int64, limit int, offset int, db *gorm.DB) ([]models.ProductQuestion, int64, error) {
+	questions := []models.ProductQuestion{}
+	published := func() *gorm.DB {
+		return db.Model(&models.ProductQuestion{}).Where("product_id = ? AND status = ?", productID, models.QuestionPublished)
+	}
+
+	var total int64
+	if err := published().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := published().Scopes(withAuthor, withAnswers(false)).
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&questions).Error
+
+	return questions, total, err
+}
+
+// QuestionFilter narrows the question moderation queue. Zero fields match every question.
+type QuestionFilter struct {
+	Status    models.QuestionStatus
+	ProductID int64
+	UserID    int64
+	// Unanswered matches the questions without a published answer.
+	Unanswered bool
+}
+
+// GetModerationQuestions returns a page of questions in any status with all of their answers and
+// how many match the filter, newest first.
+func GetModerationQuestions(filter QuestionFilter, limit int, offset int, db *gorm.DB) ([]models.ProductQuestion, int64, error) {
+	questions := []models.ProductQuestion{}
+	filtered := func() *gorm.DB {
+		query := db.Model(&models.ProductQuestion{})
+		if filter.Status != "" {
+			query = query.Where("status = ?", filter.Status)
+		}
+		if filter.ProductID != 0 {
+			query = query.Where("product_id = ?", filter.ProductID)
+		}
+		if filter.UserID != 0 {
+			query = query.Where("user_id = ?", filter.UserID)
+		}
+		if filter.Unanswered {
+			answered := db.Model(&models.ProductAnswer{}).Select("1").
+				Where("product_answers.question_id = product_questions.id AND product_answers.status = ?", models.QuestionPublished)
+			query = query.Where("NOT EXISTS (?)", answered)
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := filtered().Scopes(withAuthor, withAnswers(true)).
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&questions).Error
+
+	return questions, total, err
+}
+
+// SaveAnswerVote records the user's upvote of an answer, reporting whether they had not upvoted
+// it already, and recounts the answer's upvotes.
+func SaveAnswerVote(vote *models.AnswerVote, db *gorm.DB) (bool, error) {
+	var created bool
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(vote)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		created = result.RowsAffected > 0
+		return countAnswerVotes(vote.AnswerID, tx)
+	})
+
+	return created, err
+}
+
+// DeleteAnswerVote withdraws the user's upvote of an answer, reporting whether there was one, and
+// recounts the answer's upvotes.
+func DeleteAnswerVote(answerID int64, userID int64, db *gorm.DB) (bool, error) {
+	var deleted bool
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("answer_id = ? AND user_id = ?", answerID, userID).Delete(&models.AnswerVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		deleted = result.RowsAffected > 0
+		return countAnswerVotes(answerID, tx)
+	})
+
+	return deleted, err
+}
+
+func countAnswerVotes(answerID int64, db *gorm.DB) error {
+	votes := db.Model(&models.AnswerVote{}).Select("COUNT(*)").Where("answer_id = ?", answerID)
+
+	return db.Model(&models.ProductAnswer{}).Where("id = ?", answerID).UpdateColumn("upvote_count", votes).Error
+}
+
+// GetAnswerByID returns an answer in any status with its author.
+func GetAnswerByID(id int64, db *gorm.DB) (models.ProductAnswer, error) {
+	var answer models.ProductAnswer
+	err := db.Scopes(withAuthor).Where("id = ?", id).First(&answer).Error
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		slog.ErrorContext(db.Statement.Context, "Error fetching answer by ID", "error", err)
+	}
+
+	return answer, err
+}
@@ -2,10 +2,13 @@ package repositories
 
 import (
 	"errors"
+	"fmt"
 	"keylab/database/models"
-	"log"
+	"keylab/ratings"
+	"log/slog"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Get review by ID
@@ -14,41 +17,286 @@ func GetReviewByID(id int64, db *gorm.DB) (models.ProductReviews, error) {
 
 	err := db.Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "forename", "surname")
-	}).Preload("Product").Preload("Product.Category").Where("id = ?", id).First(&review).Error
+	}).Preload("Product").Preload("Product.Category").Scopes(withReviewPhotos).Where("id = ?", id).First(&review).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching review by ID: %v", err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching review by ID", "error", err)
 	}
 
 	return review, err
 }
 
-// Retrieve all reviews by a user
-func GetReviewByUserID(userID int64, db *gorm.DB) ([]models.ProductReviews, error) {
-	var reviews []models.ProductReviews
+// withReviewPhotos loads the photos of the reviews, in the order they were uploaded.
+func withReviewPhotos(db *gorm.DB) *gorm.DB {
+	return db.Preload("Photos", func(db *gorm.DB) *gorm.DB {
+		return db.Order("review_photos.id")
+	})
+}
 
-	err := db.Preload("User", func(db *gorm.DB) *gorm.DB {
-		return db.Select("id", "forename", "surname")
-	}).Preload("Product").Preload("Product.Category").Where("user_id = ?", userID).Find(&reviews).Error
+// SetReviewPhotoURLs sets the URL each of the reviews' photos is served at.
+func SetReviewPhotoURLs(reviews []models.ProductReviews, baseURL string) {
+	for i := range reviews {
+		for j := range reviews[i].Photos {
+			reviews[i].Photos[j].URL = fmt.Sprintf("%s/reviews/photos/%s", baseURL, reviews[i].Photos[j].Image)
+		}
+	}
+}
 
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching reviews by user ID: %v", err)
+// approvedReviews limits a query to the reviews shoppers can see.
+func approvedReviews(db *gorm.DB) *gorm.DB {
+	return db.Where("product_reviews.status = ?", models.ReviewApproved)
+}
+
+// VerifiedReviews limits a query to reviews that are, or are not, verified purchases.
+func VerifiedReviews(verified bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("product_reviews.verified_purchase = ?", verified)
 	}
+}
 
-	return reviews, err
+// HasReceivedProduct reports whether the user has an order with the product that was delivered.
+func HasReceivedProduct(userID int64, productID int64, db *gorm.DB) (bool, error) {
+	delivered := db.Table("ordered_items").
+		Select("1").
+		Joins("JOIN orders ON orders.id = ordered_items.order_id").
+		Where("orders.user_id = ? AND ordered_items.product_id = ? AND orders.status = ?", userID, productID, models.Delivered)
+
+	var received bool
+	err := db.Raw("SELECT EXISTS (?)", delivered).Scan(&received).Error
+
+	return received, err
 }
 
-// Retrieve all reviews for a product by product ID
-func GetReviewsByProductID(productID int64, db *gorm.DB) ([]models.ProductReviews, error) {
-	var reviews []models.ProductReviews
+// ReviewPage is a page of a review listing in one of the ratings orders.
+type ReviewPage struct {
+	Sort   ratings.Sort
+	Limit  int
+	Offset int
+}
 
-	err := db.Preload("User", func(db *gorm.DB) *gorm.DB {
+func reviewOrder(sort ratings.Sort) string {
+	switch sort {
+	case ratings.MostHelpful:
+		return ratings.WilsonSQL("product_reviews.helpful_count", "product_reviews.not_helpful_count") + " DESC, product_reviews.created_at DESC, product_reviews.id DESC"
+	case ratings.Highest:
+		return "product_reviews.rating DESC, product_reviews.created_at DESC, product_reviews.id DESC"
+	case ratings.Lowest:
+		return "product_reviews.rating, product_reviews.created_at DESC, product_reviews.id DESC"
+	default:
+		return "product_reviews.created_at DESC, product_reviews.id DESC"
+	}
+}
+
+// find returns the page of the reviews matched by query and how many there are in all.
+func (p ReviewPage) find(query *gorm.DB) ([]models.ProductReviews, int64, error) {
+	query = query.Model(&models.ProductReviews{}).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	reviews := []models.ProductReviews{}
+	err := query.Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "forename", "surname")
-	}).Preload("Product").Preload("Product.Category").Where("product_id = ?", productID).Find(&reviews).Error
+	}).Preload("Product").Preload("Product.Category").Scopes(withReviewPhotos).Order(reviewOrder(p.Sort)).Limit(p.Limit).Offset(p.Offset).Find(&reviews).Error
 
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching reviews by product ID: %v", err)
+	return reviews, total, err
+}
+
+// Retrieve a page of the approved reviews by a user
+func GetReviewByUserID(userID int64, page ReviewPage, db *gorm.DB) ([]models.ProductReviews, int64, error) {
+	reviews, total, err := page.find(db.Scopes(approvedReviews).Where("product_reviews.user_id = ?", userID))
+
+	if err != nil {
+		slog.ErrorContext(db.Statement.Context, "Error fetching reviews by user ID", "error", err)
+	}
+
+	return reviews, total, err
+}
+
+// Retrieve a page of the approved reviews for a product by product ID
+func GetReviewsByProductID(productID int64, page ReviewPage, db *gorm.DB) ([]models.ProductReviews, int64, error) {
+	reviews, total, err := page.find(db.Scopes(approvedReviews).Where("product_reviews.product_id = ?", productID))
+
+	if err != nil {
+		slog.ErrorContext(db.Statement.Context, "Error fetching reviews by product ID", "error", err)
+	}
+
+	return reviews, total, err
+}
+
+// GetReviewSummary counts the approved reviews of a product per rating.
+func GetReviewSummary(productID int64, db *gorm.DB) (ratings.Summary, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	err := db.Model(&models.ProductReviews{}).
+		Scopes(approvedReviews).
+		Where("product_reviews.product_id = ?", productID).
+		Select("product_reviews.rating, COUNT(*) AS count").
+		Group("product_reviews.rating").
+		Scan(&rows).Error
+	if err != nil {
+		return ratings.Summary{}, err
+	}
+
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Rating] = row.Count
+	}
+
+	return ratings.Summarise(counts), nil
+}
+
+// RefreshProductRating recounts the product's approved reviews into its review_count and
+// average_rating. Run it whenever a review of the product is created, changed or deleted.
+func RefreshProductRating(productID int64, db *gorm.DB) error {
+	return refreshRatings(db.Table("products").Where("id = ?", productID), db)
+}
+
+// RefreshProductRatings recounts the approved reviews of every product that has reviews, for
+// reviews written without going through RefreshProductRating, as the seeders do.
+func RefreshProductRatings(db *gorm.DB) error {
+	reviewed := db.Model(&models.ProductReviews{}).Select("product_id")
+
+	return refreshRatings(db.Table("products").Where("id IN (?)", reviewed), db)
+}
+
+func refreshRatings(products *gorm.DB, db *gorm.DB) error {
+	approved := func(aggregate string) *gorm.DB {
+		return db.Model(&models.ProductReviews{}).Scopes(approvedReviews).
+			Select(aggregate).Where("product_reviews.product_id = products.id")
+	}
+
+	// The columns are read only on the model, so they are written through the table. Recounting is
+	// not an edit of the product and keeps its updated_at.
+	return products.UpdateColumns(map[string]interface{}{
+		"review_count":   approved("COUNT(*)"),
+		"average_rating": approved("COALESCE(AVG(product_reviews.rating), 0)"),
+		"updated_at":     gorm.Expr("updated_at"),
+	}).Error
+}
+
+// SaveReviewVote records the user's vote on a review, replacing an earlier one, and recounts the
+// review's votes.
+func SaveReviewVote(vote *models.ReviewVote, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "review_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"helpful", "updated_at"}),
+		}).Create(vote).Error
+		if err != nil {
+			return err
+		}
+
+		return countReviewVotes(vote.ReviewID, tx)
+	})
+}
+
+// DeleteReviewVote withdraws the user's vote on a review, reporting whether there was one, and
+// recounts the review's votes.
+func DeleteReviewVote(reviewID int64, userID int64, db *gorm.DB) (bool, error) {
+	var deleted bool
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&models.ReviewVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		deleted = result.RowsAffected > 0
+		return countReviewVotes(reviewID, tx)
+	})
+
+	return deleted, err
+}
+
+func countReviewVotes(reviewID int64, db *gorm.DB) error {
+	votes := func(helpful bool) *gorm.DB {
+		return db.Model(&models.ReviewVote{}).Select("COUNT(*)").Where("review_id = ? AND helpful = ?", reviewID, helpful)
 	}
 
+	return db.Model(&models.ProductReviews{}).Where("id = ?", reviewID).UpdateColumns(map[string]interface{}{
+		"helpful_count":     votes(true),
+		"not_helpful_count": votes(false),
+	}).Error
+}
+
+// GetRecentReviews returns the newest approved reviews.
+func GetRecentReviews(limit int, db *gorm.DB) ([]models.ProductReviews, error) {
+	var reviews []models.ProductReviews
+	err := db.Preload("User").Preload("Product").Scopes(approvedReviews, withReviewPhotos).Order("created_at DESC").Limit(limit).Find(&reviews).Error
+
 	return reviews, err
 }
+
+// ReviewFilter narrows the moderation queue. Zero fields match every review.
+type ReviewFilter struct {
+	Status    models.ReviewStatus
+	ProductID int64
+	UserID    int64
+	Reported  bool
+	Verified  *bool
+}
+
+// GetModerationReviews returns a page of reviews in any status and how many match the filter,
+// newest first, or the most reported first when only reported reviews are asked for.
+func GetModerationReviews(filter ReviewFilter, limit int, offset int, db *gorm.DB) ([]models.ProductReviews, int64, error) {
+	reviews := []models.ProductReviews{}
+	filtered := func() *gorm.DB {
+		query := db.Model(&models.ProductReviews{})
+		if filter.Status != "" {
+			query = query.Where("status = ?", filter.Status)
+		}
+		if filter.ProductID != 0 {
+			query = query.Where("product_id = ?", filter.ProductID)
+		}
+		if filter.UserID != 0 {
+			query = query.Where("user_id = ?", filter.UserID)
+		}
+		if filter.Reported {
+			query = query.Where("report_count > 0")
+		}
+		if filter.Verified != nil {
+			query = query.Scopes(VerifiedReviews(*filter.Verified))
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_at DESC, id DESC"
+	if filter.Reported {
+		order = "report_count DESC, " + order
+	}
+
+	err := filtered().Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "forename", "surname")
+	}).Preload("Product").Scopes(withReviewPhotos).Order(order).Limit(limit).Offset(offset).Find(&reviews).Error
+
+	return reviews, total, err
+}
+
+// GetReviewReports returns the reports made about a review, newest first.
+func GetReviewReports(reviewID int64, db *gorm.DB) ([]models.ReviewReport, error) {
+	reports := []models.ReviewReport{}
+	err := db.Where("review_id = ?", reviewID).Order("created_at DESC, id DESC").Find(&reports).Error
+
+	return reports, err
+}
+
+// CreateReviewReport saves a report and counts it on the review.
+func CreateReviewReport(report *models.ReviewReport, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.ProductReviews{}).Where("id = ?", report.ReviewID).
+			UpdateColumn("report_count", gorm.Expr("report_count + 1")).Error
+	})
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"database/sql"
+	"keylab/database/models"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxRecommendations is how many recommendations are kept for each product, the most a request
+// can ask for.
+const MaxRecommendations = 24
+
+// recommendationsLock is the database lock held while refreshing, so only one server does it.
+const recommendationsLock = "keylab_recommendations"
+
+// coOccurrenceSQL scores each pair of different products by the number of orders placed since the
+// given time, other than cancelled or returned ones, that had both, keeping only the recommended
+// products in stock and the highest scoring ones for each product.
+const coOccurrenceSQL = `INSERT INTO product_recommendations (product_id, recommended_id, score)
+SELECT product_id, recommended_id, score
+FROM (
+	SELECT pairs.*, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY score DESC, recommended_id) AS position
+	FROM (
+		SELECT bought.product_id, other.product_id AS recommended_id, COUNT(DISTINCT bought.order_id) AS score
+		FROM ordered_items bought
+		JOIN ordered_items other ON other.order_id = bought.order_id AND other.product_id <> bought.product_id
+		JOIN orders ON orders.id = bought.order_id
+		JOIN products ON products.id = other.product_id
+		WHERE orders.status NOT IN ? AND orders.created_at >= ? AND products.stock > 0
+		GROUP BY bought.product_id, other.product_id
+	) pairs
+) ranked
+WHERE position <= ?`
+
+// RefreshRecommendations recomputes every product's recommendations from the orders placed since
+// the given time, in one transaction so they are never read half written. When another server is
+// already refreshing them it leaves the work to that one.
+func RefreshRecommendations(since time.Time, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The lock belongs to the connection, which the transaction keeps for itself
+		var locked sql.NullInt64
+		if err := tx.Raw("SELECT GET_LOCK(?, 0)", recommendationsLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if locked.Int64 != 1 {
+			slog.InfoContext(tx.Statement.Context, "Recommendations are being refreshed by another server")
+			return nil
+		}
+		defer tx.Exec("SELECT RELEASE_LOCK(?)", recommendationsLock)
+
+		if err := tx.Exec("DELETE FROM product_recommendations").Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(coOccurrenceSQL, []models.OrderStatus{models.Cancelled, models.Returned}, since, MaxRecommendations).Error
+	})
+}
+
+// recommendable loads products to show as recommendations, limited to the ones in stock.
+func recommendable(db *gorm.DB) *gorm.DB {
+	return db.Preload("Category").Preload("Category.Parent").Preload("ProductImages").Where("products.stock > 0")
+}
+
+// GetRecommendations returns up to limit products in stock to recommend alongside the product: the
+// ones most often bought with it, topped up with the best rated of its category when there are not
+// enough of those.
+func GetRecommendations(product models.Product, limit int, db *gorm.DB) ([]models.Product, error) {
+	products := []models.Product{}
+	err := db.Scopes(recommendable).
+		Joins("JOIN product_recommendations ON product_recommendations.recommended_id = products.id").
+		Where("product_recommendations.product_id = ?", product.ID).
+		Order("product_recommendations.score DESC, products.id").
+		Limit(limit).
+		Find(&products).Error
+	if err != nil || len(products) >= limit {
+		return products, err
+	}
+
+	exclude := []int64{product.ID}
+	for _, recommended := range products {
+		exclude = append(exclude, recommended.ID)
+	}
+
+	var sameCategory []models.Product
+	err = db.Scopes(recommendable).
+		Where("products.category_id = ? AND products.id NOT IN ?", product.CategoryID, exclude).
+		Order("products.average_rating DESC, products.review_count DESC, products.id").
+		Limit(limit - len(products)).
+		Find(&sameCategory).Error
+
+	return append(products, sameCategory...), err
+}
@@ -1,7 +1,7 @@
 package repositories
 
 import (
-	"log"
+	"log/slog"
 
 	"gorm.io/gorm"
 )
@@ -16,7 +16,7 @@ func CheckRolePermissions(roleID int64, requiredPermissions []string, db *gorm.D
 		Count(&count).Error
 
 	if err != nil {
-		log.Printf("Error checking role permissions: %v", err)
+		slog.ErrorContext(db.Statement.Context, "Error checking role permissions", "error", err)
 		return false, err
 	}
 
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"keylab/database/models"
+	"keylab/shipping"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ShippingCart describes the cart to the shipping package: its subtotal at the prices it is
+// charged at, and its weight.
+func ShippingCart(cartItems []models.CartItems) shipping.Cart {
+	var cart shipping.Cart
+	for _, item := range cartItems {
+		if item.Product != nil {
+			cart.Subtotal = cart.Subtotal.Add(item.Product.Price.Mul(int64(item.Quantity)))
+			cart.Weight += item.Product.Weight * item.Quantity
+		}
+	}
+	return cart
+}
+
+// GetShippingOptions quotes the methods of the zone the address is in for the cart, in the
+// pricing's currency. An address outside every zone has no options.
+func GetShippingOptions(address models.Address, cart shipping.Cart, pricing Pricing, db *gorm.DB) ([]shipping.Option, error) {
+	var zones []models.ShippingZone
+	if err := db.Preload("Methods").Where("country = ?", strings.TrimSpace(address.Country)).Find(&zones).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := make([]shipping.Zone, len(zones))
+	for i, zone := range zones {
+		candidates[i] = shipping.Zone{ID: zone.ID, Country: zone.Country, PostalPrefix: zone.PostalPrefix}
+	}
+
+	match, ok := shipping.MatchZone(candidates, address.Country, address.PostalCode)
+	if !ok {
+		return []shipping.Option{}, nil
+	}
+
+	var methods []shipping.Method
+	for _, zone := range zones {
+		if zone.ID != match.ID {
+			continue
+		}
+		for _, method := range zone.Methods {
+			methods = append(methods, shipping.Method{
+				ID:       method.ID,
+				Name:     method.Name,
+				Type:     method.Type,
+				RateType: method.RateType,
+				Price:    method.Price.Convert(pricing.Rate),
+				PerKg:    method.PerKg.Convert(pricing.Rate),
+				FreeOver: method.FreeOver.Convert(pricing.Rate),
+			})
+		}
+	}
+
+	return shipping.Options(methods, cart), nil
+}
+
+// HasShippingZones reports whether any shipping zone has been set up. Until one is, orders ship
+// without a method.
+func HasShippingZones(db *gorm.DB) (bool, error) {
+	var count int64
+	err := db.Model(&models.ShippingZone{}).Count(&count).Error
+
+	return count > 0, err
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"keylab/database/models"
+	"keylab/tax"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// TaxRateTable is the tax calculator backed by the tax_rates table admins maintain.
+type TaxRateTable struct {
+	DB *gorm.DB
+}
+
+func NewTaxRateTable(db *gorm.DB) *TaxRateTable {
+	return &TaxRateTable{DB: db}
+}
+
+// Calculate taxes the request with the rates for the address's country. Lines of the standard
+// class, TaxClassID 0, use the rates of the standard tax class.
+func (t *TaxRateTable) Calculate(ctx context.Context, request tax.Request) (tax.Result, error) {
+	db := t.DB.WithContext(ctx)
+
+	var standard models.TaxClass
+	if err := db.Where("slug = ?", models.StandardTaxClass).First(&standard).Error; err != nil {
+		return tax.Result{}, err
+	}
+
+	lines := make([]tax.Line, len(request.Lines))
+	for i, line := range request.Lines {
+		lines[i] = line
+		if line.TaxClassID == 0 {
+			lines[i].TaxClassID = standard.ID
+		}
+	}
+	request.Lines = lines
+
+	var taxRates []models.TaxRate
+	if err := db.Where("country = ?", strings.TrimSpace(request.Address.Country)).Find(&taxRates).Error; err != nil {
+		return tax.Result{}, err
+	}
+
+	rates := make([]tax.Rate, len(taxRates))
+	for i, taxRate := range taxRates {
+		rates[i] = tax.Rate{
+			TaxClassID: taxRate.TaxClassID,
+			Country:    taxRate.Country,
+			County:     taxRate.County,
+			Name:       taxRate.Name,
+			Rate:       taxRate.Rate,
+		}
+	}
+
+	return tax.Compute(request, rates), nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"keylab/database/models"
+	"keylab/i18n"
+
+	"gorm.io/gorm"
+)
+
+// LocalizeProducts replaces the name and description of each product, and of its category and
+// the category's parent, with their translation into locale. Anything without a translation keeps
+// its default language text, so a partly translated catalog still reads as a whole.
+func LocalizeProducts(products []models.Product, locale string, db *gorm.DB) error {
+	if locale == i18n.Default || len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int64, len(products))
+	var categories []*models.ProductCategory
+	for i := range products {
+		productIDs[i] = products[i].ID
+		if products[i].Category != nil {
+			categories = append(categories, products[i].Category)
+		}
+	}
+
+	var translations []models.ProductTranslation
+	if err := db.Where("product_id IN ? AND locale = ?", productIDs, locale).Find(&translations).Error; err != nil {
+		return err
+	}
+
+	byProduct := make(map[int64]models.ProductTranslation, len(translations))
+	for _, translation := range translations {
+		byProduct[translation.ProductID] = translation
+	}
+
+	for i := range products {
+		if translation, ok := byProduct[products[i].ID]; ok {
+			products[i].Name = translation.Name
+			products[i].Description = translation.Description
+		}
+	}
+
+	return LocalizeCategories(categories, locale, db)
+}
+
+// LocalizeCategories is LocalizeProducts for categories and their parents.
+func LocalizeCategories(categories []*models.ProductCategory, locale string, db *gorm.DB) error {
+	if locale == i18n.Default {
+		return nil
+	}
+
+	var all []*models.ProductCategory
+	var categoryIDs []int64
+	for _, category := range categories {
+		for ; category != nil; category = category.Parent {
+			all = append(all, category)
+			categoryIDs = append(categoryIDs, category.ID)
+		}
+	}
+
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	var translations []models.ProductCategoryTranslation
+	if err := db.Where("category_id IN ? AND locale = ?", categoryIDs, locale).Find(&translations).Error; err != nil {
+		return err
+	}
+
+	byCategory := make(map[int64]models.ProductCategoryTranslation, len(translations))
+	for _, translation := range translations {
+		byCategory[translation.CategoryID] = translation
+	}
+
+	for _, category := range all {
+		if translation, ok := byCategory[category.ID]; ok {
+			category.Name = translation.Name
+			category.Description = translation.Description
+		}
+	}
+
+	return nil
+}
@@ -3,7 +3,7 @@ package repositories
 import (
 	"errors"
 	"keylab/database/models"
-	"log"
+	"log/slog"
 
 	"gorm.io/gorm"
 )
@@ -28,7 +28,7 @@ func FindUserByID(id int64, db *gorm.DB) (models.User, error) {
 	err := db.Preload("Role").Where("id = ?", id).First(&user).Error
 
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error fetching user by ID: %v", err)
+		slog.ErrorContext(db.Statement.Context, "Error fetching user by ID", "error", err)
 	}
 
 	return user, err
@@ -1,22 +1,38 @@
 package routes
 
 import (
+	"keylab/background"
 	"keylab/handlers"
 	"keylab/middleware"
+	"keylab/openapi"
+	"keylab/repositories"
 
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.DB) {
+func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.DB, workers *background.Group) {
 	h := &handlers.Handlers{
 		DB:           db,
 		SessionStore: sessionStore,
+		Workers:      workers,
+		Tax:          repositories.NewTaxRateTable(db),
 	}
 
+	// Liveness and readiness probes
+	e.GET("/healthz", h.Healthz)
+	e.GET("/readyz", h.Readyz)
+
+	// API description and documentation page
+	e.GET("/openapi.json", openapi.Handler)
+	e.GET("/docs", openapi.DocsHandler)
+
+	e.Use(middleware.CSRFMiddleware(sessionStore))
+
 	// Auth related routes
 	authGroup := e.Group("/auth")
+	authGroup.GET("/csrf", h.GetCSRFToken)
 	authGroup.POST("/register", h.Register)
 	authGroup.POST("/login", h.Login)
 	authGroup.POST("/logout", h.Logout)
@@ -30,25 +46,37 @@ func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.D
 	categoryGroup.GET("", h.GetCategories)
 	categoryGroup.GET("/:slug", h.GetCategoryBySlug)
 
-	// // TODO: Add admin middleware to the following routes
-	categoryGroup.POST("", h.CreateCategory)
-	categoryGroup.DELETE("/:slug", h.DeleteCategory)
-	categoryGroup.PUT("/:slug", h.UpdateCategory)
+	categoryGroup.POST("", h.CreateCategory, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "categories:create"))
+	categoryGroup.DELETE("/:slug", h.DeleteCategory, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "categories:delete"))
+	categoryGroup.PUT("/:slug", h.UpdateCategory, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "categories:update"))
+	categoryGroup.GET("/:slug/translations", h.GetCategoryTranslations, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "categories:update"))
+	categoryGroup.PUT("/:slug/translations/:locale", h.SaveCategoryTranslation, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "categories:update"))
+	categoryGroup.DELETE("/:slug/translations/:locale", h.DeleteCategoryTranslation, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "categories:update"))
 
 	// // Product related routes
 	productGroup := e.Group("/products")
 	productGroup.GET("", h.ListProducts)
 	productGroup.GET("/:slug", h.GetProductBySlug)
+	productGroup.GET("/:slug/recommendations", h.GetProductRecommendations)
 	productGroup.GET("/category/:id", h.GetProductsByCategory)
 	productGroup.GET("/search/:query", h.SearchProducts)
 	productGroup.GET("/image/:path", h.GetProductImage)
 
-	// // TODO: Add admin middleware to the following routes
-	productGroup.POST("", h.CreateProduct)
-	productGroup.DELETE("/:id", h.DeleteProduct)
-	productGroup.PUT("/:id", h.UpdateProduct)
-	productGroup.POST("/:slug/image", h.UploadProductImages)
-	productGroup.DELETE("/:slug/image/:id", h.DeleteProductImage)
+	productGroup.POST("", h.CreateProduct, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:create"))
+	productGroup.DELETE("/:id", h.DeleteProduct, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:delete"))
+	productGroup.PUT("/:id", h.UpdateProduct, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+	productGroup.POST("/:slug/image", h.UploadProductImages, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:upload_image"))
+	productGroup.DELETE("/:slug/image/:id", h.DeleteProductImage, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:delete_image"))
+	productGroup.GET("/:id/translations", h.GetProductTranslations, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+	productGroup.PUT("/:id/translations/:locale", h.SaveProductTranslation, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+	productGroup.DELETE("/:id/translations/:locale", h.DeleteProductTranslation, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+
+	productGroup.GET("/:id/prices", h.GetProductPrices, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+	productGroup.PUT("/:id/prices/:currency", h.SaveProductPrice, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+	productGroup.DELETE("/:id/prices/:currency", h.DeleteProductPrice, middleware.AuthMiddleware(sessionStore, db), middleware.PermissionMiddleware(db, "products:update"))
+
+	// Currencies prices can be shown in, chosen with ?currency= or the X-Currency header
+	e.GET("/currencies", h.GetCurrencies)
 
 	productReviewGroup := e.Group("/products/:product_slug/reviews")
 	productReviewGroup.GET("", h.GetReviewsByProduct)
@@ -61,7 +89,20 @@ func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.D
 	productReviewGroup.PUT("/:id", h.UpdateReview, middleware.AuthMiddleware(sessionStore, db))
 	productReviewGroup.DELETE("/:id", h.DeleteReview, middleware.AuthMiddleware(sessionStore, db))
 	productReviewGroup.GET("/user", h.GetUserReview, middleware.AuthMiddleware(sessionStore, db))
+	productReviewGroup.POST("/:id/report", h.ReportReview, middleware.AuthMiddleware(sessionStore, db))
+	productReviewGroup.PUT("/:id/vote", h.VoteReview, middleware.AuthMiddleware(sessionStore, db))
+	productReviewGroup.DELETE("/:id/vote", h.DeleteReviewVote, middleware.AuthMiddleware(sessionStore, db))
+	productReviewGroup.DELETE("/:id/photos/:photo_id", h.DeleteReviewPhoto, middleware.AuthMiddleware(sessionStore, db))
 	e.GET("/reviews/recent", h.FetchRecentViews)
+	e.GET("/reviews/photos/:filename", h.GetReviewPhoto)
+
+	productQuestionGroup := e.Group("/products/:product_slug/questions")
+	productQuestionGroup.GET("", h.GetProductQuestions)
+	productQuestionGroup.GET("/:id", h.GetProductQuestion)
+	productQuestionGroup.POST("", h.AskQuestion, middleware.AuthMiddleware(sessionStore, db))
+	productQuestionGroup.POST("/:id/answers", h.AnswerQuestion, middleware.AuthMiddleware(sessionStore, db))
+	productQuestionGroup.PUT("/:id/answers/:answer_id/vote", h.UpvoteAnswer, middleware.AuthMiddleware(sessionStore, db))
+	productQuestionGroup.DELETE("/:id/answers/:answer_id/vote", h.DeleteAnswerVote, middleware.AuthMiddleware(sessionStore, db))
 
 	// // Cart related routes
 	cartGroup := e.Group("/cart", middleware.AuthMiddleware(sessionStore, db))
@@ -69,6 +110,7 @@ func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.D
 	cartGroup.POST("", h.AddCartItem)
 	cartGroup.PUT("/:id", h.UpdateCartItemQuantity)
 	cartGroup.DELETE("/:id", h.DeleteCartItem)
+	cartGroup.GET("/shipping-options", h.GetShippingOptions)
 	cartGroup.POST("/checkout", h.CheckoutCart)
 
 	//User related routes
@@ -80,24 +122,26 @@ func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.D
 
 	// // Orders related routes
 	e.GET("/user/orders/:id", h.GetUserOrderDetails, middleware.AuthMiddleware(sessionStore, db))
+	e.GET("/user/orders/:id/invoice", h.GetUserOrderInvoice, middleware.AuthMiddleware(sessionStore, db))
 
 	e.POST("/contact", h.ContactUs)
 
-	// NEEDS ADMIN MIDDLEWARE
 	adminGroup := e.Group("/admin", middleware.AuthMiddleware(sessionStore, db))
 
-	adminUserGroup := adminGroup.Group("/users")
+	adminUserGroup := adminGroup.Group("/users", middleware.PermissionMiddleware(db, "admin:dashboard"))
 	adminUserGroup.GET("", h.GetAllUsers)
 	adminUserGroup.PUT("/:id", h.UpdateUserByAdmin)
 	adminUserGroup.DELETE("/:id", h.DeleteUserByAdmin)
 
-	adminOrdersGroup := adminGroup.Group("/orders")
+	adminOrdersGroup := adminGroup.Group("/orders", middleware.PermissionMiddleware(db, "admin:dashboard"))
 	adminOrdersGroup.GET("", h.GetAllOrders)
 	adminOrdersGroup.GET("/:id", h.GetOrderDetails)
 	adminOrdersGroup.GET("/user/:id", h.GetUserOrders)
 	adminOrdersGroup.PUT("/:id/status", h.UpdateOrderStatus)
+	adminOrdersGroup.GET("/:id/invoice", h.GetOrderInvoice)
+	adminOrdersGroup.GET("/:id/packing-slip", h.GetOrderPackingSlip)
 
-	adminRolesGroup := adminGroup.Group("/roles")
+	adminRolesGroup := adminGroup.Group("/roles", middleware.PermissionMiddleware(db, "admin:dashboard"))
 	adminRolesGroup.GET("", h.GetAllRoles)
 	adminRolesGroup.GET("/:id", h.GetRoleById)
 	adminRolesGroup.POST("", h.CreateRole)
@@ -107,6 +151,63 @@ func RegisterRoutes(e *echo.Echo, sessionStore *sessions.CookieStore, db *gorm.D
 	adminRolesGroup.DELETE("/:roleId/permissions/:permissionId", h.RemovePermissionFromRole)
 
 	//Permissions related routes
-	adminPermissionsGroup := adminGroup.Group("/permissions")
+	adminPermissionsGroup := adminGroup.Group("/permissions", middleware.PermissionMiddleware(db, "admin:dashboard"))
 	adminPermissionsGroup.GET("", h.GetAllPermissions)
+
+	adminGroup.GET("/audit", h.GetAuditLogs, middleware.PermissionMiddleware(db, "admin:dashboard"))
+
+	adminExchangeRatesGroup := adminGroup.Group("/exchange-rates", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminExchangeRatesGroup.GET("", h.GetExchangeRates)
+	adminExchangeRatesGroup.PUT("/:currency", h.SaveExchangeRate)
+	adminExchangeRatesGroup.DELETE("/:currency", h.DeleteExchangeRate)
+
+	adminTaxGroup := adminGroup.Group("/tax", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminTaxGroup.GET("/classes", h.GetTaxClasses)
+	adminTaxGroup.POST("/classes", h.CreateTaxClass)
+	adminTaxGroup.PUT("/classes/:id", h.UpdateTaxClass)
+	adminTaxGroup.DELETE("/classes/:id", h.DeleteTaxClass)
+	adminTaxGroup.GET("/rates", h.GetTaxRates)
+	adminTaxGroup.POST("/rates", h.CreateTaxRate)
+	adminTaxGroup.PUT("/rates/:id", h.UpdateTaxRate)
+	adminTaxGroup.DELETE("/rates/:id", h.DeleteTaxRate)
+
+	adminShippingGroup := adminGroup.Group("/shipping", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminShippingGroup.GET("/zones", h.GetShippingZones)
+	adminShippingGroup.POST("/zones", h.CreateShippingZone)
+	adminShippingGroup.PUT("/zones/:id", h.UpdateShippingZone)
+	adminShippingGroup.DELETE("/zones/:id", h.DeleteShippingZone)
+	adminShippingGroup.POST("/methods", h.CreateShippingMethod)
+	adminShippingGroup.PUT("/methods/:id", h.UpdateShippingMethod)
+	adminShippingGroup.DELETE("/methods/:id", h.DeleteShippingMethod)
+
+	adminProductsGroup := adminGroup.Group("/products", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminProductsGroup.GET("/export.csv", h.ExportProducts)
+	adminProductsGroup.POST("/import", h.ImportProducts)
+	adminProductsGroup.GET("/import/:id", h.GetProductImport)
+
+	adminAnalyticsGroup := adminGroup.Group("/analytics", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminAnalyticsGroup.GET("/sales", h.GetSales)
+	adminAnalyticsGroup.GET("/products", h.GetTopProducts)
+	adminAnalyticsGroup.GET("/categories", h.GetTopCategories)
+	adminAnalyticsGroup.GET("/customers", h.GetCustomerAnalytics)
+	adminAnalyticsGroup.GET("/statuses", h.GetOrderStatusAnalytics)
+
+	adminInventoryGroup := adminGroup.Group("/inventory", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminInventoryGroup.GET("/low-stock", h.GetLowStockProducts)
+
+	adminReviewsGroup := adminGroup.Group("/reviews", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminReviewsGroup.GET("", h.GetModerationReviews)
+	adminReviewsGroup.GET("/:id", h.GetModerationReview)
+	adminReviewsGroup.PUT("/:id/status", h.ModerateReview)
+
+	adminQuestionsGroup := adminGroup.Group("/questions", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminQuestionsGroup.GET("", h.GetModerationQuestions)
+	adminQuestionsGroup.GET("/:id", h.GetModerationQuestion)
+	adminQuestionsGroup.PUT("/:id/status", h.ModerateQuestion)
+	adminQuestionsGroup.DELETE("/:id", h.DeleteQuestion)
+
+	adminAnswersGroup := adminGroup.Group("/answers", middleware.PermissionMiddleware(db, "admin:dashboard"))
+	adminAnswersGroup.PUT("/:id/status", h.ModerateAnswer)
+	adminAnswersGroup.PUT("/:id/official", h.MarkAnswerOfficial)
+	adminAnswersGroup.DELETE("/:id", h.DeleteAnswer)
 }
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"keylab/background"
+	"keylab/openapi"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// registeredOperations lists the routes added by RegisterRoutes in OpenAPI syntax. No database is
+// needed, handlers and middleware only use it once a request comes in.
+func registeredOperations() []string {
+	e := echo.New()
+	RegisterRoutes(e, sessions.NewCookieStore([]byte("0123456789abcdef0123456789abcdef")), nil, background.NewGroup())
+
+	seen := map[string]bool{}
+	var operations []string
+	for _, route := range e.Routes() {
+		// Groups with middleware register catch-all not found routes
+		if route.Method == echo.RouteNotFound || strings.HasSuffix(route.Path, "*") {
+			continue
+		}
+
+		operation := route.Method + " " + openapi.Path(route.Path)
+		if !seen[operation] {
+			seen[operation] = true
+			operations = append(operations, operation)
+		}
+	}
+	sort.Strings(operations)
+
+	return operations
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	registered := registeredOperations()
+	documented := openapi.Build().Operations()
+
+	for _, operation := range registered {
+		assert.Contains(t, documented, operation, "route is missing from openapi/endpoints.go")
+	}
+	for _, operation := range documented {
+		assert.Contains(t, registered, operation, "documented route is not registered")
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	e := echo.New()
+	RegisterRoutes(e, sessions.NewCookieStore([]byte("0123456789abcdef0123456789abcdef")), nil, background.NewGroup())
+
+	for _, path := range []string{"/openapi.json", "/docs"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	db "keylab/database"
+	"keylab/database/seeders"
+	"os"
+)
+
+// seed implements "seed": --all replaces the seeded tables with the full development data set,
+// otherwise only the named seeders run.
+func seed(args []string) int {
+	fs := newFlagSet("seed", "--all | <seeder>... [flags]")
+	all := fs.Bool("all", false, "clean the seeded tables and run every seeder")
+	list := fs.Bool("list", false, "list the available seeders")
+
+	if _, err := initialize(fs, args); err != nil {
+		return fail(err)
+	}
+
+	if *list {
+		for _, seeder := range seeders.All {
+			fmt.Println(seeder.Name)
+		}
+		return 0
+	}
+
+	if !*all && fs.NArg() == 0 {
+		return usageError(fs, "choose seeders by name or pass --all")
+	}
+	if *all && fs.NArg() > 0 {
+		return usageError(fs, "--all cannot be combined with seeder names")
+	}
+
+	database := db.InitDB()
+
+	var err error
+	if *all {
+		err = seeders.SeedAll(database)
+	} else {
+		err = seeders.SeedNamed(database, fs.Args()...)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Fprintln(os.Stderr, "Database seeded successfully")
+	return 0
+}
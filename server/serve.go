@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"keylab/apperr"
+	"keylab/background"
+	"keylab/config"
+	db "keylab/database"
+	"keylab/handlers"
+	"keylab/helpers"
+	"keylab/logging"
+	"keylab/metrics"
+	keylabMiddleware "keylab/middleware"
+	"keylab/repositories"
+	"keylab/routes"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// serve implements "serve": it runs the API until SIGINT or SIGTERM, then shuts down gracefully.
+func serve(args []string) int {
+	fs := newFlagSet("serve", "[flags]")
+	migrateFirst := fs.Bool("migrate", false, "apply pending migrations before serving")
+
+	config, err := config.InitializeFlags(fs, args)
+	if err != nil {
+		return fail(err)
+	}
+
+	logger := logging.Setup(os.Stdout, config.LOG_LEVEL, config.LOG_FORMAT)
+	logger.Info("Starting KeyLab server...")
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HTTPErrorHandler = apperr.ErrorHandler
+	e.Use(keylabMiddleware.RequestIDMiddleware())
+	e.Use(keylabMiddleware.RequestLoggerMiddleware(logger))
+	e.Use(metrics.Middleware())
+	e.Use(keylabMiddleware.LocaleMiddleware())
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     []string{config.CLIENT_URL},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, handlers.CSRFHeader},
+		ExposeHeaders:    []string{echo.HeaderXRequestID},
+		AllowCredentials: true,
+	}))
+
+	session := sessions.NewCookieStore([]byte(config.SESSIONS_KEY), []byte(config.HASH_KEY))
+	session.Options.HttpOnly = true
+	session.Options.Secure = true
+
+	database := db.InitDB()
+	if *migrateFirst {
+		if err := db.MigrateUp(database); err != nil {
+			return fail(err)
+		}
+	} else if state, err := db.GetMigrationState(context.Background(), database); err == nil && state.Pending() > 0 {
+		logger.Warn("Database has pending migrations, run keylab migrate up", "current", state.Current, "latest", state.Latest)
+	}
+
+	workers := background.NewGroup()
+	routes.RegisterRoutes(e, session, database, workers)
+
+	workers.Every("recommendations", config.RECOMMENDATIONS_INTERVAL, func(ctx context.Context) {
+		since := time.Now().Add(-config.RECOMMENDATIONS_WINDOW)
+		if err := repositories.RefreshRecommendations(since, database.WithContext(ctx)); err != nil {
+			logger.Error("Error refreshing product recommendations", "error", err)
+		}
+	})
+
+	if sqlDB, err := database.DB(); err == nil {
+		if err := metrics.RegisterDB(sqlDB, config.MARIADB_DATABASE); err != nil {
+			logger.Error("Error registering database metrics", "error", err)
+		}
+	}
+
+	// Metrics are served on their own listener when METRICS_ADDR is set, otherwise on the API
+	// listener behind METRICS_TOKEN. Without either they are not exposed at all.
+	var metricsServer *http.Server
+	if config.METRICS_ADDR != "" {
+		metricsServer = &http.Server{Addr: config.METRICS_ADDR, Handler: metrics.Handler(config.METRICS_TOKEN)}
+		go func() {
+			logger.Info("Metrics server started", "addr", config.METRICS_ADDR)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics server stopped", "error", err)
+			}
+		}()
+	} else if config.METRICS_TOKEN != "" {
+		e.GET("/metrics", echo.WrapHandler(metrics.Handler(config.METRICS_TOKEN)))
+	}
+
+	address, err := helpers.ListenAddress(config.SERVER_URL)
+	if err != nil {
+		return fail(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		logger.Info("Server started", "url", config.SERVER_URL, "addr", address)
+		if err := e.Start(address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Server stopped unexpectedly", "error", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+
+	// Drain in-flight requests first, then give background workers what is left of the timeout
+	logger.Info("Shutting down", "timeout", config.SHUTDOWN_TIMEOUT)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Error draining HTTP requests", "error", err)
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error stopping metrics server", "error", err)
+		}
+	}
+	if err := workers.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Background workers did not finish in time", "error", err)
+	}
+	if sqlDB, err := database.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	logger.Info("Server stopped")
+	return 0
+}
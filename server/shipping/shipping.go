@@ -0,0 +1,126 @@
+// Package shipping quotes the shipping methods of the zone an address is in.
+package shipping
+
+import (
+	"keylab/money"
+	"sort"
+	"strings"
+)
+
+// MethodType is the kind of delivery a method offers.
+type MethodType string
+
+const (
+	Standard MethodType = "standard"
+	Express  MethodType = "express"
+	Pickup   MethodType = "pickup"
+)
+
+// RateType is how a method's cost is worked out.
+type RateType string
+
+const (
+	// Flat methods cost Price.
+	Flat RateType = "flat"
+	// Weight methods cost Price plus PerKg for every started kilogram.
+	Weight RateType = "weight"
+	// FreeOver methods cost Price, or nothing once the cart's subtotal reaches FreeOver.
+	FreeOver RateType = "free_over"
+)
+
+// Zone is a country, or the part of it whose postal codes start with PostalPrefix.
+type Zone struct {
+	ID           int64
+	Country      string
+	PostalPrefix string
+}
+
+// Matches reports whether the address is in the zone. Countries compare case-insensitively and
+// postal codes without spaces or case.
+func (z Zone) Matches(country string, postalCode string) bool {
+	if !strings.EqualFold(strings.TrimSpace(z.Country), strings.TrimSpace(country)) {
+		return false
+	}
+
+	return strings.HasPrefix(NormalizePostalCode(postalCode), NormalizePostalCode(z.PostalPrefix))
+}
+
+// MatchZone returns the zone the address is in. When several match, the one with the longest
+// postal prefix wins, so a zone for "BT" overrides one for the whole country.
+func MatchZone(zones []Zone, country string, postalCode string) (Zone, bool) {
+	var match Zone
+	found := false
+	for _, zone := range zones {
+		if !zone.Matches(country, postalCode) {
+			continue
+		}
+		if !found || len(NormalizePostalCode(zone.PostalPrefix)) > len(NormalizePostalCode(match.PostalPrefix)) {
+			match = zone
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+// NormalizePostalCode upper-cases the postal code and drops its spaces.
+func NormalizePostalCode(postalCode string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(postalCode), ""))
+}
+
+// Cart is what a quote depends on. Weight is in grams.
+type Cart struct {
+	Subtotal money.Money
+	Weight   int
+}
+
+type Method struct {
+	ID       int64
+	Name     string
+	Type     MethodType
+	RateType RateType
+	Price    money.Money
+	PerKg    money.Money
+	FreeOver money.Money
+}
+
+// Quote returns what shipping the cart with the method costs.
+func (m Method) Quote(cart Cart) money.Money {
+	switch m.RateType {
+	case Weight:
+		kilograms := (cart.Weight + 999) / 1000
+		return m.Price.Add(m.PerKg.Mul(int64(kilograms)))
+	case FreeOver:
+		if cart.Subtotal.Cmp(m.FreeOver) >= 0 {
+			return money.Money{}
+		}
+		return m.Price
+	default:
+		return m.Price
+	}
+}
+
+// Option is a method the customer can choose, with its cost for their cart.
+type Option struct {
+	MethodID int64       `json:"shipping_method_id"`
+	Name     string      `json:"name"`
+	Type     MethodType  `json:"type"`
+	Cost     money.Money `json:"cost"`
+}
+
+// Options quotes every method for the cart, cheapest first.
+func Options(methods []Method, cart Cart) []Option {
+	options := make([]Option, len(methods))
+	for i, method := range methods {
+		options[i] = Option{MethodID: method.ID, Name: method.Name, Type: method.Type, Cost: method.Quote(cart)}
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		if c := options[i].Cost.Cmp(options[j].Cost); c != 0 {
+			return c < 0
+		}
+		return options[i].Name < options[j].Name
+	})
+
+	return options
+}
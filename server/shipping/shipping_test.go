@@ -0,0 +1,56 @@
+package shipping
+
+import (
+	"keylab/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchZone(t *testing.T) {
+	zones := []Zone{
+		{ID: 1, Country: "United Kingdom"},
+		{ID: 2, Country: "United Kingdom", PostalPrefix: "BT"},
+		{ID: 3, Country: "United Kingdom", PostalPrefix: "HS 1"},
+		{ID: 4, Country: "Ireland"},
+	}
+
+	zone, ok := MatchZone(zones, "united kingdom", "SW1A 1AA")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), zone.ID)
+
+	zone, _ = MatchZone(zones, "United Kingdom", "bt1 5gs")
+	assert.Equal(t, int64(2), zone.ID)
+
+	zone, _ = MatchZone(zones, "United Kingdom", "HS1 2AB")
+	assert.Equal(t, int64(3), zone.ID)
+
+	_, ok = MatchZone(zones, "France", "75001")
+	assert.False(t, ok)
+}
+
+func TestQuote(t *testing.T) {
+	cart := Cart{Subtotal: money.MustParse("49.99"), Weight: 2300}
+
+	flat := Method{RateType: Flat, Price: money.MustParse("3.95")}
+	assert.Equal(t, "3.95", flat.Quote(cart).String())
+
+	weight := Method{RateType: Weight, Price: money.MustParse("2.00"), PerKg: money.MustParse("1.50")}
+	assert.Equal(t, "6.50", weight.Quote(cart).String(), "three started kilograms")
+	assert.Equal(t, "2.00", weight.Quote(Cart{}).String())
+
+	freeOver := Method{RateType: FreeOver, Price: money.MustParse("4.99"), FreeOver: money.MustParse("50.00")}
+	assert.Equal(t, "4.99", freeOver.Quote(cart).String())
+	assert.True(t, freeOver.Quote(Cart{Subtotal: money.MustParse("50.00")}).IsZero())
+}
+
+func TestOptions(t *testing.T) {
+	options := Options([]Method{
+		{ID: 1, Name: "Express", Type: Express, RateType: Flat, Price: money.MustParse("9.99")},
+		{ID: 2, Name: "Standard", Type: Standard, RateType: Flat, Price: money.MustParse("3.95")},
+		{ID: 3, Name: "Pickup", Type: Pickup, RateType: Flat},
+	}, Cart{})
+
+	assert.Equal(t, []int64{3, 2, 1}, []int64{options[0].MethodID, options[1].MethodID, options[2].MethodID})
+	assert.Equal(t, "9.99", options[2].Cost.String())
+}
@@ -0,0 +1,168 @@
+// Package tax works out the tax on the lines of an order.
+//
+// The shop's own rate table is one Calculator. Anything that can answer the same question, such
+// as an external tax service, can replace it without checkout changing.
+package tax
+
+import (
+	"context"
+	"keylab/money"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Mode says whether prices already include tax.
+type Mode string
+
+const (
+	// Inclusive prices include tax, which is worked out of them. The customer pays the prices.
+	Inclusive Mode = "inclusive"
+	// Exclusive prices are net, tax is added on top of them.
+	Exclusive Mode = "exclusive"
+)
+
+// Address is where an order is taxed, its shipping address.
+type Address struct {
+	Country string
+	County  string
+}
+
+// Line is one ordered product.
+type Line struct {
+	ProductID int64
+	// TaxClassID is the tax class of the product's category, 0 for the standard class.
+	TaxClassID int64
+	// Amount is the price of the line, the unit price times the quantity.
+	Amount money.Money
+}
+
+type Request struct {
+	Address Address
+	Mode    Mode
+	Lines   []Line
+}
+
+// LineTax is the tax on one line. Rate is a percentage, e.g. 20 or 8.875.
+type LineTax struct {
+	Name   string
+	Rate   money.Rate
+	Amount money.Money
+}
+
+// Summary adds up the lines taxed at the same rate. Taxable is the net amount the rate was
+// charged on.
+type Summary struct {
+	Name    string      `json:"name"`
+	Rate    money.Rate  `json:"rate"`
+	Taxable money.Money `json:"taxable"`
+	Amount  money.Money `json:"amount"`
+}
+
+type Result struct {
+	Mode Mode
+	// Lines holds the tax of each request line, in the same order.
+	Lines []LineTax
+	// Taxes breaks Total down by rate.
+	Taxes []Summary
+	Total money.Money
+}
+
+// Calculator works out the tax on an order.
+type Calculator interface {
+	Calculate(ctx context.Context, request Request) (Result, error)
+}
+
+// Rate is a percentage charged on a tax class in a country, or only in one county of it when
+// County is set.
+type Rate struct {
+	TaxClassID int64
+	Country    string
+	County     string
+	Name       string
+	Rate       money.Rate
+}
+
+// Matches reports whether the rate applies at address. Names are compared case insensitively,
+// since addresses are typed in by customers.
+func (r Rate) Matches(address Address) bool {
+	if !strings.EqualFold(strings.TrimSpace(r.Country), strings.TrimSpace(address.Country)) {
+		return false
+	}
+
+	return r.County == "" || strings.EqualFold(strings.TrimSpace(r.County), strings.TrimSpace(address.County))
+}
+
+var hundred = big.NewRat(100, 1)
+
+// Compute taxes each line at the rate for its class at the request's address, preferring a county
+// rate over the country's. Lines without a rate are not taxed. Tax is rounded per line.
+func Compute(request Request, rates []Rate) Result {
+	result := Result{Mode: request.Mode, Lines: make([]LineTax, len(request.Lines))}
+
+	best := map[int64]Rate{}
+	for _, rate := range rates {
+		if !rate.Matches(request.Address) {
+			continue
+		}
+		if current, ok := best[rate.TaxClassID]; !ok || (current.County == "" && rate.County != "") {
+			best[rate.TaxClassID] = rate
+		}
+	}
+
+	type key struct {
+		name    string
+		percent int64
+	}
+	summaries := map[key]*Summary{}
+
+	for i, line := range request.Lines {
+		rate, ok := best[line.TaxClassID]
+		if !ok {
+			continue
+		}
+
+		percent := rate.Rate.Rat()
+		var amount, taxable money.Money
+		if request.Mode == Inclusive {
+			// The price is net * (100 + percent) / 100, so the tax in it is price * percent / (100 + percent).
+			amount = line.Amount.MulRat(new(big.Rat).Quo(percent, new(big.Rat).Add(hundred, percent)))
+			taxable = line.Amount.Sub(amount)
+		} else {
+			amount = line.Amount.MulRat(new(big.Rat).Quo(percent, hundred))
+			taxable = line.Amount
+		}
+
+		result.Lines[i] = LineTax{Name: rate.Name, Rate: rate.Rate, Amount: amount}
+		result.Total = result.Total.Add(amount)
+
+		k := key{name: rate.Name, percent: rate.Rate.Scaled()}
+		if summaries[k] == nil {
+			summaries[k] = &Summary{Name: rate.Name, Rate: rate.Rate}
+		}
+		summaries[k].Taxable = summaries[k].Taxable.Add(taxable)
+		summaries[k].Amount = summaries[k].Amount.Add(amount)
+	}
+
+	for _, summary := range summaries {
+		result.Taxes = append(result.Taxes, *summary)
+	}
+	sort.Slice(result.Taxes, func(i, j int) bool {
+		if result.Taxes[i].Rate != result.Taxes[j].Rate {
+			return result.Taxes[i].Rate.Scaled() > result.Taxes[j].Rate.Scaled()
+		}
+		return result.Taxes[i].Name < result.Taxes[j].Name
+	})
+
+	return result
+}
+
+// ParseMode returns the mode named s.
+func ParseMode(s string) (Mode, bool) {
+	switch Mode(s) {
+	case Inclusive, Exclusive:
+		return Mode(s), true
+	default:
+		return "", false
+	}
+}
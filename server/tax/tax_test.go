@@ -0,0 +1,68 @@
+package tax
+
+import (
+	"keylab/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rates = []Rate{
+	{TaxClassID: 1, Country: "United Kingdom", Name: "VAT", Rate: money.MustParseRate("20")},
+	{TaxClassID: 2, Country: "United Kingdom", Name: "VAT", Rate: money.MustParseRate("5")},
+	{TaxClassID: 1, Country: "United States", County: "New York", Name: "Sales tax", Rate: money.MustParseRate("8.875")},
+	{TaxClassID: 1, Country: "United States", Name: "Sales tax", Rate: money.MustParseRate("4")},
+}
+
+func TestComputeExclusive(t *testing.T) {
+	result := Compute(Request{
+		Address: Address{Country: "united kingdom ", County: "Kent"},
+		Mode:    Exclusive,
+		Lines: []Line{
+			{ProductID: 1, TaxClassID: 1, Amount: money.MustParse("19.99")},
+			{ProductID: 2, TaxClassID: 2, Amount: money.MustParse("10.00")},
+			{ProductID: 3, TaxClassID: 3, Amount: money.MustParse("5.00")},
+		},
+	}, rates)
+
+	assert.Equal(t, "4.00", result.Lines[0].Amount.String()) // 3.998
+	assert.Equal(t, "0.50", result.Lines[1].Amount.String())
+	assert.True(t, result.Lines[2].Amount.IsZero(), "no rate for the class")
+	assert.Equal(t, "4.50", result.Total.String())
+
+	assert.Equal(t, []Summary{
+		{Name: "VAT", Rate: money.MustParseRate("20"), Taxable: money.MustParse("19.99"), Amount: money.MustParse("4.00")},
+		{Name: "VAT", Rate: money.MustParseRate("5"), Taxable: money.MustParse("10.00"), Amount: money.MustParse("0.50")},
+	}, result.Taxes)
+}
+
+func TestComputeInclusive(t *testing.T) {
+	result := Compute(Request{
+		Address: Address{Country: "United Kingdom"},
+		Mode:    Inclusive,
+		Lines: []Line{
+			{TaxClassID: 1, Amount: money.MustParse("12.00")},
+			{TaxClassID: 1, Amount: money.MustParse("24.00")},
+		},
+	}, rates)
+
+	assert.Equal(t, "2.00", result.Lines[0].Amount.String())
+	assert.Equal(t, "6.00", result.Total.String())
+	assert.Equal(t, "30.00", result.Taxes[0].Taxable.String())
+}
+
+func TestCountyRateWins(t *testing.T) {
+	newYork := Compute(Request{
+		Address: Address{Country: "United States", County: "new york"},
+		Mode:    Exclusive,
+		Lines:   []Line{{TaxClassID: 1, Amount: money.MustParse("100.00")}},
+	}, rates)
+	assert.Equal(t, "8.88", newYork.Total.String())
+
+	elsewhere := Compute(Request{
+		Address: Address{Country: "United States", County: "Ohio"},
+		Mode:    Exclusive,
+		Lines:   []Line{{TaxClassID: 1, Amount: money.MustParse("100.00")}},
+	}, rates)
+	assert.Equal(t, "4.00", elsewhere.Total.String())
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	db "keylab/database"
+	"keylab/database/models"
+	"keylab/database/seeders"
+	"keylab/repositories"
+	"keylab/utils"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+const adminRoleName = "admin"
+
+// createAdmin implements "create-admin": it creates a user with the admin role, or gives the role
+// to the user if the email is already registered.
+func createAdmin(args []string) int {
+	fs := newFlagSet("create-admin", "--email <email> [--password <password>] [flags]")
+	email := fs.String("email", "", "email address of the admin")
+	password := fs.String("password", os.Getenv("KEYLAB_ADMIN_PASSWORD"), "password for a new user (env KEYLAB_ADMIN_PASSWORD)")
+	forename := fs.String("forename", "Admin", "forename for a new user")
+	surname := fs.String("surname", "User", "surname for a new user")
+
+	if _, err := initialize(fs, args); err != nil {
+		return fail(err)
+	}
+
+	if *email == "" {
+		return usageError(fs, "--email is required")
+	}
+
+	database := db.InitDB()
+
+	// The admin role and its permissions are created on demand, so this works on an empty database
+	if err := seeders.SeedRoles(database); err != nil {
+		return fail(fmt.Errorf("could not create roles: %w", err))
+	}
+
+	var role models.Role
+	if err := database.Where("name = ?", adminRoleName).First(&role).Error; err != nil {
+		return fail(fmt.Errorf("could not find admin role: %w", err))
+	}
+
+	existing, err := repositories.FindUserByEmail(*email, database)
+	if err != nil {
+		return fail(err)
+	}
+
+	if existing != nil {
+		if err := setRole(database, existing, &role); err != nil {
+			return fail(err)
+		}
+
+		fmt.Printf("User %s is now an admin\n", existing.Email)
+		return 0
+	}
+
+	user := models.User{Forename: *forename, Surname: *surname, Email: *email, Password: *password, RoleID: role.ID}
+	if err := user.Validate(); err != nil {
+		return fail(err)
+	}
+
+	matched, err := utils.ValidatePassword(user.Password)
+	if err != nil {
+		return fail(err)
+	}
+	if !matched {
+		return fail(errors.New("password must contain a lowercase letter, an uppercase letter and a digit"))
+	}
+
+	if user.Password, err = utils.HashPassword(user.Password); err != nil {
+		return fail(err)
+	}
+
+	if err := database.Create(&user).Error; err != nil {
+		return fail(fmt.Errorf("could not create user: %w", err))
+	}
+
+	recordAudit(database, "user.create", user.ID, nil, user)
+
+	fmt.Printf("Created admin user %s\n", user.Email)
+	return 0
+}
+
+// usersCommand implements "users set-role <email> <role|none>".
+func usersCommand(args []string) int {
+	fs := newFlagSet("users", "set-role <email> <role|none> [flags]")
+	if _, err := initialize(fs, args); err != nil {
+		return fail(err)
+	}
+
+	if fs.Arg(0) != "set-role" {
+		return usageError(fs, "unknown users subcommand %q", fs.Arg(0))
+	}
+	if fs.NArg() != 3 {
+		return usageError(fs, "set-role needs an email and a role")
+	}
+
+	database := db.InitDB()
+
+	user, err := repositories.FindUserByEmail(fs.Arg(1), database)
+	if err != nil {
+		return fail(err)
+	}
+	if user == nil {
+		return fail(fmt.Errorf("no user with email %s", fs.Arg(1)))
+	}
+
+	var role *models.Role
+	if fs.Arg(2) != "none" {
+		role = &models.Role{}
+		if err := database.Where("name = ?", fs.Arg(2)).First(role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fail(fmt.Errorf("no role named %s", fs.Arg(2)))
+			}
+			return fail(err)
+		}
+	}
+
+	if err := setRole(database, user, role); err != nil {
+		return fail(err)
+	}
+
+	fmt.Printf("Updated role of %s to %s\n", user.Email, fs.Arg(2))
+	return 0
+}
+
+// setRole assigns role to the user, or removes their role when role is nil.
+func setRole(database *gorm.DB, user *models.User, role *models.Role) error {
+	before := map[string]interface{}{"roleId": user.RoleID}
+
+	var roleID interface{}
+	after := map[string]interface{}{"roleId": nil}
+	if role != nil {
+		roleID = role.ID
+		after["roleId"] = role.ID
+	}
+
+	if err := database.Model(user).Update("role_id", roleID).Error; err != nil {
+		return fmt.Errorf("could not update role: %w", err)
+	}
+
+	recordAudit(database, "user.update", user.ID, before, after)
+	return nil
+}
+
+// recordAudit appends a change made from the command line to the audit log. There is no
+// authenticated actor or IP address for these entries.
+func recordAudit(database *gorm.DB, action string, userID int64, before interface{}, after interface{}) {
+	entry := models.AuditLog{Action: action, EntityType: models.AuditEntityUser, EntityID: userID}
+	repositories.RecordAudit(database, entry, before, after)
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Fields that are never copied into a diff, whatever entity they belong to.
+var redactedDiffFields = map[string]bool{
+	"password": true,
+}
+
+// DiffJSON compares the JSON representations of before and after and returns only the top level
+// fields that differ. A nil before (create) or nil after (delete) returns the other side in full.
+func DiffJSON(before interface{}, after interface{}) (json.RawMessage, json.RawMessage, error) {
+	beforeMap, err := toJSONMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterMap, err := toJSONMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeMap != nil && afterMap != nil {
+		changedBefore := map[string]interface{}{}
+		changedAfter := map[string]interface{}{}
+
+		for key, value := range beforeMap {
+			if !reflect.DeepEqual(value, afterMap[key]) {
+				changedBefore[key] = value
+			}
+		}
+
+		for key, value := range afterMap {
+			if !reflect.DeepEqual(value, beforeMap[key]) {
+				changedAfter[key] = value
+			}
+		}
+
+		beforeMap, afterMap = changedBefore, changedAfter
+	}
+
+	oldValues, err := marshalJSONMap(beforeMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newValues, err := marshalJSONMap(afterMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return oldValues, newValues, nil
+}
+
+func toJSONMap(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return nil, err
+	}
+
+	for key := range redactedDiffFields {
+		delete(result, key)
+	}
+
+	return result, nil
+}
+
+func marshalJSONMap(value map[string]interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(value)
+}